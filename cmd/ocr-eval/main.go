@@ -0,0 +1,167 @@
+// Command ocr-eval measures OCR accuracy on a corpus of images with ground truth, so changes to the
+// OCR pipeline (engines, presets, preprocessing) can be compared before and after.
+//
+// Each image needs a ground-truth file next to it: "notice.png" is compared with "notice.gt.txt".
+// Images in a top-level subdirectory are recognized in the language it is named after
+// ("nep/notice.png", "eng+nep/form.jpg"); others in the -lang language.
+//
+//	go run ./cmd/ocr-eval -corpus testdata/ocr -json report.json -markdown report.md
+//	go run ./cmd/ocr-eval -corpus testdata/ocr -baseline report.json   # exits with 1 on a regression
+//
+// The engines are configured like the server, from OCR_ENGINES, OCR_ENGINE, TESSDATA_PREFIX and
+// TESSERACT_CMD, or with the flags below.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/axyut/niyamAPI/internal/config"
+	"github.com/axyut/niyamAPI/internal/service"
+	"github.com/axyut/niyamAPI/internal/types"
+)
+
+func main() {
+	// Settings shared with the server may come from the same .env file.
+	_ = godotenv.Load()
+
+	corpus := flag.String("corpus", "", "Directory of images with .gt.txt ground truth (required)")
+	language := flag.String("lang", types.LangEnglish, "Language of images that are not in a language subdirectory")
+	preset := flag.String("preset", "", "OCR preset to recognize the images with")
+	engines := flag.String("engines", envOr("OCR_ENGINES", "tesseract,tesseract-cli"), "Comma-separated OCR engines to enable")
+	engine := flag.String("engine", os.Getenv("OCR_ENGINE"), "OCR engine to evaluate (default: the first available)")
+	tessdata := flag.String("tessdata", os.Getenv("TESSDATA_PREFIX"), "Directory holding *.traineddata files (default: auto-detect)")
+	tesseractCmd := flag.String("tesseract-cmd", envOr("TESSERACT_CMD", "tesseract"), "tesseract program used by the tesseract-cli engine")
+	timeout := flag.Duration("timeout", 2*time.Minute, "Maximum time to recognize one image")
+	jsonPath := flag.String("json", "", "Write the report as JSON to this file ('-' for standard output)")
+	markdownPath := flag.String("markdown", "", "Write the report as Markdown to this file ('-' for standard output)")
+	baselinePath := flag.String("baseline", "", "JSON report of an earlier run to compare with")
+	tolerance := flag.Float64("tolerance", 0.005, "Increase of an error rate that counts as a regression (0.005 = half a point)")
+	flag.Parse()
+
+	if *corpus == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	// Without any output flag, print the Markdown report.
+	if *jsonPath == "" && *markdownPath == "" {
+		*markdownPath = "-"
+	}
+
+	var baseline *types.OCREvalReport
+	if *baselinePath != "" {
+		data, err := os.ReadFile(*baselinePath)
+		if err != nil {
+			log.Fatalf("FATAL: Failed to read baseline report: %v", err)
+		}
+		baseline = &types.OCREvalReport{}
+		if err := json.Unmarshal(data, baseline); err != nil {
+			log.Fatalf("FATAL: Failed to parse baseline report '%s': %v", *baselinePath, err)
+		}
+	}
+
+	cfg := &config.AppConfig{
+		OCRTimeout:        *timeout,
+		OCRMaxConcurrency: 1,
+		TessdataDir:       *tessdata,
+		OCREngine:         *engine,
+		TesseractCmd:      *tesseractCmd,
+	}
+	for _, name := range strings.Split(*engines, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cfg.OCREngines = append(cfg.OCREngines, name)
+		}
+	}
+	languageService := service.NewLanguageService(cfg.TessdataDir)
+	engineRegistry := service.NewOCREngineRegistry(cfg, languageService.TessdataDir())
+	ocrService := service.NewOCRService(cfg, engineRegistry)
+
+	options, err := service.NewOCRPresetService(os.Getenv("OCR_PRESETS_FILE")).ResolveOptions(*preset, types.OCROptions{Engine: *engine})
+	if err != nil {
+		log.Fatalf("FATAL: Invalid OCR options: %v", err)
+	}
+	// Pin the engine, so the report names the engine that actually ran.
+	ocrEngine, err := engineRegistry.Engine(options.Engine)
+	if err != nil {
+		log.Fatalf("FATAL: No usable OCR engine: %v", err)
+	}
+	options.Engine = ocrEngine.Name()
+
+	cases, err := service.LoadOCREvalCorpus(*corpus, *language)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+	for _, c := range cases {
+		for _, code := range strings.Split(c.Language, "+") {
+			if !languageService.IsSupported(code) {
+				log.Fatalf("FATAL: Language '%s' of '%s' is not installed. Installed: %s.", code, c.Image, strings.Join(languageService.Codes(), ", "))
+			}
+		}
+	}
+	log.Printf("INFO: Evaluating %s on %d image(s) from '%s'.", ocrEngine.Name(), len(cases), *corpus)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := service.EvaluateOCR(ctx, ocrService, cases, options)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+	report.Corpus, report.Preset = *corpus, *preset
+	report.Engine = ocrEngine.Version()
+
+	var changes []types.OCREvalChange
+	if baseline != nil {
+		changes = service.CompareOCREvalReports(baseline, report, *tolerance)
+	}
+
+	if *jsonPath != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatalf("FATAL: Failed to encode report: %v", err)
+		}
+		writeOutput(*jsonPath, append(data, '\n'))
+	}
+	if *markdownPath != "" {
+		writeOutput(*markdownPath, []byte(service.FormatOCREvalMarkdown(report, changes)))
+	}
+
+	log.Printf("INFO: Overall CER %.2f%%, WER %.2f%% (%d failed).", report.Overall.CER*100, report.Overall.WER*100, report.Overall.Failed)
+	regressed := false
+	for _, change := range changes {
+		if change.Regressed {
+			log.Printf("ERROR: %s of '%s' regressed from %.2f%% to %.2f%%.", strings.ToUpper(change.Metric), change.Language, change.Baseline*100, change.Current*100)
+			regressed = true
+		}
+	}
+	if regressed {
+		stop()
+		os.Exit(1)
+	}
+}
+
+// envOr returns the environment variable key, or fallback when it is unset or empty.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// writeOutput writes data to the file at path, or to standard output for "-".
+func writeOutput(path string, data []byte) {
+	if path == "-" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		log.Fatalf("FATAL: Failed to write '%s': %v", path, err)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +19,34 @@ type AppConfig struct {
 	Environment string
 	MongoURI    string
 	JWTSecret   string // <--- NEW: Secret key for JWT signing
+
+	// OCR Configuration
+	OCRTimeout        time.Duration // Maximum wall-clock time a single OCR scan may take
+	OCRMaxConcurrency int           // Maximum number of Tesseract jobs running at once
+	TessdataDir       string        // Directory holding *.traineddata files (empty = auto-detect)
+	OCRPresetsFile    string        // Optional JSON file with additional named OCR option presets
+	SpellWordListDir  string        // Optional directory of <language>.txt word lists for spell correction
+	OCRCacheTTL       time.Duration // How long OCR results are cached (0 disables the cache)
+	OCRCacheEntries   int           // Maximum number of results in the in-process cache tier
+	OCREngines        []string      // OCR engine backends to enable, in order of preference
+	OCREngine         string        // Default OCR engine (empty = first available in OCREngines)
+	TesseractCmd      string        // Path or name of the tesseract program used by the CLI engine
+
+	// Upload Limits
+	MaxUploadBytes     int64 // Maximum size of an uploaded image
+	MaxScanUploadBytes int64 // Maximum size of all page images of one /scan request together
+	MaxImageSide       int   // Maximum width or height of an uploaded image, in pixels
+	MaxImagePixels     int64 // Maximum width*height of an uploaded image (decompression bomb guard)
+
+	// Search Configuration
+	SearchIndex string // Full-text index backend: "mongo" (persistent) or "memory" (in-process, for tests)
+
+	// Blob Storage Configuration
+	BlobStore string // Where uploaded images are kept: "local" (filesystem) or "gridfs" (MongoDB)
+	BlobDir   string // Root directory of the local blob store
+
+	// Webhook Configuration
+	WebhookAllowPrivateNetworks bool // Allow webhook URLs resolving to loopback/private addresses (local development only)
 	// Add other configurations like API keys etc.
 }
 
@@ -64,5 +95,138 @@ func LoadConfig() (*AppConfig, error) {
 		return nil, fmt.Errorf("JWT_SECRET environment variable is not set")
 	}
 
+	// OCR timeout, expressed as a Go duration string (e.g., "30s", "2m").
+	ocrTimeoutStr := os.Getenv("OCR_TIMEOUT")
+	if ocrTimeoutStr == "" {
+		ocrTimeoutStr = "30s"
+	}
+	cfg.OCRTimeout, err = time.ParseDuration(ocrTimeoutStr)
+	if err != nil || cfg.OCRTimeout <= 0 {
+		return nil, fmt.Errorf("invalid OCR_TIMEOUT environment variable: %q", ocrTimeoutStr)
+	}
+
+	// OCR concurrency limit. Abandoned (timed-out) Tesseract calls keep running
+	// until they finish, so this also bounds how much CPU they can hold.
+	ocrConcurrencyStr := os.Getenv("OCR_MAX_CONCURRENCY")
+	if ocrConcurrencyStr == "" {
+		ocrConcurrencyStr = "4"
+	}
+	cfg.OCRMaxConcurrency, err = strconv.Atoi(ocrConcurrencyStr)
+	if err != nil || cfg.OCRMaxConcurrency < 1 {
+		return nil, fmt.Errorf("invalid OCR_MAX_CONCURRENCY environment variable: %q", ocrConcurrencyStr)
+	}
+
+	// Tesseract language data directory. TESSDATA_PREFIX is the variable Tesseract itself
+	// honors; when unset, well-known install locations are probed at startup.
+	cfg.TessdataDir = os.Getenv("TESSDATA_PREFIX")
+
+	// Optional OCR presets file; built-in presets are always available.
+	cfg.OCRPresetsFile = os.Getenv("OCR_PRESETS_FILE")
+
+	// Optional word lists for spell correction; English and Nepali lists are built in.
+	cfg.SpellWordListDir = os.Getenv("SPELL_WORDLIST_DIR")
+
+	// OCR result cache: identical scans within the TTL are answered without running Tesseract.
+	ocrCacheTTLStr := os.Getenv("OCR_CACHE_TTL")
+	if ocrCacheTTLStr == "" {
+		ocrCacheTTLStr = "24h"
+	}
+	cfg.OCRCacheTTL, err = time.ParseDuration(ocrCacheTTLStr)
+	if err != nil || cfg.OCRCacheTTL < 0 {
+		return nil, fmt.Errorf("invalid OCR_CACHE_TTL environment variable: %q", ocrCacheTTLStr)
+	}
+	ocrCacheEntriesStr := os.Getenv("OCR_CACHE_ENTRIES")
+	if ocrCacheEntriesStr == "" {
+		ocrCacheEntriesStr = "1000"
+	}
+	cfg.OCRCacheEntries, err = strconv.Atoi(ocrCacheEntriesStr)
+	if err != nil || cfg.OCRCacheEntries < 1 {
+		return nil, fmt.Errorf("invalid OCR_CACHE_ENTRIES environment variable: %q", ocrCacheEntriesStr)
+	}
+
+	// OCR engine backends. The CGO engine only exists in binaries built with cgo (and without the
+	// notesseract tag); engines that are unavailable are skipped at startup.
+	ocrEnginesStr := os.Getenv("OCR_ENGINES")
+	if ocrEnginesStr == "" {
+		ocrEnginesStr = "tesseract,tesseract-cli"
+	}
+	for _, name := range strings.Split(ocrEnginesStr, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cfg.OCREngines = append(cfg.OCREngines, name)
+		}
+	}
+	cfg.OCREngine = strings.TrimSpace(os.Getenv("OCR_ENGINE"))
+	if cfg.OCREngine != "" && !slices.Contains(cfg.OCREngines, cfg.OCREngine) {
+		return nil, fmt.Errorf("invalid OCR_ENGINE environment variable: %q is not listed in OCR_ENGINES", cfg.OCREngine)
+	}
+	cfg.TesseractCmd = os.Getenv("TESSERACT_CMD")
+	if cfg.TesseractCmd == "" {
+		cfg.TesseractCmd = "tesseract"
+	}
+
+	// Upload limits for images sent to /scan.
+	maxUploadStr := os.Getenv("MAX_UPLOAD_BYTES")
+	if maxUploadStr == "" {
+		maxUploadStr = "20971520" // 20 MiB
+	}
+	cfg.MaxUploadBytes, err = strconv.ParseInt(maxUploadStr, 10, 64)
+	if err != nil || cfg.MaxUploadBytes < 1 {
+		return nil, fmt.Errorf("invalid MAX_UPLOAD_BYTES environment variable: %q", maxUploadStr)
+	}
+	maxScanUploadStr := os.Getenv("MAX_SCAN_UPLOAD_BYTES")
+	if maxScanUploadStr == "" {
+		maxScanUploadStr = strconv.FormatInt(max(104857600, cfg.MaxUploadBytes), 10) // 100 MiB
+	}
+	cfg.MaxScanUploadBytes, err = strconv.ParseInt(maxScanUploadStr, 10, 64)
+	if err != nil || cfg.MaxScanUploadBytes < cfg.MaxUploadBytes {
+		return nil, fmt.Errorf("invalid MAX_SCAN_UPLOAD_BYTES environment variable (must be at least MAX_UPLOAD_BYTES): %q", maxScanUploadStr)
+	}
+	maxSideStr := os.Getenv("MAX_IMAGE_SIDE")
+	if maxSideStr == "" {
+		maxSideStr = "20000"
+	}
+	cfg.MaxImageSide, err = strconv.Atoi(maxSideStr)
+	if err != nil || cfg.MaxImageSide < 1 {
+		return nil, fmt.Errorf("invalid MAX_IMAGE_SIDE environment variable: %q", maxSideStr)
+	}
+	maxPixelsStr := os.Getenv("MAX_IMAGE_PIXELS")
+	if maxPixelsStr == "" {
+		maxPixelsStr = "60000000" // A4 at 600 DPI is about 35 megapixels
+	}
+	cfg.MaxImagePixels, err = strconv.ParseInt(maxPixelsStr, 10, 64)
+	if err != nil || cfg.MaxImagePixels < 1 {
+		return nil, fmt.Errorf("invalid MAX_IMAGE_PIXELS environment variable: %q", maxPixelsStr)
+	}
+
+	// Full-text search index backend. The in-memory index is rebuilt empty on every start.
+	cfg.SearchIndex = os.Getenv("SEARCH_INDEX")
+	if cfg.SearchIndex == "" {
+		cfg.SearchIndex = "mongo"
+	}
+	if cfg.SearchIndex != "mongo" && cfg.SearchIndex != "memory" {
+		return nil, fmt.Errorf("invalid SEARCH_INDEX environment variable: %q (expected \"mongo\" or \"memory\")", cfg.SearchIndex)
+	}
+
+	// Blob store for uploaded scan images and their thumbnails.
+	cfg.BlobStore = os.Getenv("BLOB_STORE")
+	if cfg.BlobStore == "" {
+		cfg.BlobStore = "local"
+	}
+	if cfg.BlobStore != "local" && cfg.BlobStore != "gridfs" {
+		return nil, fmt.Errorf("invalid BLOB_STORE environment variable: %q (expected \"local\" or \"gridfs\")", cfg.BlobStore)
+	}
+	cfg.BlobDir = os.Getenv("BLOB_DIR")
+	if cfg.BlobDir == "" {
+		cfg.BlobDir = "./data/blobs"
+	}
+
+	// Webhooks are user-supplied URLs, so by default they may only reach public addresses.
+	if allowPrivateStr := os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS"); allowPrivateStr != "" {
+		cfg.WebhookAllowPrivateNetworks, err = strconv.ParseBool(allowPrivateStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WEBHOOK_ALLOW_PRIVATE_NETWORKS environment variable: %q", allowPrivateStr)
+		}
+	}
+
 	return cfg, nil
 }
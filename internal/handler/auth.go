@@ -5,10 +5,14 @@ import (
 	// For simulated load
 	"github.com/danielgtaylor/huma/v2"
 
-	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/middleware" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
 	// Adjust import path to your module
 )
 
+// BearerAuthScheme is the name of the OpenAPI security scheme for JWT bearer tokens.
+const BearerAuthScheme = "bearerAuth"
+
 // RegisterHomeHandlers registers API endpoints for the root and health checks.
 // This method is part of the Handlers struct, giving it access to shared dependencies
 // like AppConfig and DBClient.
@@ -19,3 +23,21 @@ func (h *Handlers) RegisterAuthHandlers(api huma.API) {
 		return resp, nil
 	})
 }
+
+// requireAuth returns an operation handler that protects an endpoint with a JWT bearer token.
+// When `roles` are given, only users with one of those roles may call it (e.g., "admin").
+func (h *Handlers) requireAuth(api huma.API, roles ...string) func(o *huma.Operation) {
+	return func(o *huma.Operation) {
+		o.Middlewares = append(o.Middlewares, middleware.RequireAuth(api, h.AppConfig.JWTSecret, roles...))
+		o.Security = []map[string][]string{{BearerAuthScheme: {}}}
+	}
+}
+
+// optionalAuth returns an operation handler for endpoints that accept, but do not require, a JWT bearer token.
+func (h *Handlers) optionalAuth(api huma.API) func(o *huma.Operation) {
+	return func(o *huma.Operation) {
+		o.Middlewares = append(o.Middlewares, middleware.OptionalAuth(api, h.AppConfig.JWTSecret))
+		// An empty requirement marks authentication as optional in the OpenAPI document.
+		o.Security = []map[string][]string{{}, {BearerAuthScheme: {}}}
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/middleware" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+)
+
+// RegisterDictionaryHandlers registers API endpoints for managing custom OCR dictionaries.
+// Listing is public so clients can discover domains; reading and writing word lists requires an admin token.
+func (h *Handlers) RegisterDictionaryHandlers(api huma.API) {
+	// GET /ocr/dictionaries: Lists available dictionaries (without their word lists).
+	huma.Get(api, "/ocr/dictionaries", func(ctx context.Context, input *struct{}) (*types.OCRDictionariesOutput, error) {
+		log.Println("INFO: Received request to list OCR dictionaries.")
+		return h.Services.DictionaryService.ListDictionaries(ctx)
+	})
+
+	// GET /ocr/dictionaries/{domain}: Returns a dictionary including its words and patterns.
+	huma.Get(api, "/ocr/dictionaries/{domain}", func(ctx context.Context, input *types.OCRDictionaryDomainInput) (*types.OCRDictionaryOutput, error) {
+		log.Printf("INFO: Received request to get OCR dictionary: %s", input.Domain)
+		return h.Services.DictionaryService.GetDictionary(ctx, input.Domain)
+	}, h.requireAuth(api, "admin"))
+
+	// POST /ocr/dictionaries: Uploads a new dictionary.
+	huma.Post(api, "/ocr/dictionaries", func(ctx context.Context, input *types.CreateOCRDictionaryInput) (*types.OCRDictionaryOutput, error) {
+		log.Printf("INFO: Received request to create OCR dictionary: %s", input.Body.Domain)
+
+		createdBy := ""
+		if claims, ok := middleware.ClaimsFromContext(ctx); ok {
+			createdBy = claims.UserID
+		}
+
+		dict, err := h.Services.DictionaryService.CreateDictionary(ctx, createdBy, input.Body)
+		if err != nil {
+			log.Printf("ERROR: Failed to create OCR dictionary %s: %v", input.Body.Domain, err)
+			return nil, err // Return the error directly; Huma handles the Problem JSON conversion.
+		}
+		return dict, nil
+	}, h.requireAuth(api, "admin"), func(o *huma.Operation) {
+		o.DefaultStatus = 201
+	})
+
+	// PUT /ocr/dictionaries/{domain}: Replaces the contents of a dictionary.
+	huma.Put(api, "/ocr/dictionaries/{domain}", func(ctx context.Context, input *types.UpdateOCRDictionaryInput) (*types.OCRDictionaryOutput, error) {
+		log.Printf("INFO: Received request to update OCR dictionary: %s", input.Domain)
+
+		dict, err := h.Services.DictionaryService.UpdateDictionary(ctx, input.Domain, input.Body)
+		if err != nil {
+			log.Printf("ERROR: Failed to update OCR dictionary %s: %v", input.Domain, err)
+			return nil, err
+		}
+		return dict, nil
+	}, h.requireAuth(api, "admin"))
+
+	// DELETE /ocr/dictionaries/{domain}: Removes a dictionary.
+	huma.Delete(api, "/ocr/dictionaries/{domain}", func(ctx context.Context, input *types.OCRDictionaryDomainInput) (*struct{}, error) {
+		log.Printf("INFO: Received request to delete OCR dictionary: %s", input.Domain)
+
+		if err := h.Services.DictionaryService.DeleteDictionary(ctx, input.Domain); err != nil {
+			log.Printf("ERROR: Failed to delete OCR dictionary %s: %v", input.Domain, err)
+			return nil, err
+		}
+		return nil, nil
+	}, h.requireAuth(api, "admin"))
+}
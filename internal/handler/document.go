@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/middleware" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+)
+
+// RegisterDocumentHandlers registers API endpoints for legal documents.
+// Documents are private to their owner (and admins), so all document routes require a bearer token.
+func (h *Handlers) RegisterDocumentHandlers(api huma.API) {
+	// POST /documents/parse: Segments plain text into act title, chapters, sections, sub-sections and clauses.
+	huma.Post(api, "/documents/parse", func(ctx context.Context, input *types.ParseDocumentInput) (*types.ParseDocumentOutput, error) {
+		log.Printf("INFO: Received request to parse document structure (%d bytes).", len(input.Body.Text))
+
+		return &types.ParseDocumentOutput{Body: h.Services.TextService.ParseStructure(input.Body.Text)}, nil
+	})
+
+	// POST /documents: Creates a document, optionally with initial pages.
+	huma.Post(api, "/documents", func(ctx context.Context, input *types.CreateDocumentInput) (*types.DocumentOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to create document '%s' for user %s", input.Body.Title, claims.UserID)
+
+		doc, err := h.Services.DocumentService.CreateDocument(ctx, claims, input.Body)
+		if err != nil {
+			log.Printf("ERROR: Failed to create document '%s': %v", input.Body.Title, err)
+			return nil, err // Return the error directly; Huma handles the Problem JSON conversion.
+		}
+		return doc, nil
+	}, h.requireAuth(api), func(o *huma.Operation) {
+		o.DefaultStatus = 201
+	})
+
+	// GET /documents: Lists the caller's documents (all documents for admins).
+	huma.Get(api, "/documents", func(ctx context.Context, input *struct{}) (*types.DocumentsOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to list documents for user %s", claims.UserID)
+		return h.Services.DocumentService.ListDocuments(ctx, claims)
+	}, h.requireAuth(api))
+
+	// GET /documents/{id}: Returns a document with its pages and version history.
+	huma.Get(api, "/documents/{id}", func(ctx context.Context, input *types.DocumentIDInput) (*types.DocumentOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to get document: %s", input.ID)
+		return h.Services.DocumentService.GetDocument(ctx, claims, input.ID)
+	}, h.requireAuth(api))
+
+	// POST /documents/{id}/pages: Attaches stored scans as pages.
+	huma.Post(api, "/documents/{id}/pages", func(ctx context.Context, input *types.AddDocumentPagesInput) (*types.DocumentOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to add %d page(s) to document %s", len(input.Body.ScanIDs), input.ID)
+
+		doc, err := h.Services.DocumentService.AddPages(ctx, claims, input.ID, input.Body.ScanIDs, input.Body.Position)
+		if err != nil {
+			log.Printf("ERROR: Failed to add pages to document %s: %v", input.ID, err)
+			return nil, err
+		}
+		return doc, nil
+	}, h.requireAuth(api))
+
+	// PUT /documents/{id}/pages: Reorders the pages of a document.
+	huma.Put(api, "/documents/{id}/pages", func(ctx context.Context, input *types.ReorderDocumentPagesInput) (*types.DocumentOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to reorder pages of document %s", input.ID)
+
+		doc, err := h.Services.DocumentService.ReorderPages(ctx, claims, input.ID, input.Body.ScanIDs)
+		if err != nil {
+			log.Printf("ERROR: Failed to reorder pages of document %s: %v", input.ID, err)
+			return nil, err
+		}
+		return doc, nil
+	}, h.requireAuth(api))
+
+	// GET /documents/{id}/text: Returns the text of all pages in reading order.
+	huma.Get(api, "/documents/{id}/text", func(ctx context.Context, input *types.DocumentTextInput) (*types.DocumentTextOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request for the text of document %s (version %d)", input.ID, input.Version)
+		return h.Services.DocumentService.GetDocumentText(ctx, claims, input.ID, input.Version)
+	}, h.requireAuth(api))
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/middleware" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils"      // Adjust import path to your module
+)
+
+// RegisterExtractionHandlers registers the form extraction endpoint and the management of its templates.
+// Templates can be listed and read by anyone so clients know which fields to expect; changing them requires an admin token.
+func (h *Handlers) RegisterExtractionHandlers(api huma.API) {
+	// POST /extract/{template}: Reads the fields of a filled-in form.
+	huma.Post(api, "/extract/{template}", func(ctx context.Context, input *types.ExtractInput) (*types.ExtractOutput, error) {
+		log.Printf("INFO: Received request to extract form fields with template: %s", input.Template)
+
+		formData := input.RawBody.Data()
+		if !formData.Image.IsSet {
+			return nil, huma.Error400BadRequest("No image file provided. Please upload a file with the 'image' field.", nil)
+		}
+		imageData, format, err := h.readImageUpload(formData.Image)
+		if err != nil {
+			return nil, err
+		}
+		width, height, err := utils.ImageDimensions(imageData, format)
+		if err != nil { // Already checked by readImageUpload.
+			return nil, huma.Error400BadRequest(err.Error(), nil)
+		}
+		return h.Services.ExtractionService.Extract(ctx, input.Template, imageData, width, height)
+	}, h.limitUpload(api, h.AppConfig.MaxUploadBytes), func(o *huma.Operation) {
+		o.Description = "OCRs an image of a fixed-layout form (e.g., a citizenship certificate) and returns the fields of the template " +
+			"as typed values (text, numbers, BS/AD dates, ID numbers) with per-field confidence and validation. See GET /ocr/templates."
+	})
+
+	// GET /ocr/templates: Lists extraction templates.
+	huma.Get(api, "/ocr/templates", func(ctx context.Context, input *struct{}) (*types.ExtractionTemplatesOutput, error) {
+		log.Println("INFO: Received request to list extraction templates.")
+		return h.Services.ExtractionService.ListTemplates(ctx)
+	})
+
+	// GET /ocr/templates/{name}: Returns a template including its fields.
+	huma.Get(api, "/ocr/templates/{name}", func(ctx context.Context, input *types.ExtractionTemplateNameInput) (*types.ExtractionTemplateOutput, error) {
+		log.Printf("INFO: Received request to get extraction template: %s", input.Name)
+		return h.Services.ExtractionService.GetTemplate(ctx, input.Name)
+	})
+
+	// POST /ocr/templates: Creates a template.
+	huma.Post(api, "/ocr/templates", func(ctx context.Context, input *types.CreateExtractionTemplateInput) (*types.ExtractionTemplateOutput, error) {
+		log.Printf("INFO: Received request to create extraction template: %s", input.Body.Name)
+
+		createdBy := ""
+		if claims, ok := middleware.ClaimsFromContext(ctx); ok {
+			createdBy = claims.UserID
+		}
+
+		tmpl, err := h.Services.ExtractionService.CreateTemplate(ctx, createdBy, input.Body)
+		if err != nil {
+			log.Printf("ERROR: Failed to create extraction template %s: %v", input.Body.Name, err)
+			return nil, err
+		}
+		return tmpl, nil
+	}, h.requireAuth(api, "admin"), func(o *huma.Operation) {
+		o.DefaultStatus = 201
+	})
+
+	// PUT /ocr/templates/{name}: Replaces a template.
+	huma.Put(api, "/ocr/templates/{name}", func(ctx context.Context, input *types.UpdateExtractionTemplateInput) (*types.ExtractionTemplateOutput, error) {
+		log.Printf("INFO: Received request to update extraction template: %s", input.Name)
+
+		tmpl, err := h.Services.ExtractionService.UpdateTemplate(ctx, input.Name, input.Body)
+		if err != nil {
+			log.Printf("ERROR: Failed to update extraction template %s: %v", input.Name, err)
+			return nil, err
+		}
+		return tmpl, nil
+	}, h.requireAuth(api, "admin"))
+
+	// DELETE /ocr/templates/{name}: Removes a template.
+	huma.Delete(api, "/ocr/templates/{name}", func(ctx context.Context, input *types.ExtractionTemplateNameInput) (*struct{}, error) {
+		log.Printf("INFO: Received request to delete extraction template: %s", input.Name)
+
+		if err := h.Services.ExtractionService.DeleteTemplate(ctx, input.Name); err != nil {
+			log.Printf("ERROR: Failed to delete extraction template %s: %v", input.Name, err)
+			return nil, err
+		}
+		return nil, nil
+	}, h.requireAuth(api, "admin"))
+}
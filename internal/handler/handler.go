@@ -39,4 +39,28 @@ func (h *Handlers) RegisterHandlers(api huma.API) {
 	// --- THIS IS THE CRUCIAL LINE FOR /scan ROUTE ---
 	h.RegisterScanHandlers(api) // Make absolutely sure this line is present and uncommented!
 
+	// Register OCR language and engine discovery handlers (/ocr/languages, /ocr/engines)
+	h.RegisterLanguageHandlers(api)
+
+	// Register OCR preset handlers (/ocr/presets)
+	h.RegisterPresetHandlers(api)
+
+	// Register custom OCR dictionary handlers (/ocr/dictionaries)
+	h.RegisterDictionaryHandlers(api)
+
+	// Register form extraction handlers (/extract/{template}, /ocr/templates)
+	h.RegisterExtractionHandlers(api)
+
+	// Register plain-text processing handlers (/text/transliterate)
+	h.RegisterTextHandlers(api)
+
+	// Register legal document handlers (/documents, /documents/parse)
+	h.RegisterDocumentHandlers(api)
+
+	// Register full-text search handlers (/search)
+	h.RegisterSearchHandlers(api)
+
+	// Register webhook handlers (/webhooks, /webhooks/deliveries)
+	h.RegisterWebhookHandlers(api)
+
 }
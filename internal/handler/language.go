@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// RegisterLanguageHandlers registers API endpoints describing the installed OCR languages and engines.
+func (h *Handlers) RegisterLanguageHandlers(api huma.API) {
+	// GET /ocr/languages: Lists the language/script models discovered in the tessdata directory.
+	// Any of these codes (or a '+'-joined combination) is accepted by the 'lang' field of /scan.
+	huma.Get(api, "/ocr/languages", func(ctx context.Context, input *struct{}) (*types.OCRLanguagesOutput, error) {
+		log.Println("INFO: Received request to list OCR languages.")
+
+		resp := &types.OCRLanguagesOutput{}
+		resp.Body.TessdataDir = h.Services.LanguageService.TessdataDir()
+		resp.Body.Languages = h.Services.LanguageService.ListLanguages()
+		return resp, nil
+	})
+
+	// GET /ocr/engines: Lists the OCR engine backends enabled on this server.
+	huma.Get(api, "/ocr/engines", func(ctx context.Context, input *struct{}) (*types.OCREnginesOutput, error) {
+		log.Println("INFO: Received request to list OCR engines.")
+
+		resp := &types.OCREnginesOutput{}
+		resp.Body.Engines = h.Services.OCREngines.ListEngines()
+		return resp, nil
+	})
+}
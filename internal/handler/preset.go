@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// RegisterPresetHandlers registers API endpoints describing the server-side OCR presets.
+func (h *Handlers) RegisterPresetHandlers(api huma.API) {
+	// GET /ocr/presets: Lists the named Tesseract option presets accepted by the 'preset' field of /scan.
+	huma.Get(api, "/ocr/presets", func(ctx context.Context, input *struct{}) (*types.OCRPresetsOutput, error) {
+		log.Println("INFO: Received request to list OCR presets.")
+
+		resp := &types.OCRPresetsOutput{}
+		resp.Body.Presets = h.Services.OCRPresetService.ListPresets()
+		return resp, nil
+	})
+}
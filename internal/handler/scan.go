@@ -2,18 +2,29 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"image"
 	"log"
-	"sort"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/sse"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/axyut/niyamAPI/internal/middleware"
+	"github.com/axyut/niyamAPI/internal/service"
 	"github.com/axyut/niyamAPI/internal/types"
+	"github.com/axyut/niyamAPI/internal/utils"
 )
 
-// RegisterScanHandlers registers the OCR scanning endpoint with the API.
+// RegisterScanHandlers registers the OCR scanning endpoints with the API.
 // It's a method on the Handlers struct, giving it access to services.
 func (h *Handlers) RegisterScanHandlers(api huma.API) {
 
@@ -28,11 +39,19 @@ func (h *Handlers) RegisterScanHandlers(api huma.API) {
 			return nil, huma.Error400BadRequest("No image file provided. Please upload a file with the 'image' field.", nil)
 		}
 
-		// Read the content of the uploaded image file.
-		imageData, err := io.ReadAll(formData.Image)
-		if err != nil {
-			log.Printf("ERROR: Failed to read uploaded image file: %v", err)
-			return nil, huma.Error400BadRequest(fmt.Sprintf("Failed to read image file: %v", err), nil)
+		// Read the uploaded page images, enforcing the size, format and pixel limits.
+		if len(formData.Pages) >= maxScanPages {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("A scan can have at most %d pages.", maxScanPages), nil)
+		}
+		pages := make([][]byte, 0, 1+len(formData.Pages))
+		formats := make([]string, 0, 1+len(formData.Pages))
+		for _, file := range append([]huma.FormFile{formData.Image}, formData.Pages...) {
+			imageData, format, err := h.readImageUpload(file)
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, imageData)
+			formats = append(formats, format)
 		}
 
 		// --- Language Validation and Normalization ---
@@ -60,7 +79,7 @@ func (h *Handlers) RegisterScanHandlers(api huma.API) {
 			if trimmedCode == "" {
 				continue // Skip empty strings that might result from split (e.g., "eng++hin")
 			}
-			if _, ok := types.SupportedOCRLanguages[trimmedCode]; ok {
+			if h.Services.LanguageService.IsSupported(trimmedCode) {
 				validatedLangCodes = append(validatedLangCodes, trimmedCode)
 			} else {
 				invalidLanguages = append(invalidLanguages, trimmedCode)
@@ -70,7 +89,7 @@ func (h *Handlers) RegisterScanHandlers(api huma.API) {
 		// Handle cases where all provided languages are invalid, or none were provided at all.
 		if len(invalidLanguages) > 0 {
 			errorMessage := fmt.Sprintf("Unsupported language code(s) found: '%s'. Supported codes are: %s.",
-				strings.Join(invalidLanguages, "', '"), strings.Join(getSortedSupportedLangCodes(), ", "))
+				strings.Join(invalidLanguages, "', '"), strings.Join(h.Services.LanguageService.Codes(), ", "))
 			log.Printf("ERROR: %s", errorMessage)
 			return nil, huma.Error400BadRequest(errorMessage, nil)
 		}
@@ -86,31 +105,624 @@ func (h *Handlers) RegisterScanHandlers(api huma.API) {
 		log.Printf("INFO: OCR language(s) finalized: %s", finalLanguage)
 		// --- End Language Validation ---
 
-		// Call the OCRService with both image data and the finalized language string.
-		text, err := h.Services.OCRService.ExtractTextFromImage(ctx, imageData, finalLanguage)
+		// --- OCR Options (preset + per-request overrides) ---
+		overrides, err := parseOCROptionOverrides(formData)
+		if err != nil {
+			log.Printf("ERROR: Invalid OCR options: %v", err)
+			return nil, huma.Error400BadRequest(fmt.Sprintf("Invalid OCR options: %v", err), nil)
+		}
+		// A selected dictionary contributes its words and patterns on top of the request's own.
+		if domain := strings.TrimSpace(formData.Dictionary); domain != "" {
+			dictOptions, err := h.Services.DictionaryService.OptionsForDomain(ctx, domain)
+			if err != nil {
+				log.Printf("ERROR: Failed to load OCR dictionary '%s': %v", domain, err)
+				return nil, err
+			}
+			overrides = service.MergeOCROptions(overrides, dictOptions)
+		}
+		ocrOptions, err := h.Services.OCRPresetService.ResolveOptions(strings.TrimSpace(formData.Preset), overrides)
+		if err != nil {
+			log.Printf("ERROR: Failed to resolve OCR options: %v", err)
+			return nil, err // Already a huma 400 error with a descriptive message.
+		}
+		// Pin the engine, so results (and cache entries) name the engine that actually ran.
+		engine, err := h.Services.OCREngines.Engine(ocrOptions.Engine)
+		if err != nil {
+			return nil, err
+		}
+		ocrOptions.Engine = engine.Name()
+		// --- End OCR Options ---
+
+		parseStructure, err := parseOptionalBool("parse_structure", formData.ParseStructure)
+		if err != nil {
+			return nil, huma.Error400BadRequest(err.Error(), nil)
+		}
+
+		regions, err := parseScanRegions(formData.Regions, formData.RegionUnits, pages, formats)
+		if err != nil {
+			return nil, err
+		}
+
+		excludeUnreliable, err := parseOptionalBool("exclude_unreliable", formData.ExcludeUnreliable)
+		if err != nil {
+			return nil, huma.Error400BadRequest(err.Error(), nil)
+		}
+
+		barcodes, err := parseOptionalBool("barcodes", formData.Barcodes)
+		if err != nil {
+			return nil, huma.Error400BadRequest(err.Error(), nil)
+		}
+
+		tables, err := parseOptionalBool("tables", formData.Tables)
+		if err != nil {
+			return nil, huma.Error400BadRequest(err.Error(), nil)
+		}
+
+		checkQuality, err := parseOptionalBool("check_quality", formData.CheckQuality)
+		if err != nil {
+			return nil, huma.Error400BadRequest(err.Error(), nil)
+		}
+
+		spellCorrect, err := parseOptionalBool("spell_correct", formData.SpellCorrect)
+		if err != nil {
+			return nil, huma.Error400BadRequest(err.Error(), nil)
+		}
+		// Correction needs a word list for one of the languages (or the words of a selected dictionary).
+		if spellCorrect != nil && *spellCorrect && len(ocrOptions.UserWords) == 0 &&
+			!slices.ContainsFunc(validatedLangCodes, func(code string) bool { return slices.Contains(h.Services.SpellService.Languages(), code) }) {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("Spell correction is not available for '%s'. Word lists exist for: %s.",
+				finalLanguage, strings.Join(h.Services.SpellService.Languages(), ", ")), nil)
+		}
+
+		// Reject an unsupported transliteration scheme before spending time on OCR.
+		scheme := strings.ToLower(strings.TrimSpace(formData.Transliterate))
+		if scheme != "" {
+			if _, err := h.Services.TextService.Transliterate("", "", scheme); err != nil {
+				return nil, err
+			}
+		}
+
+		mode := strings.ToLower(strings.TrimSpace(formData.Mode))
+		if mode != "" && mode != types.ScanModeFast && mode != types.ScanModeAccurate {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("mode must be '%s' or '%s', got '%s'", types.ScanModeFast, types.ScanModeAccurate, formData.Mode), nil)
+		}
+
+		async, err := parseOptionalBool("async", formData.Async)
+		if err != nil {
+			return nil, huma.Error400BadRequest(err.Error(), nil)
+		}
+
+		// Scans made with a bearer token belong to that user.
+		ownerID := ""
+		if claims, ok := middleware.ClaimsFromContext(ctx); ok {
+			ownerID = claims.UserID
+		}
+
+		// A per-scan webhook is validated now so a bad URL fails the request, not the notification.
+		webhookURL := strings.TrimSpace(formData.WebhookURL)
+		if webhookURL != "" {
+			if ownerID == "" {
+				return nil, huma.Error401Unauthorized("Webhook notifications require a bearer token.", nil)
+			}
+			if len(formData.WebhookSecret) < minWebhookSecretLength {
+				return nil, huma.Error400BadRequest(fmt.Sprintf("webhook_secret must be at least %d characters long.", minWebhookSecretLength), nil)
+			}
+			if err := h.Services.WebhookService.ValidateURL(webhookURL); err != nil {
+				return nil, err
+			}
+		}
+
+		req := &scanRequest{
+			id:                primitive.NewObjectID(),
+			ownerID:           ownerID,
+			language:          finalLanguage,
+			preset:            strings.TrimSpace(formData.Preset),
+			options:           ocrOptions,
+			scheme:            scheme,
+			parseStructure:    parseStructure != nil && *parseStructure,
+			tables:            tables != nil && *tables,
+			barcodes:          barcodes != nil && *barcodes,
+			excludeUnreliable: excludeUnreliable != nil && *excludeUnreliable,
+			checkQuality:      checkQuality != nil && *checkQuality,
+			spellCorrect:      spellCorrect != nil && *spellCorrect,
+			accurate:          mode == types.ScanModeAccurate,
+			pages:             pages,
+			regions:           regions,
+			webhookURL:        webhookURL,
+			webhookSecret:     formData.WebhookSecret,
+		}
+
+		if async == nil || !*async {
+			// The response must be written before the server's write deadline, so all pages and regions of a
+			// synchronous scan share one OCR timeout. Long scans should use async=true.
+			scanCtx, cancel := context.WithTimeout(ctx, h.AppConfig.OCRTimeout)
+			body, err := h.runScan(scanCtx, req, nil)
+			cancel()
+			if err != nil && errors.Is(scanCtx.Err(), context.DeadlineExceeded) {
+				log.Printf("WARNING: Synchronous scan of %d page(s) exceeded the maximum duration of %s", len(pages), h.AppConfig.OCRTimeout)
+				err = huma.Error504GatewayTimeout(fmt.Sprintf("Scan did not finish within %s; use 'async=true' for long scans.", h.AppConfig.OCRTimeout), nil)
+			}
+			h.notifyScan(context.WithoutCancel(ctx), req, body, err) // Also when the client went away.
+			if err != nil {
+				return nil, err
+			}
+			return &types.ScanOutput{Status: http.StatusOK, Body: *body}, nil
+		}
+
+		// Asynchronous scan: answer right away and report progress on GET /scans/{id}/events,
+		// which only the owner can follow.
+		if ownerID == "" {
+			return nil, huma.Error401Unauthorized("Asynchronous scans require a bearer token.", nil)
+		}
+		jobID := req.id.Hex()
+		h.Services.ScanJobService.CreateJob(jobID, ownerID, len(pages))
+		go func() {
+			// The scan outlives the request, so detach it from the request's cancellation.
+			bgCtx := context.WithoutCancel(ctx)
+			body, err := h.runScan(bgCtx, req, func(progress types.ScanProgressEvent) {
+				h.Services.ScanJobService.Publish(jobID, types.ScanEvent{Progress: &progress})
+			})
+			h.notifyScan(bgCtx, req, body, err)
+			if err != nil {
+				h.Services.ScanJobService.Publish(jobID, types.ScanEvent{Failed: scanFailure(err)})
+				return
+			}
+			h.Services.ScanJobService.Publish(jobID, types.ScanEvent{Completed: body})
+		}()
+
+		log.Printf("INFO: Started asynchronous scan %s (%d page(s)).", jobID, len(pages))
+		return &types.ScanOutput{Status: http.StatusAccepted, Body: types.ScanOutputBody{
+			ID:        jobID,
+			Status:    types.ScanStatusQueued,
+			EventsURL: "/scans/" + jobID + "/events",
+		}}, nil
+	}, h.limitUpload(api, h.AppConfig.MaxScanUploadBytes), h.optionalAuth(api), func(o *huma.Operation) {
+		// Document the languages discovered at startup, since they cannot be a static `enum` tag.
+		o.Description = fmt.Sprintf("Extracts text from an uploaded image. Installed 'lang' codes: %s. Combine codes with '+' (e.g., 'eng+nep'). "+
+			"With 'async=true' the scan runs in the background: the response (202) carries its ID, and progress is streamed by GET /scans/{id}/events. "+
+			"Registered webhooks (see POST /webhooks) and an optional per-scan 'webhook_url' are notified when the scan completes or fails.",
+			strings.Join(h.Services.LanguageService.Codes(), ", "))
+		o.Responses = map[string]*huma.Response{"202": {Description: "Asynchronous scan accepted"}}
+	})
+
+	// POST /scan/quality: Assesses whether an image is good enough for OCR, without recognizing it.
+	huma.Post(api, "/scan/quality", func(ctx context.Context, input *types.ScanQualityInput) (*types.ScanQualityOutput, error) {
+		formData := input.RawBody.Data()
+		imageData, format, err := h.readImageUpload(formData.Image)
+		if err != nil {
+			return nil, err
+		}
+		if format != utils.ImageFormatPNG && format != utils.ImageFormatJPEG {
+			return nil, huma.NewError(http.StatusUnsupportedMediaType,
+				fmt.Sprintf("The image is a %s image; quality can only be assessed for PNG and JPEG images.", format))
+		}
+		quality, err := h.Services.QualityService.AssessImage(imageData)
 		if err != nil {
-			log.Printf("ERROR: Failed to process image for OCR: %v", err)
-			return nil, huma.Error400BadRequest(fmt.Sprintf("Failed to process image for OCR: %v", err), nil)
+			return nil, huma.NewError(http.StatusUnsupportedMediaType, fmt.Sprintf("Unreadable %s image: %v", format, err))
 		}
+		log.Printf("INFO: Assessed image quality: score %d, %d issue(s)", quality.Score, len(quality.Issues))
+		return &types.ScanQualityOutput{Body: *quality}, nil
+	}, h.limitUpload(api, h.AppConfig.MaxUploadBytes), h.optionalAuth(api), func(o *huma.Operation) {
+		o.Description = "Measures the blur, exposure, contrast, resolution and skew of a photo or scan and returns a 0-100 score " +
+			"with advice, so a client can ask for a retake before uploading to /scan ('check_quality' on /scan does the same inline)."
+	})
 
-		log.Println("INFO: Text extracted successfully from image.")
-		return &types.ScanOutput{
-			Body: struct {
-				Text string `json:"text" huma:"example:Extracted text from the image"`
-			}{
-				Text: text,
+	// GET /scans/{id}/image: Returns the uploaded image of a stored scan (or its thumbnail).
+	huma.Get(api, "/scans/{id}/image", func(ctx context.Context, input *types.ScanImageInput) (*types.ScanImageOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request for image of scan %s (page: %d, thumbnail: %t)", input.ID, input.Page, input.Thumbnail)
+		return h.Services.ScanService.GetScanImage(ctx, claims, input.ID, input.Page, input.Thumbnail)
+	}, h.requireAuth(api), func(o *huma.Operation) {
+		o.Description = "Returns an original uploaded page image of a scan you own, or a JPEG thumbnail of the first page with '?thumbnail=true'."
+		o.Responses = map[string]*huma.Response{
+			"200": {
+				Description: "Scan image",
+				Content: map[string]*huma.MediaType{
+					"image/*": {Schema: &huma.Schema{Type: huma.TypeString, Format: "binary"}},
+				},
 			},
-		}, nil
+		}
+	})
+
+	// GET /scans/{id}/events: Streams the progress of an asynchronous scan as Server-Sent Events.
+	eventsOp := huma.Operation{
+		OperationID: "get-scan-events",
+		Method:      http.MethodGet,
+		Path:        "/scans/{id}/events",
+		Summary:     "Stream scan progress",
+		Description: "Streams 'progress' events (queued, page N of M, preprocessing, recognizing) of an asynchronous scan, " +
+			"ending with a 'completed' event carrying the scan result or a 'failed' event. Reconnect with Last-Event-ID to resume.",
+	}
+	h.requireAuth(api)(&eventsOp)
+	// An SSE handler cannot return errors once streaming, so unknown jobs are rejected beforehand.
+	eventsOp.Middlewares = append(eventsOp.Middlewares, h.requireScanJob(api))
+	sse.Register(api, eventsOp, map[string]any{
+		"progress":  types.ScanProgressEvent{},
+		"completed": types.ScanOutputBody{},
+		"failed":    types.ScanFailedEvent{},
+	}, func(ctx context.Context, input *types.ScanEventsInput, send sse.Sender) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Streaming events of scan %s after event %d", input.ID, input.LastEventID)
+
+		events, err := h.Services.ScanJobService.Subscribe(ctx, claims, input.ID, input.LastEventID)
+		if err != nil { // The job expired since the middleware checked it.
+			send.Data(types.ScanFailedEvent{Status: http.StatusNotFound, Error: err.Error()})
+			return
+		}
+		for event := range events {
+			msg := sse.Message{ID: event.ID}
+			switch {
+			case event.Progress != nil:
+				msg.Data = event.Progress
+			case event.Completed != nil:
+				msg.Data = event.Completed
+			default:
+				msg.Data = event.Failed
+			}
+			if err := send(msg); err != nil {
+				log.Printf("INFO: Stopped streaming events of scan %s: %v", input.ID, err)
+				return
+			}
+		}
 	})
 }
 
-// getSortedSupportedLangCodes is a helper function to get a sorted list of supported language codes.
-// This is used for consistent and readable error messages.
-func getSortedSupportedLangCodes() []string {
-	codes := []string{}
-	for code := range types.SupportedOCRLanguages {
-		codes = append(codes, code)
+// requireScanJob returns a middleware that answers 404 Problem JSON unless the scan job in the
+// path exists and belongs to the authenticated user. It must run after requireAuth.
+func (h *Handlers) requireScanJob(api huma.API) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		claims, _ := middleware.ClaimsFromContext(ctx.Context())
+		if err := h.Services.ScanJobService.CheckAccess(claims, ctx.Param("id")); err != nil {
+			huma.WriteErr(api, ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		next(ctx)
+	}
+}
+
+// maxScanPages is the maximum number of page images in one scan.
+const maxScanPages = 50
+
+// maxScanRegions is the maximum number of regions of interest per page.
+const maxScanRegions = 20
+
+// minWebhookSecretLength matches the minimum length of registered webhook secrets.
+const minWebhookSecretLength = 16
+
+// scanRequest holds the validated inputs of a scan, so it can run within the request or in the background.
+type scanRequest struct {
+	id                primitive.ObjectID // ID of the stored scan (and of its job, for asynchronous scans)
+	ownerID           string
+	language          string
+	preset            string
+	options           types.OCROptions
+	scheme            string // Transliteration scheme; empty for none
+	parseStructure    bool
+	tables            bool // Detect tables on the pages
+	barcodes          bool // Decode QR codes and barcodes on the pages
+	excludeUnreliable bool // Leave text flagged as handwriting or low quality out of the scan text
+	checkQuality      bool // Assess the image quality of the pages
+	spellCorrect      bool // Correct low-confidence words with the languages' word lists
+	accurate          bool // Use the ensemble OCR service
+	pages             [][]byte
+	regions           [][]image.Rectangle // Regions of interest of each page, in request order; nil for whole pages
+	webhookURL        string              // Per-scan webhook; empty for none
+	webhookSecret     string
+}
+
+// notifyScan sends the outcome of a scan to the owner's webhooks. Anonymous scans have no webhooks.
+func (h *Handlers) notifyScan(ctx context.Context, req *scanRequest, body *types.ScanOutputBody, err error) {
+	if req.ownerID == "" {
+		return
+	}
+	payload := types.WebhookPayload{Event: types.WebhookEventScanCompleted, ScanID: req.id.Hex(), OccurredAt: time.Now(), Result: body}
+	if err != nil {
+		payload.Event, payload.Result, payload.Failure = types.WebhookEventScanFailed, nil, scanFailure(err)
+	}
+	h.Services.WebhookService.NotifyScan(ctx, req.ownerID, req.webhookURL, req.webhookSecret, payload)
+}
+
+// scanFailure describes a failed scan with the HTTP status its error maps to.
+func scanFailure(err error) *types.ScanFailedEvent {
+	failure := &types.ScanFailedEvent{Status: http.StatusInternalServerError, Error: err.Error()}
+	var statusErr huma.StatusError
+	if errors.As(err, &statusErr) {
+		failure.Status = statusErr.GetStatus()
+	}
+	return failure
+}
+
+// runScan recognizes the pages of a scan in order, stores the result and derives the dates, structure and
+// transliteration. Progress is reported to `progress` if it is not nil.
+func (h *Handlers) runScan(ctx context.Context, req *scanRequest, progress func(types.ScanProgressEvent)) (*types.ScanOutputBody, error) {
+	if progress == nil {
+		progress = func(types.ScanProgressEvent) {}
+	}
+
+	texts := make([]string, 0, len(req.pages))
+	cached := true
+	var ensemble []types.OCREnsembleReport
+	var regions []types.ScanRegionResult
+	var tables []types.DetectedTable
+	var barcodes []types.DetectedBarcode
+	var warnings []types.ScanWarning
+	var quality []types.ImageQuality
+	var corrections []types.SpellCorrection
+	ocrService := h.Services.OCRService
+	if req.accurate {
+		ocrService = h.Services.EnsembleOCRService
+	}
+	for i, imageData := range req.pages {
+		page, pages := i+1, len(req.pages)
+		progress(types.ScanProgressEvent{Stage: types.ScanStagePage, Page: page, Pages: pages, Message: fmt.Sprintf("page %d of %d", page, pages)})
+		pageCtx := service.WithOCRProgress(ctx, func(stage string) {
+			progress(types.ScanProgressEvent{Stage: stage, Page: page, Pages: pages, Message: fmt.Sprintf("%s page %d of %d", stage, page, pages)})
+		})
+
+		if req.checkQuality {
+			// The assessment is best effort: pages in formats the decoder lacks (e.g., TIFF) are skipped.
+			pageQuality, err := h.Services.QualityService.AssessImage(imageData)
+			if err != nil {
+				log.Printf("WARNING: Quality of page %d of %d was not assessed: %v", page, pages, err)
+			} else {
+				pageQuality.Page = page
+				quality = append(quality, *pageQuality)
+			}
+		}
+
+		units, err := scanUnits(imageData, req.regions, i)
+		if err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("Failed to crop regions of page %d: %v", page, err), nil)
+		}
+		var pageTexts []string
+		for _, unit := range units {
+			// Call the OCRService with the image data, the finalized language string and the resolved options.
+			result, err := ocrService.ExtractTextFromImage(pageCtx, unit.data, req.language, req.options)
+			if err != nil {
+				log.Printf("ERROR: Failed to process image for OCR (page %d of %d): %v", page, pages, err)
+				// Timeouts (504) and client cancellations (499) already carry their Problem JSON status.
+				var statusErr huma.StatusError
+				if errors.As(err, &statusErr) {
+					return nil, err
+				}
+				return nil, huma.Error400BadRequest(fmt.Sprintf("Failed to process image for OCR: %v", err), nil)
+			}
+			cached = cached && result.Cached
+			if req.spellCorrect {
+				var unitCorrections []types.SpellCorrection
+				result, unitCorrections = h.Services.SpellService.CorrectResult(result, req.language, req.options)
+				for _, correction := range unitCorrections {
+					correction.Page = page
+					correction.Box.X, correction.Box.Y = correction.Box.X+unit.box.Min.X, correction.Box.Y+unit.box.Min.Y
+					corrections = append(corrections, correction)
+				}
+			}
+			unitText, unitWarnings := h.Services.LayoutService.FlagUnreliableText(result, req.options, req.excludeUnreliable)
+			for _, warning := range unitWarnings {
+				warning.Page = page
+				warning.Box.X, warning.Box.Y = warning.Box.X+unit.box.Min.X, warning.Box.Y+unit.box.Min.Y
+				warnings = append(warnings, warning)
+			}
+			if unit.region < 0 {
+				pageTexts = append(pageTexts, unitText)
+			} else {
+				pageTexts = append(pageTexts, strings.TrimSpace(unitText))
+				regions = append(regions, types.ScanRegionResult{
+					Page: page, Index: unit.region, Text: unitText, Confidence: result.Confidence,
+					Box: types.OCRBox{X: unit.box.Min.X, Y: unit.box.Min.Y, Width: unit.box.Dx(), Height: unit.box.Dy()},
+				})
+			}
+			if result.Ensemble != nil {
+				report := *result.Ensemble
+				report.Page = page
+				ensemble = append(ensemble, report)
+			}
+			if req.tables {
+				// Table detection is best effort: pages in formats the decoder lacks (e.g., TIFF) are skipped.
+				unitTables, err := h.Services.LayoutService.ExtractTables(unit.data, result, req.options)
+				if err != nil {
+					log.Printf("WARNING: Tables of page %d of %d were not detected: %v", page, pages, err)
+				}
+				for _, table := range unitTables {
+					table.Page = page
+					table.Box.X, table.Box.Y = table.Box.X+unit.box.Min.X, table.Box.Y+unit.box.Min.Y
+					tables = append(tables, table)
+				}
+			}
+			if req.barcodes {
+				unitBarcodes, err := h.Services.LayoutService.ReadBarcodes(unit.data)
+				if err != nil {
+					log.Printf("WARNING: Barcodes of page %d of %d were not read: %v", page, pages, err)
+				}
+				for _, barcode := range unitBarcodes {
+					barcode.Page = page
+					barcode.Box.X, barcode.Box.Y = barcode.Box.X+unit.box.Min.X, barcode.Box.Y+unit.box.Min.Y
+					barcodes = append(barcodes, barcode)
+				}
+			}
+		}
+		// Regions are separated like paragraphs.
+		texts = append(texts, strings.Join(pageTexts, "\n\n"))
+	}
+
+	log.Println("INFO: Text extracted successfully from image.")
+	// Pages are separated by a blank line, as in assembled document text.
+	text := strings.Join(texts, "\n\n")
+	body := &types.ScanOutputBody{Status: types.ScanStatusCompleted, Text: text, Cached: cached, Warnings: warnings, Ensemble: ensemble, Regions: regions, Tables: tables, Barcodes: barcodes, Quality: quality, Corrections: corrections}
+
+	// Store the result and the original images of users' scans so they can be attached to a document.
+	// Anonymous scans are not kept. A storage failure does not fail the scan itself.
+	if req.ownerID != "" {
+		record := &types.ScanRecord{ID: req.id, OwnerID: req.ownerID, Language: req.language, Preset: req.preset, Text: text}
+		if record, err := h.Services.ScanService.RecordScan(ctx, record, req.pages); err != nil {
+			log.Printf("WARNING: Scan result was not stored: %v", err)
+		} else {
+			body.ID = record.ID.Hex()
+		}
+	}
+
+	body.Dates = h.Services.TextService.ExtractDates(text)
+	if req.parseStructure {
+		structure := h.Services.TextService.ParseStructure(text)
+		body.Structure = &structure
+	}
+	if req.scheme != "" {
+		var err error
+		if body.Transliteration, err = h.Services.TextService.Transliterate(text, "", req.scheme); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// scanUnit is an image that is recognized on its own: a whole page, or a region of interest of one.
+type scanUnit struct {
+	data   []byte
+	box    image.Rectangle // Position of the image on its page
+	region int             // Index of the region in the request; -1 for a whole page
+}
+
+// scanUnits returns the images to recognize for page `page` (0-based): the page itself, or its regions
+// cropped and encoded as PNG.
+func scanUnits(imageData []byte, regions [][]image.Rectangle, page int) ([]scanUnit, error) {
+	if regions == nil {
+		return []scanUnit{{data: imageData, region: -1}}, nil
+	}
+	img, _, err := utils.DecodeImage(imageData)
+	if err != nil {
+		return nil, err
+	}
+	units := make([]scanUnit, 0, len(regions[page]))
+	for i, rect := range regions[page] {
+		data, err := utils.EncodePNG(utils.Crop(img, rect))
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, scanUnit{data: data, box: rect, region: i})
+	}
+	return units, nil
+}
+
+// parseScanRegions decodes the 'regions' form field and converts its rectangles to pixels on every page,
+// clipped to the page. It returns nil when no regions were requested.
+func parseScanRegions(value, units string, pages [][]byte, formats []string) ([][]image.Rectangle, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	var regions []types.ScanRegion
+	if err := json.Unmarshal([]byte(value), &regions); err != nil {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("regions must be a JSON array of {x, y, width, height} objects: %v", err), nil)
+	}
+	if len(regions) == 0 || len(regions) > maxScanRegions {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("regions must list between 1 and %d rectangles.", maxScanRegions), nil)
+	}
+
+	normalized := false
+	switch strings.ToLower(strings.TrimSpace(units)) {
+	case "", types.RegionUnitsPixels:
+	case types.RegionUnitsNormalized:
+		normalized = true
+	default:
+		return nil, huma.Error400BadRequest(fmt.Sprintf("region_units must be '%s' or '%s', got '%s'", types.RegionUnitsPixels, types.RegionUnitsNormalized, units), nil)
+	}
+	for i, r := range regions {
+		if r.X < 0 || r.Y < 0 || r.Width <= 0 || r.Height <= 0 {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("Region %d must have a non-negative position and a positive size.", i), nil)
+		}
+		if normalized && (r.X+r.Width > 1 || r.Y+r.Height > 1) {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("Region %d extends beyond the page; normalized coordinates are fractions from 0 to 1.", i), nil)
+		}
+	}
+
+	rects := make([][]image.Rectangle, len(pages))
+	for p, data := range pages {
+		// Regions are cropped from the decoded image, which needs a decoder for the format.
+		if formats[p] != utils.ImageFormatPNG && formats[p] != utils.ImageFormatJPEG {
+			return nil, huma.NewError(http.StatusUnsupportedMediaType,
+				fmt.Sprintf("Page %d is a %s image; regions can only be read from PNG and JPEG images.", p+1, formats[p]))
+		}
+		width, height, err := utils.ImageDimensions(data, formats[p])
+		if err != nil {
+			return nil, huma.NewError(http.StatusUnsupportedMediaType, fmt.Sprintf("Unreadable %s image: %v", formats[p], err))
+		}
+		page := image.Rect(0, 0, width, height)
+		for i, r := range regions {
+			if normalized {
+				r = types.ScanRegion{X: r.X * float64(width), Y: r.Y * float64(height), Width: r.Width * float64(width), Height: r.Height * float64(height)}
+			}
+			rect := image.Rect(int(r.X), int(r.Y), int(math.Ceil(r.X+r.Width)), int(math.Ceil(r.Y+r.Height))).Intersect(page)
+			if rect.Empty() {
+				return nil, huma.Error400BadRequest(fmt.Sprintf("Region %d lies outside page %d (%dx%d pixels).", i, p+1, width, height), nil)
+			}
+			rects[p] = append(rects[p], rect)
+		}
+	}
+	return rects, nil
+}
+
+// parseOCROptionOverrides converts the optional string form fields of a scan request into OCROptions.
+// Empty fields are left unset so the selected preset (or Tesseract default) applies.
+func parseOCROptionOverrides(formData *types.ScanFormData) (types.OCROptions, error) {
+	var opts types.OCROptions
+
+	if v := strings.TrimSpace(formData.PageSegMode); v != "" {
+		psm, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("psm must be an integer, got '%s'", v)
+		}
+		opts.PageSegMode = &psm
+	}
+	if v := strings.TrimSpace(formData.EngineMode); v != "" {
+		oem, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("oem must be an integer, got '%s'", v)
+		}
+		opts.EngineMode = &oem
+	}
+	var err error
+	if opts.PreserveInterwordSpaces, err = parseOptionalBool("preserve_spaces", formData.PreserveInterwordSpaces); err != nil {
+		return opts, err
+	}
+	if opts.PostProcess.NormalizeUnicode, err = parseOptionalBool("normalize", formData.NormalizeUnicode); err != nil {
+		return opts, err
+	}
+	if opts.PostProcess.RepairLineBreaks, err = parseOptionalBool("repair_lines", formData.RepairLineBreaks); err != nil {
+		return opts, err
+	}
+	if opts.PostProcess.FixConfusions, err = parseOptionalBool("fix_confusions", formData.FixConfusions); err != nil {
+		return opts, err
+	}
+	opts.PostProcess.Digits = strings.ToLower(strings.TrimSpace(formData.Digits))
+
+	opts.Engine = strings.TrimSpace(formData.Engine)
+	opts.CharWhitelist = formData.CharWhitelist
+	opts.CharBlacklist = formData.CharBlacklist
+	opts.UserWords = splitLines(formData.UserWords)
+	opts.UserPatterns = splitLines(formData.UserPatterns)
+	return opts, nil
+}
+
+// parseOptionalBool parses a 'true'/'false' form value; an empty value yields nil ("not set").
+func parseOptionalBool(field, value string) (*bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be 'true' or 'false', got '%s'", field, value)
+	}
+	return &parsed, nil
+}
+
+// splitLines splits a multi-line form value into trimmed, non-empty lines.
+func splitLines(value string) []string {
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
 	}
-	sort.Strings(codes) // Sort the codes alphabetically
-	return codes
+	return lines
 }
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/middleware" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+)
+
+// RegisterSearchHandlers registers the full-text search endpoint.
+func (h *Handlers) RegisterSearchHandlers(api huma.API) {
+	// GET /search: Finds stored scans and documents containing all query words, phrases and prefixes.
+	huma.Get(api, "/search", func(ctx context.Context, input *types.SearchInput) (*types.SearchOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received search request from user %s: %q", claims.UserID, input.Query)
+
+		results, err := h.Services.SearchService.Search(ctx, claims, input)
+		if err != nil {
+			log.Printf("ERROR: Search %q failed: %v", input.Query, err)
+			return nil, err
+		}
+		return results, nil
+	}, h.requireAuth(api))
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// RegisterTextHandlers registers API endpoints that operate on plain text rather than images.
+func (h *Handlers) RegisterTextHandlers(api huma.API) {
+	// POST /text/transliterate: Converts text between Devanagari and Latin romanizations.
+	huma.Post(api, "/text/transliterate", func(ctx context.Context, input *types.TransliterateInput) (*types.TransliterateOutput, error) {
+		log.Printf("INFO: Received request to transliterate text (%s → %s).", input.Body.From, input.Body.To)
+
+		text, err := h.Services.TextService.Transliterate(input.Body.Text, input.Body.From, input.Body.To)
+		if err != nil {
+			log.Printf("ERROR: Failed to transliterate text: %v", err)
+			return nil, err
+		}
+
+		resp := &types.TransliterateOutput{}
+		resp.Body.Text = text
+		resp.Body.From = input.Body.From
+		resp.Body.To = input.Body.To
+		return resp, nil
+	})
+}
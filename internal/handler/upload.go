@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/middleware" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils"      // Adjust import path to your module
+)
+
+// uploadFormOverhead is the allowance for multipart boundaries and non-file form fields
+// (e.g., user words) on top of the maximum image size.
+const uploadFormOverhead = 1 << 20
+
+// limitUpload returns an operation handler for image upload endpoints. It caps the request body at
+// maxBytes (plus form overhead) and documents the 413 and 415 errors. Each image is checked against
+// the maximum upload size by readImageUpload.
+func (h *Handlers) limitUpload(api huma.API, maxBytes int64) func(o *huma.Operation) {
+	return func(o *huma.Operation) {
+		// Prepend, so the body is limited before any middleware wraps the context.
+		limit := middleware.LimitUploadSize(api, maxBytes+uploadFormOverhead)
+		o.Middlewares = append(huma.Middlewares{limit}, o.Middlewares...)
+		o.Errors = append(o.Errors, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType)
+	}
+}
+
+// readImageUpload reads an uploaded image and validates it before any decoding work: at most
+// MaxUploadBytes long, a PNG/JPEG/TIFF/WebP/BMP file by its magic bytes (whatever content type
+// was declared), and within the configured pixel limits to guard against decompression bombs.
+// It returns the image data and its detected format.
+func (h *Handlers) readImageUpload(file huma.FormFile) ([]byte, string, error) {
+	maxBytes := h.AppConfig.MaxUploadBytes
+	if file.Size > maxBytes {
+		return nil, "", huma.NewError(http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("Image is %d bytes; the maximum upload size is %d bytes.", file.Size, maxBytes))
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		log.Printf("ERROR: Failed to read uploaded image file: %v", err)
+		return nil, "", huma.Error400BadRequest(fmt.Sprintf("Failed to read image file: %v", err), nil)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, "", huma.NewError(http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("Image exceeds the maximum upload size of %d bytes.", maxBytes))
+	}
+
+	format := utils.DetectImageFormat(data)
+	if format == "" {
+		log.Printf("INFO: Rejected upload '%s' (declared %s): unrecognized image format", file.Filename, file.ContentType)
+		return nil, "", huma.NewError(http.StatusUnsupportedMediaType,
+			"Unsupported image format. Upload a PNG, JPEG, TIFF, WebP or BMP image.")
+	}
+
+	width, height, err := utils.ImageDimensions(data, format)
+	if err != nil {
+		log.Printf("INFO: Rejected upload '%s': %v", file.Filename, err)
+		return nil, "", huma.NewError(http.StatusUnsupportedMediaType, fmt.Sprintf("Unreadable %s image: %v", format, err))
+	}
+	if maxSide := h.AppConfig.MaxImageSide; width > maxSide || height > maxSide {
+		return nil, "", huma.NewError(http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("Image is %dx%d pixels; each side may be at most %d pixels.", width, height, maxSide))
+	}
+	if maxPixels := h.AppConfig.MaxImagePixels; int64(width)*int64(height) > maxPixels {
+		return nil, "", huma.NewError(http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("Image is %dx%d pixels; at most %d pixels in total are allowed.", width, height, maxPixels))
+	}
+	return data, format, nil
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/middleware" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+)
+
+// RegisterWebhookHandlers registers API endpoints for managing webhooks and their delivery log.
+// All of them act on the authenticated user's own webhooks.
+func (h *Handlers) RegisterWebhookHandlers(api huma.API) {
+	// POST /webhooks: Registers a webhook notified when the user's scans finish.
+	huma.Post(api, "/webhooks", func(ctx context.Context, input *types.CreateWebhookInput) (*types.CreateWebhookOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to register webhook %s for user %s", input.Body.URL, claims.UserID)
+
+		hook, err := h.Services.WebhookService.CreateWebhook(ctx, claims, input.Body)
+		if err != nil {
+			log.Printf("ERROR: Failed to register webhook for user %s: %v", claims.UserID, err)
+			return nil, err
+		}
+		return hook, nil
+	}, h.requireAuth(api), func(o *huma.Operation) {
+		o.DefaultStatus = 201
+		o.Description = "Registers a URL that receives a signed JSON POST when one of your scans completes or fails. " +
+			"Verify the X-Niyam-Signature header: 'sha256=' + hex HMAC-SHA256 of '<X-Niyam-Timestamp>.<body>' keyed with the returned secret. " +
+			"Failed deliveries are retried with exponential backoff."
+	})
+
+	// GET /webhooks: Lists the user's webhooks.
+	huma.Get(api, "/webhooks", func(ctx context.Context, input *struct{}) (*types.WebhooksOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to list webhooks of user %s", claims.UserID)
+		return h.Services.WebhookService.ListWebhooks(ctx, claims)
+	}, h.requireAuth(api))
+
+	// DELETE /webhooks/{id}: Removes a webhook.
+	huma.Delete(api, "/webhooks/{id}", func(ctx context.Context, input *types.WebhookIDInput) (*struct{}, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to delete webhook %s", input.ID)
+
+		if err := h.Services.WebhookService.DeleteWebhook(ctx, claims, input.ID); err != nil {
+			log.Printf("ERROR: Failed to delete webhook %s: %v", input.ID, err)
+			return nil, err
+		}
+		return nil, nil
+	}, h.requireAuth(api))
+
+	// GET /webhooks/deliveries: Lists the delivery log, including every attempt.
+	huma.Get(api, "/webhooks/deliveries", func(ctx context.Context, input *types.WebhookDeliveriesInput) (*types.WebhookDeliveriesOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to list webhook deliveries of user %s (scan: %q)", claims.UserID, input.ScanID)
+		return h.Services.WebhookService.ListDeliveries(ctx, claims, input.ScanID, input.Limit)
+	}, h.requireAuth(api))
+
+	// GET /webhooks/deliveries/{id}: Returns one delivery.
+	huma.Get(api, "/webhooks/deliveries/{id}", func(ctx context.Context, input *types.WebhookDeliveryIDInput) (*types.WebhookDeliveryOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to get webhook delivery %s", input.ID)
+		return h.Services.WebhookService.GetDelivery(ctx, claims, input.ID)
+	}, h.requireAuth(api))
+
+	// POST /webhooks/deliveries/{id}/replay: Sends a finished delivery again.
+	huma.Post(api, "/webhooks/deliveries/{id}/replay", func(ctx context.Context, input *types.WebhookDeliveryIDInput) (*types.WebhookDeliveryOutput, error) {
+		claims, _ := middleware.ClaimsFromContext(ctx)
+		log.Printf("INFO: Received request to replay webhook delivery %s", input.ID)
+
+		delivery, err := h.Services.WebhookService.ReplayDelivery(ctx, claims, input.ID)
+		if err != nil {
+			log.Printf("ERROR: Failed to replay webhook delivery %s: %v", input.ID, err)
+			return nil, err
+		}
+		return delivery, nil
+	}, h.requireAuth(api), func(o *huma.Operation) {
+		o.DefaultStatus = 202
+		o.Description = "Re-sends the same payload and delivery ID with a new timestamp and signature, with fresh retries. " +
+			"Progress is recorded in the delivery's attempts."
+	})
+}
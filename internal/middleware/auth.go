@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// claimsContextKey is the private context key under which authenticated JWT claims are stored.
+type claimsContextKey struct{}
+
+// RequireAuth returns a Huma middleware that validates the "Authorization: Bearer <JWT>" header
+// issued by UserService.GenerateToken. When `roles` are given, the token's role must be one of them.
+// On success the claims are available to handlers through ClaimsFromContext.
+func RequireAuth(api huma.API, jwtSecret string, roles ...string) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		tokenString, ok := strings.CutPrefix(ctx.Header("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(tokenString) == "" {
+			huma.WriteErr(api, ctx, 401, "missing or malformed bearer token")
+			return
+		}
+
+		claims, err := ParseToken(strings.TrimSpace(tokenString), jwtSecret)
+		if err != nil {
+			log.Printf("INFO: Rejected bearer token: %v", err)
+			huma.WriteErr(api, ctx, 401, "invalid or expired token")
+			return
+		}
+
+		if len(roles) > 0 && !hasRole(claims.Role, roles) {
+			log.Printf("INFO: User %s (role: %s) denied access to %s %s", claims.UserID, claims.Role, ctx.Method(), ctx.URL().Path)
+			huma.WriteErr(api, ctx, 403, "insufficient permissions")
+			return
+		}
+
+		next(huma.WithValue(ctx, claimsContextKey{}, claims))
+	}
+}
+
+// OptionalAuth returns a Huma middleware for endpoints that also serve anonymous callers.
+// Requests without an Authorization header pass through unchanged; a bearer token, if sent,
+// must be valid (401 otherwise) and its claims are then available through ClaimsFromContext.
+func OptionalAuth(api huma.API, jwtSecret string) func(ctx huma.Context, next func(huma.Context)) {
+	requireAuth := RequireAuth(api, jwtSecret)
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if ctx.Header("Authorization") == "" {
+			next(ctx)
+			return
+		}
+		requireAuth(ctx, next)
+	}
+}
+
+// ParseToken verifies a JWT signed with `jwtSecret` and returns its claims.
+func ParseToken(tokenString, jwtSecret string) (*types.AuthClaims, error) {
+	claims := &types.AuthClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer("niyam-api"),
+		jwt.WithAudience("users"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	return claims, nil
+}
+
+// ClaimsFromContext returns the JWT claims stored by RequireAuth, if any.
+func ClaimsFromContext(ctx context.Context) (*types.AuthClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*types.AuthClaims)
+	return claims, ok
+}
+
+// hasRole reports whether `role` is one of the allowed roles.
+func hasRole(role string, allowed []string) bool {
+	for _, r := range allowed {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
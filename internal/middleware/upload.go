@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+)
+
+// requestUnwrapper is implemented by the router adapters' contexts (humachi, and humaflow used by humatest).
+// Contexts wrapped by huma.WithValue do not implement it, so LimitUploadSize must run before such middlewares.
+type requestUnwrapper interface {
+	Unwrap() (*http.Request, http.ResponseWriter)
+}
+
+// LimitUploadSize returns a Huma middleware that rejects request bodies larger than maxBytes with
+// 413 Problem JSON. A declared Content-Length is checked up front; otherwise the limit is enforced
+// while the body streams in, by parsing multipart forms here before Huma reads them.
+// It must be the first middleware of the operation.
+func LimitUploadSize(api huma.API, maxBytes int64) func(ctx huma.Context, next func(huma.Context)) {
+	tooLarge := fmt.Sprintf("request body exceeds the maximum upload size of %d bytes", maxBytes)
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if length, err := strconv.ParseInt(ctx.Header("Content-Length"), 10, 64); err == nil && length > maxBytes {
+			log.Printf("INFO: Rejected upload of %d bytes to %s (limit %d)", length, ctx.URL().Path, maxBytes)
+			huma.WriteErr(api, ctx, http.StatusRequestEntityTooLarge, tooLarge)
+			return
+		}
+
+		unwrapper, ok := ctx.(requestUnwrapper)
+		if !ok {
+			log.Printf("WARNING: Cannot limit the request body of %s; the upload limit middleware must run first", ctx.URL().Path)
+			next(ctx)
+			return
+		}
+		r, w := unwrapper.Unwrap()
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+		// Huma reuses an already parsed form, so parsing it here lets the size error become a 413
+		// instead of a generic form validation error.
+		if strings.HasPrefix(ctx.Header("Content-Type"), "multipart/form-data") {
+			var maxBytesErr *http.MaxBytesError
+			if err := r.ParseMultipartForm(humachi.MultipartMaxMemory); errors.As(err, &maxBytesErr) {
+				log.Printf("INFO: Rejected streamed upload to %s exceeding %d bytes", ctx.URL().Path, maxBytes)
+				huma.WriteErr(api, ctx, http.StatusRequestEntityTooLarge, tooLarge)
+				return
+			}
+		}
+		next(ctx)
+	}
+}
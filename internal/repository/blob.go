@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+// BlobStore defines the interface for storing binary objects such as uploaded images.
+// Blobs are content-addressed: the key is the hex SHA-256 of the data, so storing the
+// same bytes twice keeps a single copy.
+type BlobStore interface {
+	// Put stores data (unless a blob with the same content exists) and returns its key.
+	Put(ctx context.Context, data []byte) (string, error)
+	// Get returns the blob with the given key, or an error "blob not found".
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Exists reports whether a blob with the given key is stored.
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete removes a blob; deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// blobKeyPattern matches valid blob keys (lowercase hex SHA-256).
+var blobKeyPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// BlobKey returns the content address of data.
+func BlobKey(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// checkBlobKey rejects keys that are not SHA-256 hex strings (and could, e.g., escape the blob directory).
+func checkBlobKey(key string) error {
+	if !blobKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// gridFSBlobStore implements BlobStore with MongoDB GridFS. The blob key is the GridFS filename.
+type gridFSBlobStore struct {
+	bucket *gridfs.Bucket
+}
+
+// NewGridFSBlobStore creates a GridFS blob store in the "blobs" bucket of db.
+func NewGridFSBlobStore(db *mongo.Database) (BlobStore, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName("blobs"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GridFS bucket: %w", err)
+	}
+	return &gridFSBlobStore{bucket: bucket}, nil
+}
+
+// Put uploads the blob unless a file with the same key exists. Two concurrent uploads of
+// new, identical content may both be stored; they are interchangeable and both are removed by Delete.
+func (s *gridFSBlobStore) Put(ctx context.Context, data []byte) (string, error) {
+	key := BlobKey(data)
+	exists, err := s.Exists(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return key, nil
+	}
+
+	// GridFS streams take deadlines rather than contexts; ctx's deadline is applied to this stream only,
+	// since the bucket is shared by concurrent requests.
+	stream, err := s.bucket.OpenUploadStream(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload blob to GridFS: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		stream.SetWriteDeadline(deadline)
+	}
+	if _, err := stream.Write(data); err != nil {
+		stream.Abort()
+		return "", fmt.Errorf("failed to upload blob to GridFS: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		stream.Abort()
+		return "", err
+	}
+	if err := stream.Close(); err != nil {
+		return "", fmt.Errorf("failed to upload blob to GridFS: %w", err)
+	}
+	return key, nil
+}
+
+// Get downloads a blob from GridFS.
+func (s *gridFSBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := checkBlobKey(key); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	stream, err := s.bucket.OpenDownloadStreamByName(key)
+	if err != nil {
+		if err == gridfs.ErrFileNotFound {
+			return nil, fmt.Errorf("blob not found")
+		}
+		return nil, fmt.Errorf("failed to download blob from GridFS: %w", err)
+	}
+	defer stream.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		stream.SetReadDeadline(deadline)
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(stream); err != nil {
+		return nil, fmt.Errorf("failed to download blob from GridFS: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Exists reports whether a GridFS file with the key as filename exists.
+func (s *gridFSBlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	if err := checkBlobKey(key); err != nil {
+		return false, err
+	}
+	count, err := s.bucket.GetFilesCollection().CountDocuments(ctx, bson.M{"filename": key}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check blob in GridFS: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Delete removes every GridFS file stored under the key.
+func (s *gridFSBlobStore) Delete(ctx context.Context, key string) error {
+	if err := checkBlobKey(key); err != nil {
+		return err
+	}
+	cursor, err := s.bucket.FindContext(ctx, bson.M{"filename": key})
+	if err != nil {
+		return fmt.Errorf("failed to find blob in GridFS: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var files []gridfs.File
+	if err := cursor.All(ctx, &files); err != nil {
+		return fmt.Errorf("failed to decode GridFS files: %w", err)
+	}
+	for _, file := range files {
+		if err := s.bucket.DeleteContext(ctx, file.ID); err != nil && err != gridfs.ErrFileNotFound {
+			return fmt.Errorf("failed to delete blob from GridFS: %w", err)
+		}
+	}
+	return nil
+}
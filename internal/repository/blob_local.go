@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// localBlobStore implements BlobStore on the local filesystem. Blobs are stored as
+// <dir>/<first two hex digits>/<key> to keep directories small.
+type localBlobStore struct {
+	dir string
+}
+
+// NewLocalBlobStore creates a filesystem blob store rooted at dir, creating the directory if needed.
+func NewLocalBlobStore(dir string) (BlobStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory '%s': %w", dir, err)
+	}
+	return &localBlobStore{dir: dir}, nil
+}
+
+// path returns the file path of a blob.
+func (s *localBlobStore) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key)
+}
+
+// Put writes the blob through a temporary file and renames it into place,
+// so readers never see a partially written blob.
+func (s *localBlobStore) Put(ctx context.Context, data []byte) (string, error) {
+	key := BlobKey(data)
+	path := s.path(key)
+	if _, err := os.Stat(path); err == nil {
+		return key, nil // Already stored.
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary blob file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename.
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to store blob: %w", err)
+	}
+	return key, nil
+}
+
+// Get reads a blob from disk.
+func (s *localBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := checkBlobKey(key); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("blob not found")
+		}
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	return data, nil
+}
+
+// Exists reports whether the blob file exists.
+func (s *localBlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	if err := checkBlobKey(key); err != nil {
+		return false, err
+	}
+	_, err := os.Stat(s.path(key))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check blob: %w", err)
+}
+
+// Delete removes the blob file.
+func (s *localBlobStore) Delete(ctx context.Context, key string) error {
+	if err := checkBlobKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
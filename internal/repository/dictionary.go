@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+)
+
+// DictionaryRepository defines the interface for custom OCR dictionary data operations.
+type DictionaryRepository interface {
+	CreateDictionary(ctx context.Context, dict *types.OCRDictionary) (*types.OCRDictionary, error)
+	GetDictionaryByDomain(ctx context.Context, domain string) (*types.OCRDictionary, error)
+	ListDictionaries(ctx context.Context) ([]types.OCRDictionary, error)
+	UpdateDictionary(ctx context.Context, dict *types.OCRDictionary) error
+	DeleteDictionary(ctx context.Context, domain string) error
+}
+
+// mongoDictionaryRepository implements DictionaryRepository for MongoDB.
+type mongoDictionaryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoDictionaryRepository creates a new MongoDB dictionary repository and ensures domains are unique.
+func NewMongoDictionaryRepository(db *mongo.Database) DictionaryRepository {
+	r := &mongoDictionaryRepository{
+		collection: db.Collection("ocr_dictionaries"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "domain", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("WARNING: Failed to create OCR dictionary domain index: %v", err)
+	}
+	return r
+}
+
+// CreateDictionary inserts a new dictionary into MongoDB.
+func (r *mongoDictionaryRepository) CreateDictionary(ctx context.Context, dict *types.OCRDictionary) (*types.OCRDictionary, error) {
+	if dict.ID.IsZero() {
+		dict.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.InsertOne(ctx, dict); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("dictionary with this domain already exists")
+		}
+		return nil, fmt.Errorf("failed to create dictionary: %w", err)
+	}
+
+	log.Printf("INFO: OCR dictionary created with ID: %s (domain: %s)", dict.ID.Hex(), dict.Domain)
+	return dict, nil
+}
+
+// GetDictionaryByDomain retrieves a dictionary by its domain name.
+func (r *mongoDictionaryRepository) GetDictionaryByDomain(ctx context.Context, domain string) (*types.OCRDictionary, error) {
+	var dict types.OCRDictionary
+	err := r.collection.FindOne(ctx, bson.M{"domain": domain}).Decode(&dict)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("dictionary not found")
+		}
+		return nil, fmt.Errorf("failed to get dictionary by domain: %w", err)
+	}
+	return &dict, nil
+}
+
+// ListDictionaries retrieves all dictionaries, sorted by domain.
+func (r *mongoDictionaryRepository) ListDictionaries(ctx context.Context) ([]types.OCRDictionary, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "domain", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dictionaries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	dicts := []types.OCRDictionary{}
+	if err := cursor.All(ctx, &dicts); err != nil {
+		return nil, fmt.Errorf("failed to decode dictionaries: %w", err)
+	}
+	return dicts, nil
+}
+
+// UpdateDictionary replaces the stored dictionary with the same domain.
+func (r *mongoDictionaryRepository) UpdateDictionary(ctx context.Context, dict *types.OCRDictionary) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"domain": dict.Domain}, dict)
+	if err != nil {
+		return fmt.Errorf("failed to update dictionary: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("dictionary not found")
+	}
+	return nil
+}
+
+// DeleteDictionary removes the dictionary with the given domain.
+func (r *mongoDictionaryRepository) DeleteDictionary(ctx context.Context, domain string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"domain": domain})
+	if err != nil {
+		return fmt.Errorf("failed to delete dictionary: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("dictionary not found")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+)
+
+// DocumentRepository defines the interface for document data operations.
+type DocumentRepository interface {
+	CreateDocument(ctx context.Context, doc *types.Document) (*types.Document, error)
+	GetDocumentByID(ctx context.Context, id primitive.ObjectID) (*types.Document, error)
+	// ListDocuments returns documents owned by `ownerID` (all documents when empty), newest first.
+	ListDocuments(ctx context.Context, ownerID string) ([]types.Document, error)
+	// UpdateDocument replaces the document only if its stored version is still `expectedVersion`.
+	UpdateDocument(ctx context.Context, doc *types.Document, expectedVersion int) error
+}
+
+// mongoDocumentRepository implements DocumentRepository for MongoDB.
+type mongoDocumentRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoDocumentRepository creates a new MongoDB document repository.
+func NewMongoDocumentRepository(db *mongo.Database) DocumentRepository {
+	return &mongoDocumentRepository{
+		collection: db.Collection("documents"),
+	}
+}
+
+// CreateDocument inserts a new document into MongoDB.
+func (r *mongoDocumentRepository) CreateDocument(ctx context.Context, doc *types.Document) (*types.Document, error) {
+	if doc.ID.IsZero() {
+		doc.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.InsertOne(ctx, doc); err != nil {
+		return nil, fmt.Errorf("failed to create document: %w", err)
+	}
+
+	log.Printf("INFO: Document created with ID: %s", doc.ID.Hex())
+	return doc, nil
+}
+
+// GetDocumentByID retrieves a document by its MongoDB ObjectID.
+func (r *mongoDocumentRepository) GetDocumentByID(ctx context.Context, id primitive.ObjectID) (*types.Document, error) {
+	var doc types.Document
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("document not found")
+		}
+		return nil, fmt.Errorf("failed to get document by ID: %w", err)
+	}
+	return &doc, nil
+}
+
+// ListDocuments retrieves documents, optionally filtered by owner, newest first.
+func (r *mongoDocumentRepository) ListDocuments(ctx context.Context, ownerID string) ([]types.Document, error) {
+	filter := bson.M{}
+	if ownerID != "" {
+		filter["owner_id"] = ownerID
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	docs := []types.Document{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
+	return docs, nil
+}
+
+// UpdateDocument replaces the stored document, guarding against concurrent page changes.
+func (r *mongoDocumentRepository) UpdateDocument(ctx context.Context, doc *types.Document, expectedVersion int) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": doc.ID, "version": expectedVersion}, doc)
+	if err != nil {
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("document version conflict")
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+)
+
+// OCRCache defines the interface for a store of OCR results keyed by an opaque cache key
+// (see service.OCRCacheKey). Entries expire after the TTL given when they were stored.
+type OCRCache interface {
+	// Get returns the cached result for key; found is false on a miss or an expired entry.
+	Get(ctx context.Context, key string) (result *types.OCRResult, found bool, err error)
+	// Set stores result under key for ttl, replacing any previous entry.
+	Set(ctx context.Context, key string, result *types.OCRResult, ttl time.Duration) error
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+)
+
+// memoryOCRCache implements OCRCache as an in-process LRU with a fixed number of entries.
+type memoryOCRCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List               // Most recently used at the front
+	entries    map[string]*list.Element // Values are *memoryOCRCacheEntry
+}
+
+// memoryOCRCacheEntry is an element of the LRU list.
+type memoryOCRCacheEntry struct {
+	key       string
+	result    types.OCRResult
+	expiresAt time.Time
+}
+
+// NewMemoryOCRCache creates an in-process LRU cache holding at most maxEntries results.
+func NewMemoryOCRCache(maxEntries int) OCRCache {
+	return &memoryOCRCache{
+		maxEntries: max(maxEntries, 1),
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns a copy of the cached result and marks it as recently used.
+func (c *memoryOCRCache) Get(ctx context.Context, key string) (*types.OCRResult, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := elem.Value.(*memoryOCRCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	result := entry.result
+	return &result, true, nil
+}
+
+// Set stores a copy of result, evicting the least recently used entries when full.
+func (c *memoryOCRCache) Set(ctx context.Context, key string, result *types.OCRResult, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &memoryOCRCacheEntry{key: key, result: *result, expiresAt: time.Now().Add(ttl)}
+	entry.result.Cached = false
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryOCRCacheEntry).key)
+	}
+	return nil
+}
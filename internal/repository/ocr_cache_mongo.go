@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+)
+
+// mongoOCRCache implements OCRCache on a MongoDB collection. A TTL index removes expired
+// entries; since MongoDB only purges them periodically, Get also checks the expiry itself.
+type mongoOCRCache struct {
+	collection *mongo.Collection
+}
+
+// mongoOCRCacheEntry is the stored form of a cached result.
+type mongoOCRCacheEntry struct {
+	Key       string          `bson:"_id"`
+	Result    types.OCRResult `bson:"result"`
+	CreatedAt time.Time       `bson:"created_at"`
+	ExpiresAt time.Time       `bson:"expires_at"`
+}
+
+// NewMongoOCRCache creates a MongoDB-backed OCR result cache and ensures its TTL index exists.
+func NewMongoOCRCache(db *mongo.Database) OCRCache {
+	c := &mongoOCRCache{collection: db.Collection("ocr_cache")}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := c.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		log.Printf("WARNING: Failed to create OCR cache TTL index: %v", err)
+	}
+	return c
+}
+
+// Get looks up an unexpired entry.
+func (c *mongoOCRCache) Get(ctx context.Context, key string) (*types.OCRResult, bool, error) {
+	var entry mongoOCRCacheEntry
+	err := c.collection.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read OCR cache: %w", err)
+	}
+	return &entry.Result, true, nil
+}
+
+// Set upserts an entry.
+func (c *mongoOCRCache) Set(ctx context.Context, key string, result *types.OCRResult, ttl time.Duration) error {
+	now := time.Now()
+	entry := mongoOCRCacheEntry{Key: key, Result: *result, CreatedAt: now, ExpiresAt: now.Add(ttl)}
+	if _, err := c.collection.ReplaceOne(ctx, bson.M{"_id": key}, entry, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to write OCR cache: %w", err)
+	}
+	return nil
+}
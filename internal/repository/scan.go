@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+)
+
+// ScanRepository defines the interface for stored scan data operations.
+type ScanRepository interface {
+	CreateScan(ctx context.Context, scan *types.ScanRecord) (*types.ScanRecord, error)
+	GetScanByID(ctx context.Context, id primitive.ObjectID) (*types.ScanRecord, error)
+	// GetScansByIDs returns the scans that exist among `ids`, keyed by ID; missing IDs are simply absent.
+	GetScansByIDs(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]*types.ScanRecord, error)
+}
+
+// mongoScanRepository implements ScanRepository for MongoDB.
+type mongoScanRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoScanRepository creates a new MongoDB scan repository.
+func NewMongoScanRepository(db *mongo.Database) ScanRepository {
+	return &mongoScanRepository{
+		collection: db.Collection("scans"),
+	}
+}
+
+// CreateScan inserts a new scan into MongoDB.
+func (r *mongoScanRepository) CreateScan(ctx context.Context, scan *types.ScanRecord) (*types.ScanRecord, error) {
+	if scan.ID.IsZero() {
+		scan.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.InsertOne(ctx, scan); err != nil {
+		return nil, fmt.Errorf("failed to create scan: %w", err)
+	}
+
+	log.Printf("INFO: Scan stored with ID: %s", scan.ID.Hex())
+	return scan, nil
+}
+
+// GetScanByID retrieves a scan by its MongoDB ObjectID.
+func (r *mongoScanRepository) GetScanByID(ctx context.Context, id primitive.ObjectID) (*types.ScanRecord, error) {
+	var scan types.ScanRecord
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&scan)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("scan not found")
+		}
+		return nil, fmt.Errorf("failed to get scan by ID: %w", err)
+	}
+	return &scan, nil
+}
+
+// GetScansByIDs retrieves all scans whose ID is in `ids`.
+func (r *mongoScanRepository) GetScansByIDs(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]*types.ScanRecord, error) {
+	scans := make(map[primitive.ObjectID]*types.ScanRecord, len(ids))
+	if len(ids) == 0 {
+		return scans, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scans by IDs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var scan types.ScanRecord
+		if err := cursor.Decode(&scan); err != nil {
+			return nil, fmt.Errorf("failed to decode scan: %w", err)
+		}
+		scans[scan.ID] = &scan
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read scans: %w", err)
+	}
+	return scans, nil
+}
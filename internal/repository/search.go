@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path
+)
+
+// SearchIndex defines the interface of a full-text index over scans and documents.
+// Implementations tokenize with utils.Tokenize, so Devanagari words, digits and spelling
+// variants match the same way in every backend.
+type SearchIndex interface {
+	// IndexItem adds an item, replacing any earlier entry with the same kind and ID.
+	IndexItem(ctx context.Context, item *types.SearchItem) error
+	// RemoveItem deletes an item from the index; removing a missing item is not an error.
+	RemoveItem(ctx context.Context, kind, itemID string) error
+	// Search returns the page of hits selected by filter.Limit/Offset, best first, and the total number of hits.
+	Search(ctx context.Context, query utils.SearchQuery, filter types.SearchFilter) ([]types.SearchHit, int, error)
+}
+
+// searchKey identifies an item in the index.
+func searchKey(kind, itemID string) string {
+	return kind + ":" + itemID
+}
+
+// searchLanguages splits a Tesseract language string ("eng+nep") into its codes.
+func searchLanguages(language string) []string {
+	return strings.FieldsFunc(language, func(r rune) bool { return r == '+' || r == ',' })
+}
+
+// indexTerms returns the terms indexed for an item: its title followed by its text.
+func indexTerms(item *types.SearchItem) []string {
+	return utils.Terms(item.Title + "\n" + item.Text)
+}
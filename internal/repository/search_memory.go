@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path
+)
+
+// memorySearchIndex is an in-process inverted index. It is not persisted, which makes it
+// suitable for tests and single-instance development setups.
+type memorySearchIndex struct {
+	mu       sync.RWMutex
+	items    map[string]*memorySearchEntry
+	postings map[string]map[string]struct{} // term → keys of items containing it
+}
+
+// memorySearchEntry is an indexed item with its terms in text order (for phrase matching).
+type memorySearchEntry struct {
+	item  types.SearchItem
+	terms []string
+}
+
+// NewMemorySearchIndex creates an empty in-process search index.
+func NewMemorySearchIndex() SearchIndex {
+	return &memorySearchIndex{
+		items:    make(map[string]*memorySearchEntry),
+		postings: make(map[string]map[string]struct{}),
+	}
+}
+
+// IndexItem adds or replaces an item.
+func (idx *memorySearchIndex) IndexItem(ctx context.Context, item *types.SearchItem) error {
+	key := searchKey(item.Kind, item.ItemID)
+	entry := &memorySearchEntry{item: *item, terms: indexTerms(item)}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(key)
+	idx.items[key] = entry
+	for _, term := range entry.terms {
+		keys, ok := idx.postings[term]
+		if !ok {
+			keys = make(map[string]struct{})
+			idx.postings[term] = keys
+		}
+		keys[key] = struct{}{}
+	}
+	return nil
+}
+
+// RemoveItem deletes an item, if present.
+func (idx *memorySearchIndex) RemoveItem(ctx context.Context, kind, itemID string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(searchKey(kind, itemID))
+	return nil
+}
+
+// removeLocked deletes an item and its postings. The caller must hold the write lock.
+func (idx *memorySearchIndex) removeLocked(key string) {
+	entry, ok := idx.items[key]
+	if !ok {
+		return
+	}
+	for _, term := range entry.terms {
+		if keys, ok := idx.postings[term]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+	delete(idx.items, key)
+}
+
+// Search finds items containing every word, phrase and prefix of the query.
+// The score is the number of matching term occurrences, with phrase occurrences counting double.
+func (idx *memorySearchIndex) Search(ctx context.Context, query utils.SearchQuery, filter types.SearchFilter) ([]types.SearchHit, int, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var hits []types.SearchHit
+	for _, key := range idx.candidates(query.AllWords()) {
+		entry := idx.items[key]
+		if !matchesSearchFilter(&entry.item, filter) {
+			continue
+		}
+		if score, ok := scoreEntry(entry, query); ok {
+			hits = append(hits, types.SearchHit{Item: entry.item, Score: score})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Item.CreatedAt.After(hits[j].Item.CreatedAt)
+	})
+
+	total := len(hits)
+	start := min(filter.Offset, total)
+	end := total
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, total)
+	}
+	return hits[start:end], total, nil
+}
+
+// candidates returns the keys of items containing all `words` (every item when there are none).
+func (idx *memorySearchIndex) candidates(words []string) []string {
+	var keys []string
+	if len(words) == 0 {
+		for key := range idx.items {
+			keys = append(keys, key)
+		}
+		return keys
+	}
+
+	// Start from the rarest word to keep the intersection small.
+	sorted := slices.Clone(words)
+	sort.Slice(sorted, func(i, j int) bool { return len(idx.postings[sorted[i]]) < len(idx.postings[sorted[j]]) })
+	for key := range idx.postings[sorted[0]] {
+		found := true
+		for _, word := range sorted[1:] {
+			if _, ok := idx.postings[word][key]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// scoreEntry checks phrases and prefixes against an entry that contains all query words, and scores it.
+func scoreEntry(entry *memorySearchEntry, query utils.SearchQuery) (float64, bool) {
+	score := 0.0
+	for _, term := range entry.terms {
+		if slices.Contains(query.Words, term) {
+			score++
+		}
+	}
+	for _, phrase := range query.Phrases {
+		count := 0
+		for i := 0; i+len(phrase) <= len(entry.terms); i++ {
+			if slices.Equal(entry.terms[i:i+len(phrase)], phrase) {
+				count++
+			}
+		}
+		if count == 0 {
+			return 0, false
+		}
+		score += 2 * float64(count)
+	}
+	for _, prefix := range query.Prefixes {
+		count := 0
+		for _, term := range entry.terms {
+			if strings.HasPrefix(term, prefix) {
+				count++
+			}
+		}
+		if count == 0 {
+			return 0, false
+		}
+		score += float64(count)
+	}
+	return score, true
+}
+
+// matchesSearchFilter reports whether an item passes the owner, language, kind and date filters.
+func matchesSearchFilter(item *types.SearchItem, filter types.SearchFilter) bool {
+	switch {
+	case filter.OwnerID != "" && item.OwnerID != filter.OwnerID:
+		return false
+	case filter.Kind != "" && item.Kind != filter.Kind:
+		return false
+	case filter.Language != "" && !slices.Contains(searchLanguages(item.Language), filter.Language):
+		return false
+	case !filter.From.IsZero() && item.CreatedAt.Before(filter.From):
+		return false
+	case !filter.To.IsZero() && !item.CreatedAt.Before(filter.To):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path
+)
+
+// mongoSearchIndex implements SearchIndex on a MongoDB collection with a text index.
+// MongoDB's own tokenizer knows nothing about Devanagari, so each entry stores its terms
+// already normalized by utils.Tokenize: `norm_text` (space-separated, text-indexed) for
+// candidate retrieval and ranking, and `terms` for exact word, phrase and prefix checks.
+type mongoSearchIndex struct {
+	collection *mongo.Collection
+}
+
+// mongoSearchEntry is the stored form of a SearchItem.
+type mongoSearchEntry struct {
+	Key       string    `bson:"_id"`
+	Kind      string    `bson:"kind"`
+	ItemID    string    `bson:"item_id"`
+	OwnerID   string    `bson:"owner_id"`
+	Language  string    `bson:"language"`
+	Languages []string  `bson:"languages"`
+	Title     string    `bson:"title"`
+	Text      string    `bson:"text"`
+	NormText  string    `bson:"norm_text"`
+	Terms     []string  `bson:"terms"`
+	CreatedAt time.Time `bson:"created_at"`
+	Score     float64   `bson:"score,omitempty"` // Text score, only set in search results
+}
+
+// NewMongoSearchIndex creates a MongoDB search index and ensures its collection indexes exist.
+// Index creation failures are logged; searches still work, only more slowly.
+func NewMongoSearchIndex(db *mongo.Database) SearchIndex {
+	idx := &mongoSearchIndex{collection: db.Collection("search_index")}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := idx.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		// Terms are pre-normalized, so disable language-specific stemming and stop words.
+		{Keys: bson.D{{Key: "norm_text", Value: "text"}}, Options: options.Index().SetName("norm_text_text").SetDefaultLanguage("none")},
+		{Keys: bson.D{{Key: "terms", Value: 1}}},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("WARNING: Failed to create search index collection indexes: %v", err)
+	}
+	return idx
+}
+
+// IndexItem upserts an item.
+func (idx *mongoSearchIndex) IndexItem(ctx context.Context, item *types.SearchItem) error {
+	terms := indexTerms(item)
+	entry := mongoSearchEntry{
+		Key:       searchKey(item.Kind, item.ItemID),
+		Kind:      item.Kind,
+		ItemID:    item.ItemID,
+		OwnerID:   item.OwnerID,
+		Language:  item.Language,
+		Languages: searchLanguages(item.Language),
+		Title:     item.Title,
+		Text:      item.Text,
+		NormText:  strings.Join(terms, " "),
+		Terms:     terms,
+		CreatedAt: item.CreatedAt,
+	}
+
+	_, err := idx.collection.ReplaceOne(ctx, bson.M{"_id": entry.Key}, entry, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to index %s %s: %w", item.Kind, item.ItemID, err)
+	}
+	return nil
+}
+
+// RemoveItem deletes an item, if present.
+func (idx *mongoSearchIndex) RemoveItem(ctx context.Context, kind, itemID string) error {
+	if _, err := idx.collection.DeleteOne(ctx, bson.M{"_id": searchKey(kind, itemID)}); err != nil {
+		return fmt.Errorf("failed to remove %s %s from search index: %w", kind, itemID, err)
+	}
+	return nil
+}
+
+// Search finds items matching every part of the query, ranked by MongoDB's text score
+// (or newest first for prefix-only queries, which the text index cannot serve).
+func (idx *mongoSearchIndex) Search(ctx context.Context, query utils.SearchQuery, filter types.SearchFilter) ([]types.SearchHit, int, error) {
+	conditions := bson.A{}
+
+	useText := len(query.Words) > 0 || len(query.Phrases) > 0
+	if useText {
+		// Quoted terms are required by $text; the $all and regex conditions below make
+		// every word and phrase mandatory regardless of how $text combines them.
+		var search []string
+		for _, word := range query.Words {
+			search = append(search, `"`+word+`"`)
+		}
+		for _, phrase := range query.Phrases {
+			search = append(search, `"`+strings.Join(phrase, " ")+`"`)
+		}
+		conditions = append(conditions, bson.M{"$text": bson.M{"$search": strings.Join(search, " "), "$language": "none"}})
+		conditions = append(conditions, bson.M{"terms": bson.M{"$all": query.AllWords()}})
+	}
+	for _, phrase := range query.Phrases {
+		pattern := `(^| )` + regexp.QuoteMeta(strings.Join(phrase, " ")) + `( |$)`
+		conditions = append(conditions, bson.M{"norm_text": bson.M{"$regex": pattern}})
+	}
+	for _, prefix := range query.Prefixes {
+		conditions = append(conditions, bson.M{"terms": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}})
+	}
+
+	if filter.OwnerID != "" {
+		conditions = append(conditions, bson.M{"owner_id": filter.OwnerID})
+	}
+	if filter.Kind != "" {
+		conditions = append(conditions, bson.M{"kind": filter.Kind})
+	}
+	if filter.Language != "" {
+		conditions = append(conditions, bson.M{"languages": filter.Language})
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$gte": filter.From}})
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$lt": filter.To}})
+	}
+
+	filterDoc := bson.M{}
+	if len(conditions) > 0 {
+		filterDoc = bson.M{"$and": conditions}
+	}
+
+	total, err := idx.collection.CountDocuments(ctx, filterDoc)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	opts := options.Find().SetSkip(int64(filter.Offset)).SetProjection(bson.M{"norm_text": 0, "terms": 0})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	if useText {
+		opts.SetProjection(bson.M{"norm_text": 0, "terms": 0, "score": bson.M{"$meta": "textScore"}})
+		opts.SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "created_at", Value: -1}})
+	} else {
+		opts.SetSort(bson.D{{Key: "created_at", Value: -1}})
+	}
+
+	cursor, err := idx.collection.Find(ctx, filterDoc, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []mongoSearchEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode search results: %w", err)
+	}
+
+	hits := make([]types.SearchHit, 0, len(entries))
+	for _, entry := range entries {
+		hits = append(hits, types.SearchHit{
+			Item: types.SearchItem{
+				Kind:      entry.Kind,
+				ItemID:    entry.ItemID,
+				OwnerID:   entry.OwnerID,
+				Language:  entry.Language,
+				Title:     entry.Title,
+				Text:      entry.Text,
+				CreatedAt: entry.CreatedAt,
+			},
+			Score: entry.Score,
+		})
+	}
+	return hits, int(total), nil
+}
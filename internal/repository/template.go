@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+)
+
+// TemplateRepository defines the interface for extraction template data operations.
+type TemplateRepository interface {
+	CreateTemplate(ctx context.Context, tmpl *types.ExtractionTemplate) (*types.ExtractionTemplate, error)
+	GetTemplateByName(ctx context.Context, name string) (*types.ExtractionTemplate, error)
+	ListTemplates(ctx context.Context) ([]types.ExtractionTemplate, error)
+	UpdateTemplate(ctx context.Context, tmpl *types.ExtractionTemplate) error
+	DeleteTemplate(ctx context.Context, name string) error
+}
+
+// mongoTemplateRepository implements TemplateRepository for MongoDB.
+type mongoTemplateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoTemplateRepository creates a new MongoDB template repository and ensures names are unique.
+func NewMongoTemplateRepository(db *mongo.Database) TemplateRepository {
+	r := &mongoTemplateRepository{
+		collection: db.Collection("extraction_templates"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("WARNING: Failed to create extraction template name index: %v", err)
+	}
+	return r
+}
+
+// CreateTemplate inserts a new template into MongoDB.
+func (r *mongoTemplateRepository) CreateTemplate(ctx context.Context, tmpl *types.ExtractionTemplate) (*types.ExtractionTemplate, error) {
+	if tmpl.ID.IsZero() {
+		tmpl.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.collection.InsertOne(ctx, tmpl); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("template with this name already exists")
+		}
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	log.Printf("INFO: Extraction template created with ID: %s (name: %s)", tmpl.ID.Hex(), tmpl.Name)
+	return tmpl, nil
+}
+
+// GetTemplateByName retrieves a template by its name.
+func (r *mongoTemplateRepository) GetTemplateByName(ctx context.Context, name string) (*types.ExtractionTemplate, error) {
+	var tmpl types.ExtractionTemplate
+	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&tmpl)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("template not found")
+		}
+		return nil, fmt.Errorf("failed to get template by name: %w", err)
+	}
+	return &tmpl, nil
+}
+
+// ListTemplates retrieves all templates, sorted by name.
+func (r *mongoTemplateRepository) ListTemplates(ctx context.Context) ([]types.ExtractionTemplate, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	templates := []types.ExtractionTemplate{}
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode templates: %w", err)
+	}
+	return templates, nil
+}
+
+// UpdateTemplate replaces the stored template with the same name.
+func (r *mongoTemplateRepository) UpdateTemplate(ctx context.Context, tmpl *types.ExtractionTemplate) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"name": tmpl.Name}, tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("template not found")
+	}
+	return nil
+}
+
+// DeleteTemplate removes the template with the given name.
+func (r *mongoTemplateRepository) DeleteTemplate(ctx context.Context, name string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("template not found")
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path
+)
+
+// WebhookRepository defines the interface for webhook registrations and their delivery log.
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, hook *types.Webhook) (*types.Webhook, error)
+	GetWebhookByID(ctx context.Context, id primitive.ObjectID) (*types.Webhook, error)
+	// ListWebhooks returns the webhooks of an owner, newest first.
+	ListWebhooks(ctx context.Context, ownerID string) ([]types.Webhook, error)
+	DeleteWebhook(ctx context.Context, id primitive.ObjectID) error
+
+	CreateDelivery(ctx context.Context, delivery *types.WebhookDelivery) (*types.WebhookDelivery, error)
+	GetDeliveryByID(ctx context.Context, id primitive.ObjectID) (*types.WebhookDelivery, error)
+	// ListDeliveries returns an owner's deliveries (optionally for one scan), newest first.
+	ListDeliveries(ctx context.Context, ownerID, scanID string, limit int) ([]types.WebhookDelivery, error)
+	// AddDeliveryAttempt appends an attempt to a delivery and sets its status.
+	AddDeliveryAttempt(ctx context.Context, id primitive.ObjectID, attempt types.WebhookAttempt, status string) error
+	// RestartDelivery sets a finished delivery, or a pending one last updated before `staleBefore`
+	// (abandoned, e.g. by a restart mid-retry), back to pending. It reports false if the delivery is
+	// still being attempted, so concurrent replays cannot both start.
+	RestartDelivery(ctx context.Context, id primitive.ObjectID, staleBefore time.Time) (bool, error)
+}
+
+// mongoWebhookRepository implements WebhookRepository for MongoDB.
+type mongoWebhookRepository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+// NewMongoWebhookRepository creates a new MongoDB webhook repository.
+func NewMongoWebhookRepository(db *mongo.Database) WebhookRepository {
+	return &mongoWebhookRepository{
+		webhooks:   db.Collection("webhooks"),
+		deliveries: db.Collection("webhook_deliveries"),
+	}
+}
+
+// CreateWebhook inserts a new webhook into MongoDB.
+func (r *mongoWebhookRepository) CreateWebhook(ctx context.Context, hook *types.Webhook) (*types.Webhook, error) {
+	if hook.ID.IsZero() {
+		hook.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.webhooks.InsertOne(ctx, hook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	log.Printf("INFO: Webhook created with ID: %s (owner: %s)", hook.ID.Hex(), hook.OwnerID)
+	return hook, nil
+}
+
+// GetWebhookByID retrieves a webhook by its MongoDB ObjectID.
+func (r *mongoWebhookRepository) GetWebhookByID(ctx context.Context, id primitive.ObjectID) (*types.Webhook, error) {
+	var hook types.Webhook
+	err := r.webhooks.FindOne(ctx, bson.M{"_id": id}).Decode(&hook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook by ID: %w", err)
+	}
+	return &hook, nil
+}
+
+// ListWebhooks retrieves the webhooks of an owner, newest first.
+func (r *mongoWebhookRepository) ListWebhooks(ctx context.Context, ownerID string) ([]types.Webhook, error) {
+	cursor, err := r.webhooks.Find(ctx, bson.M{"owner_id": ownerID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	hooks := []types.Webhook{}
+	if err := cursor.All(ctx, &hooks); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+// DeleteWebhook removes a webhook. Its past deliveries stay in the log.
+func (r *mongoWebhookRepository) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+	return nil
+}
+
+// CreateDelivery inserts a new delivery log entry.
+func (r *mongoWebhookRepository) CreateDelivery(ctx context.Context, delivery *types.WebhookDelivery) (*types.WebhookDelivery, error) {
+	if delivery.ID.IsZero() {
+		delivery.ID = primitive.NewObjectID()
+	}
+	if _, err := r.deliveries.InsertOne(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// GetDeliveryByID retrieves a delivery by its MongoDB ObjectID.
+func (r *mongoWebhookRepository) GetDeliveryByID(ctx context.Context, id primitive.ObjectID) (*types.WebhookDelivery, error) {
+	var delivery types.WebhookDelivery
+	err := r.deliveries.FindOne(ctx, bson.M{"_id": id}).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("webhook delivery not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery by ID: %w", err)
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries retrieves an owner's deliveries, newest first. An empty scanID matches all scans.
+func (r *mongoWebhookRepository) ListDeliveries(ctx context.Context, ownerID, scanID string, limit int) ([]types.WebhookDelivery, error) {
+	filter := bson.M{"owner_id": ownerID}
+	if scanID != "" {
+		filter["scan_id"] = scanID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []types.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// AddDeliveryAttempt appends an attempt and updates the delivery status.
+func (r *mongoWebhookRepository) AddDeliveryAttempt(ctx context.Context, id primitive.ObjectID, attempt types.WebhookAttempt, status string) error {
+	update := bson.M{
+		"$push": bson.M{"attempts": attempt},
+		"$set":  bson.M{"status": status, "updated_at": time.Now()},
+	}
+	result, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("webhook delivery not found")
+	}
+	return nil
+}
+
+// RestartDelivery marks a delivery as pending unless it is pending and was updated since staleBefore.
+func (r *mongoWebhookRepository) RestartDelivery(ctx context.Context, id primitive.ObjectID, staleBefore time.Time) (bool, error) {
+	filter := bson.M{"_id": id, "$or": bson.A{
+		bson.M{"status": bson.M{"$ne": types.WebhookDeliveryPending}},
+		bson.M{"updated_at": bson.M{"$lt": staleBefore}},
+	}}
+	update := bson.M{"$set": bson.M{"status": types.WebhookDeliveryPending, "updated_at": time.Now()}}
+	result, err := r.deliveries.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to restart webhook delivery: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
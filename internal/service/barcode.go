@@ -0,0 +1,137 @@
+package service
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	multiqr "github.com/makiuchi-d/gozxing/multi/qrcode"
+	"github.com/makiuchi-d/gozxing/oned"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path to your module
+)
+
+// maxBarcodeSearchDepth bounds how often the areas around a found barcode are searched for more,
+// as in ZXing's GenericMultipleBarcodeReader.
+const maxBarcodeSearchDepth = 4
+
+// minBarcodeSearchSide is the smallest area (in pixels) searched for further barcodes.
+const minBarcodeSearchSide = 40
+
+// barcodeFormats maps the decoded formats to their API names.
+var barcodeFormats = map[gozxing.BarcodeFormat]string{
+	gozxing.BarcodeFormat_QR_CODE:     types.BarcodeFormatQRCode,
+	gozxing.BarcodeFormat_CODE_128:    types.BarcodeFormatCode128,
+	gozxing.BarcodeFormat_EAN_13:      types.BarcodeFormatEAN13,
+	gozxing.BarcodeFormat_DATA_MATRIX: types.BarcodeFormatDataMatrix,
+	gozxing.BarcodeFormat_PDF_417:     types.BarcodeFormatPDF417,
+}
+
+// ReadBarcodes decodes the QR codes, Code 128 and EAN-13 barcodes, Data Matrix codes and PDF417 symbols on a
+// page image.
+func (s *layoutService) ReadBarcodes(imageData []byte) ([]types.DetectedBarcode, error) {
+	img, _, err := utils.DecodeImage(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read barcodes: %w", err)
+	}
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read barcodes: %w", err)
+	}
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+
+	var results []*gozxing.Result
+	// A page can carry several QR codes; the multi reader finds them all in one pass.
+	if qrs, err := multiqr.NewQRCodeMultiReader().DecodeMultiple(bitmap, hints); err == nil {
+		results = append(results, qrs...)
+	}
+	results = append(results, findBarcodes(bitmap, image.Point{}, hints, 0)...)
+
+	barcodes := []types.DetectedBarcode{}
+	seen := map[string]bool{}
+	for _, result := range results {
+		format, ok := barcodeFormats[result.GetBarcodeFormat()]
+		key := format + "\x00" + result.GetText()
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		barcode := types.DetectedBarcode{Format: format, Text: result.GetText(), Box: ocrBox(barcodeBounds(result.GetResultPoints()))}
+		if u, err := url.Parse(strings.TrimSpace(barcode.Text)); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+			barcode.URL = u.String()
+		}
+		barcodes = append(barcodes, barcode)
+	}
+	// Report codes in reading order rather than in the order the readers found them.
+	sort.SliceStable(barcodes, func(i, j int) bool {
+		if barcodes[i].Box.Y != barcodes[j].Box.Y {
+			return barcodes[i].Box.Y < barcodes[j].Box.Y
+		}
+		return barcodes[i].Box.X < barcodes[j].Box.X
+	})
+	return barcodes, nil
+}
+
+// findBarcodes decodes one barcode in `bitmap` and then searches the areas left of, right of, above and
+// below it for more. Result points are translated by `offset`, the bitmap's position on the page.
+func findBarcodes(bitmap *gozxing.BinaryBitmap, offset image.Point, hints map[gozxing.DecodeHintType]interface{}, depth int) []*gozxing.Result {
+	// Readers keep per-decode state, so they are created for each search.
+	readers := []gozxing.Reader{oned.NewCode128Reader(), oned.NewEAN13Reader(), datamatrix.NewDataMatrixReader(), newPDF417Reader()}
+	var result *gozxing.Result
+	for _, reader := range readers {
+		if r, err := reader.Decode(bitmap, hints); err == nil {
+			result = r
+			break
+		}
+	}
+	if result == nil {
+		return nil
+	}
+
+	local := barcodeBounds(result.GetResultPoints())
+	points := make([]gozxing.ResultPoint, 0, len(result.GetResultPoints()))
+	for _, p := range result.GetResultPoints() {
+		points = append(points, gozxing.NewResultPoint(p.GetX()+float64(offset.X), p.GetY()+float64(offset.Y)))
+	}
+	found := []*gozxing.Result{gozxing.NewResult(result.GetText(), result.GetRawBytes(), points, result.GetBarcodeFormat())}
+	if depth >= maxBarcodeSearchDepth {
+		return found
+	}
+
+	width, height := bitmap.GetWidth(), bitmap.GetHeight()
+	for _, area := range []image.Rectangle{
+		image.Rect(0, 0, local.Min.X, height),
+		image.Rect(0, 0, width, local.Min.Y),
+		image.Rect(local.Max.X, 0, width, height),
+		image.Rect(0, local.Max.Y, width, height),
+	} {
+		if area.Dx() < minBarcodeSearchSide || area.Dy() < minBarcodeSearchSide {
+			continue
+		}
+		cropped, err := bitmap.Crop(area.Min.X, area.Min.Y, area.Dx(), area.Dy())
+		if err != nil {
+			continue
+		}
+		found = append(found, findBarcodes(cropped, offset.Add(area.Min), hints, depth+1)...)
+	}
+	return found
+}
+
+// barcodeBounds returns the rectangle spanned by a barcode's result points.
+func barcodeBounds(points []gozxing.ResultPoint) image.Rectangle {
+	if len(points) == 0 {
+		return image.Rectangle{}
+	}
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minX, maxX = min(minX, p.GetX()), max(maxX, p.GetX())
+		minY, maxY = min(minY, p.GetY()), max(maxY, p.GetY())
+	}
+	return image.Rect(int(minX), int(minY), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1)
+}
@@ -0,0 +1,761 @@
+package service
+
+import (
+	"math"
+	"math/big"
+	"slices"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/makiuchi-d/gozxing"
+)
+
+// PDF417 symbol structure (ISO/IEC 15438). Every row is a start pattern, a left row indicator, 1-30 data
+// columns, a right row indicator and a stop pattern. A codeword is 4 bars and 4 spaces over 17 modules;
+// the rows cycle through three clusters of patterns so that neighbouring rows can be told apart.
+const (
+	pdf417MaxRows    = 90
+	pdf417MaxColumns = 30
+	pdf417Modulus    = 929 // Codewords and error correction are computed modulo 929
+	pdf417Modules    = 17  // Modules in a codeword and in the start pattern
+
+	// maxPDF417LineGap is how many scanlines (in module widths) may go by without a start pattern
+	// before the lines that follow are taken to belong to another symbol.
+	maxPDF417LineGap = 2
+	// maxPDF417Misses is how many codewords in a row a scanline may fail to read before it is taken to
+	// have left the symbol.
+	maxPDF417Misses = 3
+)
+
+// Element widths (in modules) of the start and stop patterns, beginning with a bar.
+var (
+	pdf417StartWidths = []int{8, 1, 1, 1, 1, 1, 1, 3}
+	pdf417StopWidths  = []int{7, 1, 1, 3, 1, 1, 1, 2, 1}
+)
+
+// Mode latches and shifts of the data codewords.
+const (
+	pdf417TextLatch       = 900
+	pdf417ByteLatch       = 901
+	pdf417NumericLatch    = 902
+	pdf417ByteShift       = 913
+	pdf417MacroTerminator = 922
+	pdf417MacroOptional   = 923
+	pdf417ByteLatch6      = 924
+	pdf417ECIUser         = 925
+	pdf417ECIGeneral      = 926
+	pdf417ECICharset      = 927
+	pdf417MacroControl    = 928
+)
+
+// Characters of the mixed and punctuation text submodes; the remaining values are latches and shifts.
+const (
+	pdf417MixedChars = "0123456789&\r\t,:#-.$/+%*=^"
+	pdf417PunctChars = ";<>@[\\]_`~!\r\t,:\n-.$/\"|*()?{}'"
+)
+
+// pdf417Patterns maps the 17-module pattern of every codeword to its cluster (0, 1 or 2 for clusters 0, 3
+// and 6) and value.
+var pdf417Patterns = func() map[uint32]pdf417Codeword {
+	patterns := make(map[uint32]pdf417Codeword, 3*pdf417Modulus)
+	for cluster := range pdf417Codewords {
+		for value, pattern := range pdf417Codewords[cluster] {
+			patterns[pattern] = pdf417Codeword{cluster: cluster, value: value}
+		}
+	}
+	return patterns
+}()
+
+// pdf417Exp and pdf417Log are powers and logarithms of the generator 3 in GF(929).
+var pdf417Exp, pdf417Log = func() (exp, log [pdf417Modulus]int) {
+	x := 1
+	for i := 0; i < pdf417Modulus; i++ {
+		exp[i] = x
+		x = x * 3 % pdf417Modulus
+	}
+	for i := 0; i < pdf417Modulus-1; i++ {
+		log[exp[i]] = i
+	}
+	return exp, log
+}()
+
+// pdf417Codeword is a codeword read from a scanline; cluster is -1 when the pattern was not recognized.
+type pdf417Codeword struct {
+	cluster int
+	value   int
+}
+
+// pdf417Line is one scanline through a PDF417 symbol: the codewords after the start pattern, beginning
+// with the left row indicator.
+type pdf417Line struct {
+	line       int
+	start, end int // Positions along the scanline of the start pattern and the end of the last element read
+	unit       float64
+	codewords  []pdf417Codeword
+	stopped    bool // The stop pattern was found, so the last codeword is the right row indicator
+}
+
+// pdf417Scan is a scan direction: along image rows or columns, forwards or backwards.
+type pdf417Scan struct {
+	vertical, reverse bool
+}
+
+// pdf417Reader decodes a PDF417 symbol, which gozxing does not support. It reads each image row (and
+// column) as a scanline, as a handheld scanner would, so it handles upright, upside-down and sideways
+// symbols skewed by a few degrees, but not perspective distortion.
+type pdf417Reader struct{}
+
+func newPDF417Reader() gozxing.Reader {
+	return pdf417Reader{}
+}
+
+func (r pdf417Reader) DecodeWithoutHints(bitmap *gozxing.BinaryBitmap) (*gozxing.Result, error) {
+	return r.Decode(bitmap, nil)
+}
+
+func (pdf417Reader) Reset() {}
+
+func (pdf417Reader) Decode(bitmap *gozxing.BinaryBitmap, _ map[gozxing.DecodeHintType]interface{}) (*gozxing.Result, error) {
+	matrix, err := bitmap.GetBlackMatrix()
+	if err != nil {
+		return nil, err
+	}
+	err = gozxing.NewNotFoundException("no PDF417 symbol")
+	// Reading rows and columns both ways finds symbols at 0, 180, 90 and 270 degrees.
+	for _, vertical := range []bool{false, true} {
+		lines := pdf417Runs(matrix, vertical)
+		for _, reverse := range []bool{false, true} {
+			scan := pdf417Scan{vertical: vertical, reverse: reverse}
+			for _, symbol := range findPDF417Lines(lines, reverse) {
+				var text string
+				codewords, decodeErr := decodePDF417Codewords(symbol)
+				if decodeErr == nil {
+					text, decodeErr = decodePDF417Data(codewords)
+				}
+				if decodeErr != nil {
+					err = decodeErr
+					continue
+				}
+				first, last := symbol[0], symbol[len(symbol)-1]
+				points := []gozxing.ResultPoint{
+					scan.point(matrix, first.line, first.start), scan.point(matrix, last.line, last.start),
+					scan.point(matrix, first.line, first.end), scan.point(matrix, last.line, last.end),
+				}
+				return gozxing.NewResult(text, nil, points, gozxing.BarcodeFormat_PDF_417), nil
+			}
+		}
+	}
+	return nil, err
+}
+
+// point returns the image position of `pos` along scanline `line`.
+func (s pdf417Scan) point(matrix *gozxing.BitMatrix, line, pos int) gozxing.ResultPoint {
+	length := matrix.GetWidth()
+	if s.vertical {
+		length = matrix.GetHeight()
+	}
+	if s.reverse {
+		pos = length - 1 - pos
+	}
+	if s.vertical {
+		return gozxing.NewResultPoint(float64(line), float64(pos))
+	}
+	return gozxing.NewResultPoint(float64(pos), float64(line))
+}
+
+// pdf417Runs returns the lengths of the alternating white and black runs along every image row (or
+// column), each starting with a (possibly empty) white run.
+func pdf417Runs(matrix *gozxing.BitMatrix, vertical bool) [][]int {
+	if vertical {
+		// Transpose the matrix so that columns can be read a word at a time, too.
+		transposed, err := gozxing.NewBitMatrix(matrix.GetHeight(), matrix.GetWidth())
+		if err != nil {
+			return nil
+		}
+		var row *gozxing.BitArray
+		for y := 0; y < matrix.GetHeight(); y++ {
+			row = matrix.GetRow(y, row)
+			for x := row.GetNextSet(0); x < matrix.GetWidth(); x = row.GetNextSet(x + 1) {
+				transposed.Set(y, x)
+			}
+		}
+		matrix = transposed
+	}
+	all := make([][]int, matrix.GetHeight())
+	var row *gozxing.BitArray
+	for y := range all {
+		row = matrix.GetRow(y, row)
+		runs := []int{}
+		for pos, black := 0, false; pos < matrix.GetWidth(); black = !black {
+			next := row.GetNextSet(pos)
+			if black {
+				next = row.GetNextUnset(pos)
+			}
+			next = min(next, matrix.GetWidth())
+			runs = append(runs, next-pos)
+			pos = next
+		}
+		all[y] = runs
+	}
+	return all
+}
+
+// findPDF417Lines reads every scanline, forwards or backwards, and groups the lines that start at about
+// the same position into symbols, the longest first.
+func findPDF417Lines(lines [][]int, reverse bool) [][]pdf417Line {
+	var symbols [][]pdf417Line
+	for line, runs := range lines {
+		if reverse {
+			runs = slices.Clone(runs)
+			slices.Reverse(runs)
+			if len(runs)%2 == 0 {
+				runs = append([]int{0}, runs...)
+			}
+		}
+		starts := make([]int, len(runs)+1)
+		for i, run := range runs {
+			starts[i+1] = starts[i] + run
+		}
+		for i := 1; i+len(pdf417StartWidths) < len(runs); i += 2 {
+			// The 8-module bar is much wider than the space after it; most runs fail this cheap test.
+			if runs[i] < 2*runs[i+1] {
+				continue
+			}
+			unit, ok := matchPDF417Pattern(runs[i:], pdf417StartWidths, pdf417Modules)
+			// The start pattern must follow a quiet zone (or the edge of the image).
+			if !ok || (i > 1 && float64(runs[i-1]) < 2*unit) {
+				continue
+			}
+			read := readPDF417Line(runs, starts, i, unit)
+			read.line = line
+			if len(read.codewords) < 2 {
+				continue
+			}
+			joined := false
+			for s, symbol := range symbols {
+				last := symbol[len(symbol)-1]
+				if last.line < line && float64(line-last.line) <= maxPDF417LineGap*unit+1 &&
+					math.Abs(float64(last.start-read.start)) <= 3*unit && math.Abs(last.unit-unit) <= unit/4 {
+					symbols[s] = append(symbol, read)
+					joined = true
+					break
+				}
+			}
+			if !joined {
+				symbols = append(symbols, []pdf417Line{read})
+			}
+		}
+	}
+	sort.SliceStable(symbols, func(i, j int) bool { return len(symbols[i]) > len(symbols[j]) })
+	return symbols
+}
+
+// readPDF417Line reads the codewords after the start pattern at runs[i]; starts holds the position of each
+// run. A codeword that cannot be read is kept as a gap, and the next one is looked for a codeword width
+// further on, so that the codewords after a blot stay in their columns.
+func readPDF417Line(runs, starts []int, i int, unit float64) pdf417Line {
+	read := pdf417Line{start: starts[i], unit: unit}
+	j := i + len(pdf417StartWidths) // Bars are at odd indexes
+	next := float64(starts[j])      // Where the next codeword should begin
+	misses := 0
+	for j < len(runs) && len(read.codewords) < pdf417MaxColumns+2 && misses <= maxPDF417Misses {
+		for j < len(runs) && float64(starts[j]) < next-unit {
+			j += 2
+		}
+		if j >= len(runs) {
+			break
+		}
+		if _, ok := matchPDF417Pattern(runs[j:], pdf417StopWidths, pdf417Modules+1); ok && math.Abs(float64(starts[j])-next) <= unit {
+			read.stopped = true
+			read.end = starts[j+len(pdf417StopWidths)]
+			break
+		}
+		var codeword pdf417Codeword
+		ok, total := false, 0
+		if math.Abs(float64(starts[j])-next) <= unit && j+8 <= len(runs) {
+			for _, run := range runs[j : j+8] {
+				total += run
+			}
+			// A codeword much wider or narrower than the last one is a blot or the edge of the symbol.
+			if expected := unit * pdf417Modules; float64(total) >= 0.75*expected && float64(total) <= 1.25*expected {
+				codeword, ok = pdf417Patterns[pdf417Pattern(runs[j:j+8])]
+			}
+		}
+		if ok {
+			unit = float64(total) / pdf417Modules
+			next = float64(starts[j] + total)
+			read.end = starts[j] + total
+			j += 8
+			misses = 0
+		} else {
+			codeword = pdf417Codeword{cluster: -1}
+			next += unit * pdf417Modules
+			misses++
+		}
+		read.codewords = append(read.codewords, codeword)
+	}
+	// Trailing gaps only add noise, unless the last codeword is known to be the right row indicator.
+	for !read.stopped && len(read.codewords) > 0 && read.codewords[len(read.codewords)-1].cluster < 0 {
+		read.codewords = read.codewords[:len(read.codewords)-1]
+	}
+	return read
+}
+
+// matchPDF417Pattern reports whether the runs starting with a bar have the given element widths, and
+// returns the module width.
+func matchPDF417Pattern(runs []int, widths []int, modules int) (float64, bool) {
+	if len(runs) < len(widths) {
+		return 0, false
+	}
+	total := 0
+	for _, run := range runs[:len(widths)] {
+		total += run
+	}
+	unit := float64(total) / float64(modules)
+	if unit < 1 {
+		return 0, false
+	}
+	for k, width := range widths {
+		if math.Abs(float64(runs[k])-float64(width)*unit) > 0.8*unit {
+			return 0, false
+		}
+	}
+	return unit, true
+}
+
+// pdf417Pattern rounds the widths of a codeword's 8 elements to whole modules adding up to 17 and returns
+// the module pattern, one bit per module with the first bar in bit 16.
+func pdf417Pattern(runs []int) uint32 {
+	total := 0
+	for _, run := range runs {
+		total += run
+	}
+	var widths [8]int
+	var errs [8]float64
+	sum := 0
+	for k, run := range runs {
+		exact := float64(run) * pdf417Modules / float64(total)
+		widths[k] = min(max(int(math.Round(exact)), 1), 6)
+		errs[k] = exact - float64(widths[k])
+		sum += widths[k]
+	}
+	// Fix the total by widening the elements rounded down the most, or narrowing those rounded up the most.
+	for sum != pdf417Modules {
+		best := -1
+		for k := range widths {
+			if sum < pdf417Modules && widths[k] < 6 && (best < 0 || errs[k] > errs[best]) {
+				best = k
+			}
+			if sum > pdf417Modules && widths[k] > 1 && (best < 0 || errs[k] < errs[best]) {
+				best = k
+			}
+		}
+		if best < 0 {
+			return 0
+		}
+		if sum < pdf417Modules {
+			widths[best]++
+			errs[best]--
+			sum++
+		} else {
+			widths[best]--
+			errs[best]++
+			sum--
+		}
+	}
+	var pattern uint32
+	for k, width := range widths {
+		pattern <<= width
+		if k%2 == 0 {
+			pattern |= 1<<width - 1
+		}
+	}
+	return pattern
+}
+
+// pdf417Metadata collects the votes of the row indicators on the symbol's size and error correction level.
+type pdf417Metadata struct {
+	columns, rowsHigh, rowsLow, ecLevel map[int]int
+}
+
+// decodePDF417Codewords arranges the codewords read on the lines of a symbol in its rows and columns,
+// taking the most frequent reading of each, and corrects errors. It returns the data codewords, starting
+// with the symbol length descriptor.
+func decodePDF417Codewords(lines []pdf417Line) ([]int, error) {
+	meta := pdf417Metadata{columns: map[int]int{}, rowsHigh: map[int]int{}, rowsLow: map[int]int{}, ecLevel: map[int]int{}}
+	// The left indicator of a row in cluster 0 gives the row count / 3, in cluster 3 the error
+	// correction level and row count % 3 and in cluster 6 the column count; the right indicator has
+	// the same values rotated by one cluster.
+	indicate := func(codeword pdf417Codeword, right bool) {
+		kind := codeword.cluster
+		if right {
+			kind = (kind + 2) % 3
+		}
+		x := codeword.value % 30
+		switch kind {
+		case 0:
+			meta.rowsHigh[x]++
+		case 1:
+			meta.ecLevel[x/3]++
+			meta.rowsLow[x%3]++
+		case 2:
+			meta.columns[x+1]++
+		}
+	}
+	for _, line := range lines {
+		if left := line.codewords[0]; left.cluster >= 0 {
+			indicate(left, false)
+		}
+		if right := line.codewords[len(line.codewords)-1]; line.stopped && right.cluster >= 0 {
+			indicate(right, true)
+		}
+	}
+	columns := pdf417Vote(meta.columns)
+	if columns < 1 || columns > pdf417MaxColumns {
+		return nil, gozxing.NewFormatException("PDF417 column count %d", columns)
+	}
+	ecLevel := pdf417Vote(meta.ecLevel)
+	if ecLevel < 0 || ecLevel > 8 {
+		return nil, gozxing.NewFormatException("PDF417 error correction level %d", ecLevel)
+	}
+
+	// Place each codeword by the row its line's indicators name. A skewed scanline can cross into the
+	// row above or below; the codeword's cluster tells which.
+	votes := map[[2]int]map[int]int{}
+	maxRow := -1
+	for _, line := range lines {
+		row := -1
+		if left := line.codewords[0]; left.cluster >= 0 {
+			row = 3*(left.value/30) + left.cluster
+		} else if len(line.codewords) > columns+1 {
+			if right := line.codewords[columns+1]; right.cluster >= 0 {
+				row = 3*(right.value/30) + right.cluster
+			}
+		}
+		if row < 0 || row >= pdf417MaxRows {
+			continue
+		}
+		maxRow = max(maxRow, row)
+		for col := 1; col <= columns && col < len(line.codewords); col++ {
+			codeword := line.codewords[col]
+			if codeword.cluster < 0 {
+				continue
+			}
+			r := row + (codeword.cluster-row%3+4)%3 - 1
+			if r < 0 || r >= pdf417MaxRows {
+				continue
+			}
+			cell := [2]int{r, col - 1}
+			if votes[cell] == nil {
+				votes[cell] = map[int]int{}
+			}
+			votes[cell][codeword.value]++
+		}
+	}
+	if maxRow < 0 {
+		return nil, gozxing.NewNotFoundException("no PDF417 row indicators")
+	}
+	rows := maxRow + 1
+	if high, low := pdf417Vote(meta.rowsHigh), pdf417Vote(meta.rowsLow); high >= 0 && low >= 0 {
+		// Rows missed at the bottom are recovered by error correction.
+		rows = min(max(rows, 3*high+low+1), pdf417MaxRows)
+	}
+
+	codewords := make([]int, rows*columns)
+	var erasures []int // Cells no scanline could read
+	for i := range codewords {
+		if values := votes[[2]int{i / columns, i % columns}]; values != nil {
+			codewords[i] = pdf417Vote(values)
+		} else {
+			erasures = append(erasures, i)
+		}
+	}
+	ecCount := 2 << ecLevel
+	// A symbol holds at most 928 codewords, the order of the error correction field.
+	if len(codewords) <= ecCount || len(codewords) > pdf417Modulus-1 {
+		return nil, gozxing.NewFormatException("PDF417 symbol of %d codewords", len(codewords))
+	}
+	if err := correctPDF417Errors(codewords, ecCount, erasures); err != nil {
+		return nil, err
+	}
+	length := codewords[0]
+	if length == 0 {
+		length = len(codewords) - ecCount
+	}
+	if length > len(codewords)-ecCount {
+		return nil, gozxing.NewFormatException("PDF417 length descriptor %d", length)
+	}
+	return codewords[:length], nil
+}
+
+// pdf417Vote returns the value with the most votes (the smallest on ties), or -1 without votes.
+func pdf417Vote(votes map[int]int) int {
+	best := -1
+	for value, count := range votes {
+		if best < 0 || count > votes[best] || (count == votes[best] && value < best) {
+			best = value
+		}
+	}
+	return best
+}
+
+// correctPDF417Errors corrects wrong codewords in place with the Reed-Solomon code over GF(929) whose
+// generator has the roots 3^1 ... 3^ecCount. It corrects e errors at unknown positions and the codewords
+// at the erasure indexes as long as 2e + len(erasures) <= ecCount.
+func correctPDF417Errors(codewords []int, ecCount int, erasures []int) error {
+	mul := func(a, b int) int { return a * b % pdf417Modulus }
+	inv := func(a int) int { return pdf417Exp[(pdf417Modulus-1-pdf417Log[a])%(pdf417Modulus-1)] }
+	// eval evaluates a polynomial with its lowest coefficient first.
+	eval := func(poly []int, x int) int {
+		y := 0
+		for i := len(poly) - 1; i >= 0; i-- {
+			y = (mul(y, x) + poly[i]) % pdf417Modulus
+		}
+		return y
+	}
+	syndromes := func() ([]int, bool) {
+		s := make([]int, ecCount)
+		clean := true
+		for i := range s {
+			x := pdf417Exp[i+1]
+			for _, c := range codewords {
+				s[i] = (mul(s[i], x) + c) % pdf417Modulus
+			}
+			clean = clean && s[i] == 0
+		}
+		return s, clean
+	}
+	s, clean := syndromes()
+	if clean {
+		return nil
+	}
+
+	if len(erasures) > ecCount {
+		return gozxing.NewChecksumException("too many PDF417 erasures")
+	}
+	// The erasure locator has a root at the inverse power of each erased position; Berlekamp-Massey
+	// extends it to the locator of all errors.
+	locator := []int{1}
+	for _, j := range erasures {
+		x := pdf417Exp[(len(codewords)-1-j)%(pdf417Modulus-1)]
+		next := make([]int, len(locator)+1)
+		copy(next, locator)
+		for i, c := range locator {
+			next[i+1] = (next[i+1] + pdf417Modulus - mul(x, c)) % pdf417Modulus
+		}
+		locator = next
+	}
+	prev := locator
+	degree, shift, prevDiscrepancy := len(erasures), 1, 1
+	for n := len(erasures); n < ecCount; n++ {
+		d := s[n]
+		for i := 1; i <= degree && i <= n && i < len(locator); i++ {
+			d = (d + mul(locator[i], s[n-i])) % pdf417Modulus
+		}
+		if d == 0 {
+			shift++
+			continue
+		}
+		factor := mul(d, inv(prevDiscrepancy))
+		next := make([]int, max(len(locator), len(prev)+shift))
+		copy(next, locator)
+		for i, c := range prev {
+			next[i+shift] = (next[i+shift] + pdf417Modulus - mul(factor, c)) % pdf417Modulus
+		}
+		if 2*degree <= n+len(erasures) {
+			prev, degree, prevDiscrepancy, shift = locator, n+1+len(erasures)-degree, d, 1
+		} else {
+			shift++
+		}
+		locator = next
+	}
+	if 2*degree-len(erasures) > ecCount {
+		return gozxing.NewChecksumException("too many PDF417 errors")
+	}
+
+	// The evaluator is S(x)·Λ(x) mod x^ecCount; Forney's formula gives the error values.
+	evaluator := make([]int, ecCount)
+	for i := range evaluator {
+		for j := 0; j <= i && j < len(locator); j++ {
+			evaluator[i] = (evaluator[i] + mul(locator[j], s[i-j])) % pdf417Modulus
+		}
+	}
+	derivative := make([]int, max(len(locator)-1, 1))
+	for i := 1; i < len(locator); i++ {
+		derivative[i-1] = mul(i%pdf417Modulus, locator[i])
+	}
+	found := 0
+	for j := range codewords {
+		degree := len(codewords) - 1 - j
+		xInv := pdf417Exp[(pdf417Modulus-1-degree%(pdf417Modulus-1))%(pdf417Modulus-1)]
+		if eval(locator, xInv) != 0 {
+			continue
+		}
+		denominator := eval(derivative, xInv)
+		if denominator == 0 {
+			return gozxing.NewChecksumException("bad PDF417 error locator")
+		}
+		value := mul(eval(evaluator, xInv), inv(denominator))
+		codewords[j] = (codewords[j] + value) % pdf417Modulus
+		found++
+	}
+	if found != degree {
+		return gozxing.NewChecksumException("PDF417 errors not located")
+	}
+	if _, clean := syndromes(); !clean {
+		return gozxing.NewChecksumException("PDF417 errors not corrected")
+	}
+	return nil
+}
+
+// Text compaction submodes.
+const (
+	pdf417Alpha = iota
+	pdf417Lower
+	pdf417Mixed
+	pdf417Punct
+)
+
+// decodePDF417Data decodes the data codewords after the length descriptor. Text and numeric compaction
+// give ASCII; byte compaction is returned as UTF-8 when it is valid UTF-8 and read as Latin-1 otherwise.
+// Macro PDF417 control blocks end the data.
+func decodePDF417Data(codewords []int) (string, error) {
+	var out []byte
+	mode, submode, shift := pdf417TextLatch, pdf417Alpha, -1
+	for i := 1; i < len(codewords); {
+		switch cw := codewords[i]; cw {
+		case pdf417TextLatch, pdf417ByteLatch, pdf417ByteLatch6, pdf417NumericLatch:
+			mode = cw
+			if cw == pdf417TextLatch {
+				submode, shift = pdf417Alpha, -1
+			}
+			i++
+			continue
+		case pdf417ByteShift:
+			if i+1 < len(codewords) && codewords[i+1] < 256 {
+				out = append(out, byte(codewords[i+1]))
+			}
+			i += 2
+			continue
+		case pdf417ECIUser, pdf417ECICharset:
+			i += 2
+			continue
+		case pdf417ECIGeneral:
+			i += 3
+			continue
+		case pdf417MacroControl, pdf417MacroOptional, pdf417MacroTerminator:
+			i = len(codewords)
+			continue
+		}
+		end := i
+		for end < len(codewords) && codewords[end] < pdf417TextLatch {
+			end++
+		}
+		if end == i {
+			// Other reserved codewords carry no data.
+			i++
+			continue
+		}
+		segment := codewords[i:end]
+		i = end
+		switch mode {
+		case pdf417TextLatch:
+			for _, cw := range segment {
+				for _, v := range []int{cw / 30, cw % 30} {
+					out, submode, shift = appendPDF417Text(out, v, submode, shift)
+				}
+			}
+		case pdf417ByteLatch, pdf417ByteLatch6:
+			// Groups of 5 codewords hold 6 bytes. In mode 901 the last 1-5 codewords are single bytes.
+			groups := len(segment) / 5
+			if mode == pdf417ByteLatch {
+				groups = (len(segment) - 1) / 5
+			}
+			for g := 0; g < groups; g++ {
+				var value uint64
+				for _, cw := range segment[5*g : 5*g+5] {
+					value = value*900 + uint64(cw)
+				}
+				for shift := 40; shift >= 0; shift -= 8 {
+					out = append(out, byte(value>>shift))
+				}
+			}
+			for _, cw := range segment[5*groups:] {
+				if cw > 255 {
+					return "", gozxing.NewFormatException("PDF417 byte codeword %d", cw)
+				}
+				out = append(out, byte(cw))
+			}
+		case pdf417NumericLatch:
+			// Groups of up to 15 codewords are a base 900 number whose decimal digits follow a leading 1.
+			for g := 0; g < len(segment); g += 15 {
+				value := new(big.Int)
+				for _, cw := range segment[g:min(g+15, len(segment))] {
+					value.Mul(value, big.NewInt(900)).Add(value, big.NewInt(int64(cw)))
+				}
+				digits := value.String()
+				if digits[0] != '1' {
+					return "", gozxing.NewFormatException("bad PDF417 numeric group")
+				}
+				out = append(out, digits[1:]...)
+			}
+		}
+	}
+	if utf8.Valid(out) {
+		return string(out), nil
+	}
+	runes := make([]rune, len(out))
+	for i, b := range out {
+		runes[i] = rune(b)
+	}
+	return string(runes), nil
+}
+
+// appendPDF417Text decodes one text compaction value. A shift (-1 for none) applies to the next value only.
+func appendPDF417Text(out []byte, v, submode, shift int) ([]byte, int, int) {
+	mode := submode
+	if shift >= 0 {
+		mode, shift = shift, -1
+	}
+	switch mode {
+	case pdf417Alpha, pdf417Lower:
+		switch {
+		case v < 26 && mode == pdf417Alpha:
+			out = append(out, byte('A'+v))
+		case v < 26:
+			out = append(out, byte('a'+v))
+		case v == 26:
+			out = append(out, ' ')
+		case v == 27 && mode == pdf417Alpha:
+			submode = pdf417Lower
+		case v == 27:
+			shift = pdf417Alpha
+		case v == 28:
+			submode = pdf417Mixed
+		default:
+			shift = pdf417Punct
+		}
+	case pdf417Mixed:
+		switch {
+		case v < len(pdf417MixedChars):
+			out = append(out, pdf417MixedChars[v])
+		case v == 25:
+			submode = pdf417Punct
+		case v == 26:
+			out = append(out, ' ')
+		case v == 27:
+			submode = pdf417Lower
+		case v == 28:
+			submode = pdf417Alpha
+		default:
+			shift = pdf417Punct
+		}
+	case pdf417Punct:
+		if v < len(pdf417PunctChars) {
+			out = append(out, pdf417PunctChars[v])
+		} else {
+			submode = pdf417Alpha
+		}
+	}
+	return out, submode, shift
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/boombuler/barcode/pdf417"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+func TestPDF417CodewordTable(t *testing.T) {
+	seen := map[uint32]bool{}
+	for cluster := range pdf417Codewords {
+		for value, pattern := range pdf417Codewords[cluster] {
+			// Read the element widths back from the bits, starting with the bar in bit 16.
+			var widths []int
+			for bit := 16; bit >= 0; bit-- {
+				black := pattern>>bit&1 == 1
+				if len(widths)%2 == 0 == black {
+					widths = append(widths, 0)
+				}
+				widths[len(widths)-1]++
+			}
+			if pattern>>17 != 0 || len(widths) != 8 || slices.Max(widths) > 6 || seen[pattern] {
+				t.Fatalf("cluster %d value %d: bad pattern %017b", cluster*3, value, pattern)
+			}
+			if got := (widths[0] - widths[2] + widths[4] - widths[6] + 9) % 9; got != cluster*3 {
+				t.Fatalf("cluster %d value %d: pattern %017b is in cluster %d", cluster*3, value, pattern, got)
+			}
+			seen[pattern] = true
+		}
+	}
+}
+
+func TestCorrectPDF417Errors(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, ecCount := range []int{2, 8, 64} {
+		data := make([]int, 40)
+		for i := range data {
+			data[i] = rng.Intn(pdf417Modulus)
+		}
+		want := append(data, pdf417ErrorCorrection(data, ecCount)...)
+		if err := correctPDF417Errors(slices.Clone(want), ecCount, nil); err != nil {
+			t.Fatalf("%d EC codewords, no errors: %v", ecCount, err)
+		}
+
+		got := slices.Clone(want)
+		for _, i := range rng.Perm(len(got))[:ecCount/2] {
+			got[i] = (got[i] + 1 + rng.Intn(pdf417Modulus-1)) % pdf417Modulus
+		}
+		if err := correctPDF417Errors(got, ecCount, nil); err != nil || !slices.Equal(got, want) {
+			t.Errorf("%d EC codewords, %d errors: got %v, corrected %v", ecCount, ecCount/2, err, slices.Equal(got, want))
+		}
+
+		// Erasures (unread codewords) cost half as much as errors.
+		got = slices.Clone(want)
+		positions := rng.Perm(len(got))[:ecCount/2+ecCount/4]
+		erasures := positions[:ecCount/2]
+		for _, i := range erasures {
+			got[i] = 0
+		}
+		for _, i := range positions[ecCount/2:] {
+			got[i] = (got[i] + 1 + rng.Intn(pdf417Modulus-1)) % pdf417Modulus
+		}
+		if err := correctPDF417Errors(got, ecCount, erasures); err != nil || !slices.Equal(got, want) {
+			t.Errorf("%d EC codewords, %d erasures and %d errors: got %v, corrected %v", ecCount, len(erasures), ecCount/4, err, slices.Equal(got, want))
+		}
+
+		got = slices.Clone(want)
+		for _, i := range rng.Perm(len(got))[:ecCount/2+1] {
+			got[i] = (got[i] + 1 + rng.Intn(pdf417Modulus-1)) % pdf417Modulus
+		}
+		if err := correctPDF417Errors(got, ecCount, nil); err == nil && !slices.Equal(got, want) {
+			t.Errorf("%d EC codewords, %d errors: miscorrected without an error", ecCount, ecCount/2+1)
+		}
+	}
+}
+
+// pdf417ErrorCorrection computes the error correction codewords of `data` by dividing by the generator
+// polynomial (x - 3)(x - 3^2)...(x - 3^ecCount).
+func pdf417ErrorCorrection(data []int, ecCount int) []int {
+	generator := []int{1} // Highest coefficient first
+	for i := 1; i <= ecCount; i++ {
+		root := pdf417Exp[i]
+		next := make([]int, len(generator)+1)
+		for j, c := range generator {
+			next[j] = (next[j] + c) % pdf417Modulus
+			next[j+1] = (next[j+1] + pdf417Modulus - c*root%pdf417Modulus) % pdf417Modulus
+		}
+		generator = next
+	}
+	remainder := append(slices.Clone(data), make([]int, ecCount)...)
+	for i := range data {
+		factor := remainder[i]
+		for j, c := range generator {
+			remainder[i+j] = (remainder[i+j] + pdf417Modulus - factor*c%pdf417Modulus) % pdf417Modulus
+		}
+	}
+	ec := remainder[len(data):]
+	for i, c := range ec {
+		ec[i] = (pdf417Modulus - c) % pdf417Modulus
+	}
+	return ec
+}
+
+func TestDecodePDF417Data(t *testing.T) {
+	tests := []struct {
+		name      string
+		codewords []int
+		want      string
+	}{
+		// "PDF417" in text compaction: alpha, then a mixed latch for the digits.
+		{"text", []int{0, 453, 178, 121, 239}, "PDF417"},
+		{"lower and punctuation shift", []int{0, 27*30 + 0, 1*30 + 29, 10*30 + 2}, "ab!c"},
+		{"numeric", []int{0, pdf417NumericLatch, 1, 624, 434, 632, 282, 200}, "000213298174000"},
+		{"byte, 6 bytes per group", []int{0, pdf417ByteLatch6, 80, 685, 603, 352, 453}, "012345"},
+		{"byte, single bytes", []int{0, pdf417ByteLatch, 'O', 'K'}, "OK"},
+		{"byte shift in text", []int{0, 1*30 + 1, pdf417ByteShift, '@', 2*30 + 29}, "BB@C"},
+		{"macro block ends the data", []int{0, 1*30 + 1, pdf417MacroControl, 1, 2}, "BB"},
+		{"Latin-1 bytes", []int{0, pdf417ByteLatch, 0xe9}, "é"},
+	}
+	for _, tt := range tests {
+		got, err := decodePDF417Data(tt.codewords)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: decodePDF417Data(%v) = %q, %v; want %q", tt.name, tt.codewords, got, err, tt.want)
+		}
+	}
+}
+
+// pdf417Page renders `text` as a PDF417 symbol with 3-pixel modules and 9-pixel rows, turned by `turns`
+// quarter turns clockwise, on a white page at (60, 80). It returns the PNG and the symbol's rectangle.
+func pdf417Page(t *testing.T, text string, turns int, damage func(symbol *image.Gray)) ([]byte, image.Rectangle) {
+	t.Helper()
+	code, err := pdf417.Encode(text, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const module, rowHeight = 3, 9
+	bounds := code.Bounds() // Modules wide and two pixels per row high
+	symbol := image.NewGray(image.Rect(0, 0, bounds.Dx()*module, bounds.Dy()/2*rowHeight))
+	for y := range symbol.Rect.Dy() {
+		for x := range symbol.Rect.Dx() {
+			symbol.SetGray(x, y, color.Gray{Y: color.GrayModel.Convert(code.At(x/module, y/rowHeight*2)).(color.Gray).Y})
+		}
+	}
+	if damage != nil {
+		damage(symbol)
+	}
+
+	sw, sh := symbol.Rect.Dx(), symbol.Rect.Dy()
+	w, h := sw, sh
+	if turns%2 == 1 {
+		w, h = sh, sw
+	}
+	page := image.NewGray(image.Rect(0, 0, w+200, h+200))
+	for i := range page.Pix {
+		page.Pix[i] = 0xff
+	}
+	for y := range sh {
+		for x := range sw {
+			px, py := x, y
+			switch turns % 4 {
+			case 1:
+				px, py = sh-1-y, x
+			case 2:
+				px, py = sw-1-x, sh-1-y
+			case 3:
+				px, py = y, sw-1-x
+			}
+			page.SetGray(60+px, 80+py, symbol.GrayAt(x, y))
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, page); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes(), image.Rect(60, 80, 60+w, 80+h)
+}
+
+func TestReadPDF417(t *testing.T) {
+	const verifyURL = "https://verify.example.gov.np/c/27-01-75-01234"
+	tests := []struct {
+		name   string
+		text   string
+		turns  int
+		damage func(symbol *image.Gray)
+	}{
+		{"verification URL", verifyURL, 0, nil},
+		{"upside down", verifyURL, 2, nil},
+		{"sideways", verifyURL, 1, nil},
+		{"sideways the other way", verifyURL, 3, nil},
+		{"numeric", "Citizenship 27-01-75-01234 issued 2080/04/15, serial 12345678901234567890", 0, nil},
+		{"UTF-8 bytes", "नेपाल सरकार", 0, nil},
+		{"damaged", verifyURL, 0, func(symbol *image.Gray) {
+			// Blot out a few codewords in the middle of the symbol.
+			r := symbol.Rect
+			for y := r.Dy() / 3; y < r.Dy()/3+12; y++ {
+				for x := r.Dx() / 3; x < r.Dx()/3+60; x++ {
+					symbol.SetGray(x, y, color.Gray{Y: 0xff})
+				}
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, rect := pdf417Page(t, tt.text, tt.turns, tt.damage)
+			barcodes, err := NewLayoutService().ReadBarcodes(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(barcodes) != 1 {
+				t.Fatalf("got %+v, want one PDF417 symbol", barcodes)
+			}
+			got := barcodes[0]
+			if got.Format != types.BarcodeFormatPDF417 || got.Text != tt.text {
+				t.Errorf("got %s %q, want %s %q", got.Format, got.Text, types.BarcodeFormatPDF417, tt.text)
+			}
+			if tt.text == verifyURL && got.URL != verifyURL {
+				t.Errorf("URL = %q, want %q", got.URL, verifyURL)
+			}
+			box := image.Rect(got.Box.X, got.Box.Y, got.Box.X+got.Box.Width, got.Box.Y+got.Box.Height)
+			if !box.Overlaps(rect) || box.Intersect(rect).Dx()*box.Intersect(rect).Dy()*10 < rect.Dx()*rect.Dy()*8 {
+				t.Errorf("box %v, want about %v", box, rect)
+			}
+		})
+	}
+}
+
+func TestReadPDF417NotFound(t *testing.T) {
+	// A quiet zone and a start pattern without codewords is not a symbol.
+	page := image.NewGray(image.Rect(0, 0, 200, 50))
+	for i := range page.Pix {
+		page.Pix[i] = 0xff
+	}
+	for y := range 50 {
+		for x, width := 20, 0; width < 17*3; width++ {
+			if pdf417StartPatternBit(width / 3) {
+				page.SetGray(x+width, y, color.Gray{})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, page); err != nil {
+		t.Fatal(err)
+	}
+	barcodes, err := NewLayoutService().ReadBarcodes(buf.Bytes())
+	if err != nil || len(barcodes) != 0 {
+		t.Errorf("got %+v, %v; want no barcodes", barcodes, err)
+	}
+}
+
+// pdf417StartPatternBit reports whether module `i` of the start pattern is a bar.
+func pdf417StartPatternBit(i int) bool {
+	for k, width := range pdf417StartWidths {
+		if i < width {
+			return k%2 == 0
+		}
+		i -= width
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2" // For Huma-specific error types
+
+	"github.com/axyut/niyamAPI/internal/repository" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+)
+
+// DictionaryService defines the business logic for per-domain custom OCR vocabularies.
+type DictionaryService interface {
+	// CreateDictionary stores a new dictionary. `createdBy` is the ID of the admin creating it.
+	CreateDictionary(ctx context.Context, createdBy string, body types.OCRDictionaryBody) (*types.OCRDictionaryOutput, error)
+
+	// GetDictionary returns a dictionary, including its words and patterns.
+	GetDictionary(ctx context.Context, domain string) (*types.OCRDictionaryOutput, error)
+
+	// ListDictionaries returns summaries of all dictionaries.
+	ListDictionaries(ctx context.Context) (*types.OCRDictionariesOutput, error)
+
+	// UpdateDictionary replaces the description, language, words and patterns of a dictionary.
+	UpdateDictionary(ctx context.Context, domain string, body types.OCRDictionaryBody) (*types.OCRDictionaryOutput, error)
+
+	// DeleteDictionary removes a dictionary.
+	DeleteDictionary(ctx context.Context, domain string) error
+
+	// OptionsForDomain returns the dictionary's words and patterns as OCROptions,
+	// ready to be merged into a scan's options. Unknown domains yield a 400 error.
+	OptionsForDomain(ctx context.Context, domain string) (types.OCROptions, error)
+}
+
+// dictionaryService is the concrete implementation of DictionaryService.
+type dictionaryService struct {
+	dictRepo repository.DictionaryRepository
+}
+
+// NewDictionaryService creates and returns a new instance of DictionaryService.
+func NewDictionaryService(dictRepo repository.DictionaryRepository) DictionaryService {
+	return &dictionaryService{dictRepo: dictRepo}
+}
+
+// CreateDictionary validates and stores a new dictionary.
+func (s *dictionaryService) CreateDictionary(ctx context.Context, createdBy string, body types.OCRDictionaryBody) (*types.OCRDictionaryOutput, error) {
+	words, patterns, err := normalizeDictionaryEntries(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject duplicates up front, like user signup does for emails.
+	if _, err := s.dictRepo.GetDictionaryByDomain(ctx, body.Domain); err == nil {
+		return nil, huma.Error409Conflict(fmt.Sprintf("dictionary '%s' already exists", body.Domain), nil)
+	} else if err.Error() != "dictionary not found" {
+		log.Printf("ERROR: Unexpected error when checking for existing dictionary %s: %v", body.Domain, err)
+		return nil, fmt.Errorf("failed to check existing dictionary")
+	}
+
+	now := time.Now()
+	dict := &types.OCRDictionary{
+		Domain:      body.Domain,
+		Description: body.Description,
+		Language:    body.Language,
+		Words:       words,
+		Patterns:    patterns,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	created, err := s.dictRepo.CreateDictionary(ctx, dict)
+	if err != nil {
+		// The unique index also catches a concurrent create that passed the check above.
+		if err.Error() == "dictionary with this domain already exists" {
+			return nil, huma.Error409Conflict(fmt.Sprintf("dictionary '%s' already exists", body.Domain), nil)
+		}
+		log.Printf("ERROR: Service failed to create dictionary %s in repository: %v", body.Domain, err)
+		return nil, fmt.Errorf("failed to create dictionary")
+	}
+	return &types.OCRDictionaryOutput{Body: *created}, nil
+}
+
+// GetDictionary returns a dictionary, including its words and patterns.
+func (s *dictionaryService) GetDictionary(ctx context.Context, domain string) (*types.OCRDictionaryOutput, error) {
+	dict, err := s.getDictionary(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	return &types.OCRDictionaryOutput{Body: *dict}, nil
+}
+
+// ListDictionaries returns summaries of all dictionaries.
+func (s *dictionaryService) ListDictionaries(ctx context.Context) (*types.OCRDictionariesOutput, error) {
+	dicts, err := s.dictRepo.ListDictionaries(ctx)
+	if err != nil {
+		log.Printf("ERROR: Service failed to list dictionaries: %v", err)
+		return nil, fmt.Errorf("failed to list dictionaries")
+	}
+
+	resp := &types.OCRDictionariesOutput{}
+	resp.Body.Dictionaries = make([]types.OCRDictionarySummary, 0, len(dicts))
+	for _, dict := range dicts {
+		resp.Body.Dictionaries = append(resp.Body.Dictionaries, types.OCRDictionarySummary{
+			Domain:       dict.Domain,
+			Description:  dict.Description,
+			Language:     dict.Language,
+			WordCount:    len(dict.Words),
+			PatternCount: len(dict.Patterns),
+			UpdatedAt:    dict.UpdatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// UpdateDictionary replaces the contents of an existing dictionary. The domain itself cannot change.
+func (s *dictionaryService) UpdateDictionary(ctx context.Context, domain string, body types.OCRDictionaryBody) (*types.OCRDictionaryOutput, error) {
+	if body.Domain != domain {
+		return nil, huma.Error400BadRequest("dictionary domain in the body must match the path", nil)
+	}
+	words, patterns, err := normalizeDictionaryEntries(body)
+	if err != nil {
+		return nil, err
+	}
+
+	dict, err := s.getDictionary(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	dict.Description = body.Description
+	dict.Language = body.Language
+	dict.Words = words
+	dict.Patterns = patterns
+	dict.UpdatedAt = time.Now()
+
+	if err := s.dictRepo.UpdateDictionary(ctx, dict); err != nil {
+		if err.Error() == "dictionary not found" {
+			return nil, huma.Error404NotFound(fmt.Sprintf("dictionary '%s' not found", domain), nil)
+		}
+		log.Printf("ERROR: Service failed to update dictionary %s: %v", domain, err)
+		return nil, fmt.Errorf("failed to update dictionary")
+	}
+	return &types.OCRDictionaryOutput{Body: *dict}, nil
+}
+
+// DeleteDictionary removes a dictionary.
+func (s *dictionaryService) DeleteDictionary(ctx context.Context, domain string) error {
+	if err := s.dictRepo.DeleteDictionary(ctx, domain); err != nil {
+		if err.Error() == "dictionary not found" {
+			return huma.Error404NotFound(fmt.Sprintf("dictionary '%s' not found", domain), nil)
+		}
+		log.Printf("ERROR: Service failed to delete dictionary %s: %v", domain, err)
+		return fmt.Errorf("failed to delete dictionary")
+	}
+	return nil
+}
+
+// OptionsForDomain returns the dictionary's words and patterns as OCROptions.
+func (s *dictionaryService) OptionsForDomain(ctx context.Context, domain string) (types.OCROptions, error) {
+	dict, err := s.dictRepo.GetDictionaryByDomain(ctx, domain)
+	if err != nil {
+		// A scan naming an unknown dictionary is a client error, not a missing resource.
+		if err.Error() == "dictionary not found" {
+			return types.OCROptions{}, huma.Error400BadRequest(fmt.Sprintf("Unknown OCR dictionary '%s'. See GET /ocr/dictionaries.", domain), nil)
+		}
+		log.Printf("ERROR: Service failed to load dictionary %s for scan: %v", domain, err)
+		return types.OCROptions{}, fmt.Errorf("failed to load OCR dictionary")
+	}
+	return types.OCROptions{UserWords: dict.Words, UserPatterns: dict.Patterns}, nil
+}
+
+// getDictionary fetches a dictionary, converting repository errors into API errors.
+func (s *dictionaryService) getDictionary(ctx context.Context, domain string) (*types.OCRDictionary, error) {
+	dict, err := s.dictRepo.GetDictionaryByDomain(ctx, domain)
+	if err != nil {
+		if err.Error() == "dictionary not found" {
+			return nil, huma.Error404NotFound(fmt.Sprintf("dictionary '%s' not found", domain), nil)
+		}
+		log.Printf("ERROR: Service failed to get dictionary %s: %v", domain, err)
+		return nil, fmt.Errorf("failed to retrieve dictionary")
+	}
+	return dict, nil
+}
+
+// normalizeDictionaryEntries trims and de-duplicates words and patterns and checks them
+// against the same limits applied to per-request OCR options.
+func normalizeDictionaryEntries(body types.OCRDictionaryBody) ([]string, []string, error) {
+	words := dedupeEntries(body.Words)
+	patterns := dedupeEntries(body.Patterns)
+
+	if err := validateEntries("user word", words, maxUserWords, maxUserWordRunes); err != nil {
+		return nil, nil, huma.Error400BadRequest(fmt.Sprintf("Invalid dictionary: %v", err), nil)
+	}
+	if err := validateEntries("user pattern", patterns, maxUserPatterns, maxUserPatternRunes); err != nil {
+		return nil, nil, huma.Error400BadRequest(fmt.Sprintf("Invalid dictionary: %v", err), nil)
+	}
+	return words, patterns, nil
+}
+
+// dedupeEntries trims entries and removes empty and repeated ones, preserving order.
+func dedupeEntries(entries []string) []string {
+	seen := make(map[string]bool, len(entries))
+	out := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		out = append(out, entry)
+	}
+	return out
+}
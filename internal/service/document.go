@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2" // For Huma-specific error types
+
+	"github.com/axyut/niyamAPI/internal/repository" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+	"go.mongodb.org/mongo-driver/bson/primitive"    // For MongoDB ObjectID
+)
+
+// maxDocumentPages caps the number of pages a single document may reference.
+const maxDocumentPages = 1000
+
+// DocumentService defines the business logic for documents assembled from scans.
+// Documents are visible only to their owner and to admins; `user` is the authenticated caller.
+type DocumentService interface {
+	// CreateDocument creates a document owned by `user`, optionally with initial pages.
+	CreateDocument(ctx context.Context, user *types.AuthClaims, body types.DocumentBody) (*types.DocumentOutput, error)
+
+	// GetDocument returns a document, including its pages and version history.
+	GetDocument(ctx context.Context, user *types.AuthClaims, id string) (*types.DocumentOutput, error)
+
+	// ListDocuments returns the caller's documents (all documents for admins), newest first.
+	ListDocuments(ctx context.Context, user *types.AuthClaims) (*types.DocumentsOutput, error)
+
+	// AddPages attaches scans to a document before the 1-based page `position` (0 appends).
+	AddPages(ctx context.Context, user *types.AuthClaims, id string, scanIDs []string, position int) (*types.DocumentOutput, error)
+
+	// ReorderPages sets a new page order. `scanIDs` must contain exactly the current pages.
+	ReorderPages(ctx context.Context, user *types.AuthClaims, id string, scanIDs []string) (*types.DocumentOutput, error)
+
+	// GetDocumentText assembles the text of the document's pages at `version` (0 means current).
+	GetDocumentText(ctx context.Context, user *types.AuthClaims, id string, version int) (*types.DocumentTextOutput, error)
+}
+
+// documentService is the concrete implementation of DocumentService.
+type documentService struct {
+	docRepo     repository.DocumentRepository
+	scanRepo    repository.ScanRepository
+	searchIndex repository.SearchIndex
+}
+
+// NewDocumentService creates and returns a new instance of DocumentService.
+// The assembled text of every document is kept up to date in `searchIndex`.
+func NewDocumentService(docRepo repository.DocumentRepository, scanRepo repository.ScanRepository, searchIndex repository.SearchIndex) DocumentService {
+	return &documentService{docRepo: docRepo, scanRepo: scanRepo, searchIndex: searchIndex}
+}
+
+// CreateDocument creates a document owned by the caller.
+func (s *documentService) CreateDocument(ctx context.Context, user *types.AuthClaims, body types.DocumentBody) (*types.DocumentOutput, error) {
+	if strings.TrimSpace(body.Title) == "" {
+		return nil, huma.Error400BadRequest("document title must not be blank", nil)
+	}
+	scanIDs, err := s.checkScans(ctx, user, body.ScanIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	doc := &types.Document{
+		Title:     strings.TrimSpace(body.Title),
+		Type:      body.Type,
+		Language:  body.Language,
+		Tags:      dedupeEntries(body.Tags),
+		OwnerID:   user.UserID,
+		Pages:     []types.DocumentPage{},
+		Versions:  []types.DocumentVersion{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if doc.Type == "" {
+		doc.Type = "other"
+	}
+	for _, scanID := range scanIDs {
+		doc.Pages = append(doc.Pages, types.DocumentPage{ScanID: scanID, AddedAt: now})
+	}
+	recordDocumentVersion(doc, user, "created", now)
+
+	created, err := s.docRepo.CreateDocument(ctx, doc)
+	if err != nil {
+		log.Printf("ERROR: Service failed to create document '%s': %v", body.Title, err)
+		return nil, fmt.Errorf("failed to create document")
+	}
+	s.indexDocument(ctx, created)
+	return &types.DocumentOutput{Body: *created}, nil
+}
+
+// GetDocument returns a document visible to the caller.
+func (s *documentService) GetDocument(ctx context.Context, user *types.AuthClaims, id string) (*types.DocumentOutput, error) {
+	doc, err := s.getDocument(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	return &types.DocumentOutput{Body: *doc}, nil
+}
+
+// ListDocuments returns the documents visible to the caller.
+func (s *documentService) ListDocuments(ctx context.Context, user *types.AuthClaims) (*types.DocumentsOutput, error) {
+	ownerID := user.UserID
+	if user.Role == "admin" {
+		ownerID = ""
+	}
+
+	docs, err := s.docRepo.ListDocuments(ctx, ownerID)
+	if err != nil {
+		log.Printf("ERROR: Service failed to list documents for user %s: %v", user.UserID, err)
+		return nil, fmt.Errorf("failed to list documents")
+	}
+	resp := &types.DocumentsOutput{}
+	resp.Body.Documents = docs
+	return resp, nil
+}
+
+// AddPages attaches scans to a document at the given position.
+func (s *documentService) AddPages(ctx context.Context, user *types.AuthClaims, id string, scanIDs []string, position int) (*types.DocumentOutput, error) {
+	doc, err := s.getDocument(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Pages)+len(scanIDs) > maxDocumentPages {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("a document can have at most %d pages", maxDocumentPages), nil)
+	}
+	if position < 0 || position > len(doc.Pages)+1 {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("position must be between 1 and %d, or 0 to append", len(doc.Pages)+1), nil)
+	}
+	scanIDs, err = s.checkScans(ctx, user, scanIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	added := make([]types.DocumentPage, 0, len(scanIDs))
+	for _, scanID := range scanIDs {
+		added = append(added, types.DocumentPage{ScanID: scanID, AddedAt: now})
+	}
+	index := len(doc.Pages)
+	if position > 0 {
+		index = position - 1
+	}
+	pages := append(append(append([]types.DocumentPage{}, doc.Pages[:index]...), added...), doc.Pages[index:]...)
+
+	return s.updatePages(ctx, user, doc, pages, fmt.Sprintf("added %d page(s)", len(scanIDs)))
+}
+
+// ReorderPages sets a new page order for a document.
+func (s *documentService) ReorderPages(ctx context.Context, user *types.AuthClaims, id string, scanIDs []string) (*types.DocumentOutput, error) {
+	doc, err := s.getDocument(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new order must be a permutation of the current pages (a scan may appear more than once).
+	remaining := make(map[string][]types.DocumentPage, len(doc.Pages))
+	for _, page := range doc.Pages {
+		remaining[page.ScanID] = append(remaining[page.ScanID], page)
+	}
+	if len(scanIDs) != len(doc.Pages) {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("scanIds must list all %d current pages exactly once", len(doc.Pages)), nil)
+	}
+	pages := make([]types.DocumentPage, 0, len(scanIDs))
+	for _, scanID := range scanIDs {
+		scanID = strings.ToLower(strings.TrimSpace(scanID))
+		candidates := remaining[scanID]
+		if len(candidates) == 0 {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("scan '%s' is not a page of this document (or is listed too often)", scanID), nil)
+		}
+		pages = append(pages, candidates[0])
+		remaining[scanID] = candidates[1:]
+	}
+
+	return s.updatePages(ctx, user, doc, pages, "reordered pages")
+}
+
+// GetDocumentText assembles the document text from its pages' scans.
+func (s *documentService) GetDocumentText(ctx context.Context, user *types.AuthClaims, id string, version int) (*types.DocumentTextOutput, error) {
+	doc, err := s.getDocument(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var scanIDs []string
+	if version == 0 || version == doc.Version {
+		version = doc.Version
+		for _, page := range doc.Pages {
+			scanIDs = append(scanIDs, page.ScanID)
+		}
+	} else {
+		found := false
+		for _, v := range doc.Versions {
+			if v.Version == version {
+				scanIDs, found = v.ScanIDs, true
+				break
+			}
+		}
+		if !found {
+			return nil, huma.Error404NotFound(fmt.Sprintf("document version %d not found", version), nil)
+		}
+	}
+
+	text, err := s.assembleText(ctx, id, scanIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.DocumentTextOutput{}
+	resp.Body.DocumentID = doc.ID.Hex()
+	resp.Body.Version = version
+	resp.Body.Pages = len(scanIDs)
+	resp.Body.Text = text
+	return resp, nil
+}
+
+// assembleText joins the text of the given scans, in order, separated by blank lines.
+// Scans that no longer exist are skipped.
+func (s *documentService) assembleText(ctx context.Context, docID string, scanIDs []string) (string, error) {
+	objIDs := make([]primitive.ObjectID, 0, len(scanIDs))
+	for _, scanID := range scanIDs {
+		objID, _ := primitive.ObjectIDFromHex(scanID) // Validated when the page was attached.
+		objIDs = append(objIDs, objID)
+	}
+	scans, err := s.scanRepo.GetScansByIDs(ctx, objIDs)
+	if err != nil {
+		log.Printf("ERROR: Service failed to load scans for document %s: %v", docID, err)
+		return "", fmt.Errorf("failed to assemble document text")
+	}
+
+	texts := make([]string, 0, len(objIDs))
+	for _, objID := range objIDs {
+		scan, ok := scans[objID]
+		if !ok {
+			log.Printf("WARNING: Scan %s referenced by document %s no longer exists", objID.Hex(), docID)
+			continue
+		}
+		texts = append(texts, strings.TrimSpace(scan.Text))
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
+
+// indexDocument refreshes the search index entry of a document with its current assembled text.
+func (s *documentService) indexDocument(ctx context.Context, doc *types.Document) {
+	scanIDs := make([]string, 0, len(doc.Pages))
+	for _, page := range doc.Pages {
+		scanIDs = append(scanIDs, page.ScanID)
+	}
+	text, err := s.assembleText(ctx, doc.ID.Hex(), scanIDs)
+	if err != nil {
+		log.Printf("WARNING: Document %s was not re-indexed for search: %v", doc.ID.Hex(), err)
+		return
+	}
+	indexSearchItem(ctx, s.searchIndex, &types.SearchItem{
+		Kind:      types.SearchKindDocument,
+		ItemID:    doc.ID.Hex(),
+		OwnerID:   doc.OwnerID,
+		Language:  doc.Language,
+		Title:     doc.Title,
+		Text:      text,
+		CreatedAt: doc.CreatedAt,
+	})
+}
+
+// updatePages stores a new page list as the next document version.
+func (s *documentService) updatePages(ctx context.Context, user *types.AuthClaims, doc *types.Document, pages []types.DocumentPage, change string) (*types.DocumentOutput, error) {
+	expectedVersion := doc.Version
+	now := time.Now()
+	doc.Pages = pages
+	doc.UpdatedAt = now
+	recordDocumentVersion(doc, user, change, now)
+
+	if err := s.docRepo.UpdateDocument(ctx, doc, expectedVersion); err != nil {
+		if err.Error() == "document version conflict" {
+			return nil, huma.Error409Conflict("the document was changed by another request; reload it and try again", nil)
+		}
+		log.Printf("ERROR: Service failed to update pages of document %s: %v", doc.ID.Hex(), err)
+		return nil, fmt.Errorf("failed to update document")
+	}
+	s.indexDocument(ctx, doc)
+	return &types.DocumentOutput{Body: *doc}, nil
+}
+
+// getDocument fetches a document the caller may access. Documents of other users
+// are reported as not found so their existence is not revealed.
+func (s *documentService) getDocument(ctx context.Context, user *types.AuthClaims, id string) (*types.Document, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid document ID format", nil)
+	}
+
+	doc, err := s.docRepo.GetDocumentByID(ctx, objID)
+	if err != nil {
+		if err.Error() == "document not found" {
+			return nil, huma.Error404NotFound("document not found", nil)
+		}
+		log.Printf("ERROR: Service failed to get document by ID %s: %v", id, err)
+		return nil, fmt.Errorf("failed to retrieve document")
+	}
+	if doc.OwnerID != user.UserID && user.Role != "admin" {
+		return nil, huma.Error404NotFound("document not found", nil)
+	}
+	return doc, nil
+}
+
+// checkScans verifies that every scan exists and belongs to the caller (admins may use any scan),
+// and returns the IDs in canonical (lowercase hex) form.
+func (s *documentService) checkScans(ctx context.Context, user *types.AuthClaims, scanIDs []string) ([]string, error) {
+	if len(scanIDs) > maxDocumentPages {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("a document can have at most %d pages", maxDocumentPages), nil)
+	}
+	objIDs := make([]primitive.ObjectID, 0, len(scanIDs))
+	for _, scanID := range scanIDs {
+		objID, err := primitive.ObjectIDFromHex(strings.TrimSpace(scanID))
+		if err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid scan ID format: '%s'", scanID), nil)
+		}
+		objIDs = append(objIDs, objID)
+	}
+
+	scans, err := s.scanRepo.GetScansByIDs(ctx, objIDs)
+	if err != nil {
+		log.Printf("ERROR: Service failed to look up scans: %v", err)
+		return nil, fmt.Errorf("failed to look up scans")
+	}
+	canonical := make([]string, 0, len(objIDs))
+	for i, objID := range objIDs {
+		scan, ok := scans[objID]
+		if !ok || (scan.OwnerID != user.UserID && user.Role != "admin") {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("scan '%s' not found; only your own (authenticated) scans can be attached", scanIDs[i]), nil)
+		}
+		canonical = append(canonical, objID.Hex())
+	}
+	return canonical, nil
+}
+
+// recordDocumentVersion advances the document version and appends its page order to the history.
+func recordDocumentVersion(doc *types.Document, user *types.AuthClaims, change string, at time.Time) {
+	doc.Version++
+	scanIDs := make([]string, 0, len(doc.Pages))
+	for _, page := range doc.Pages {
+		scanIDs = append(scanIDs, page.ScanID)
+	}
+	doc.Versions = append(doc.Versions, types.DocumentVersion{
+		Version:   doc.Version,
+		ScanIDs:   scanIDs,
+		Change:    change,
+		ChangedBy: user.UserID,
+		ChangedAt: at,
+	})
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/danielgtaylor/huma/v2" // For Huma-specific error types
+
+	"github.com/axyut/niyamAPI/internal/repository" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils"      // Adjust import path to your module
+)
+
+// ExtractionService manages key-value extraction templates and reads form fields with them.
+type ExtractionService interface {
+	// CreateTemplate validates and stores a new template. `createdBy` is the ID of the admin creating it.
+	CreateTemplate(ctx context.Context, createdBy string, body types.ExtractionTemplateBody) (*types.ExtractionTemplateOutput, error)
+
+	// GetTemplate returns a template with its fields.
+	GetTemplate(ctx context.Context, name string) (*types.ExtractionTemplateOutput, error)
+
+	// ListTemplates returns summaries of all templates.
+	ListTemplates(ctx context.Context) (*types.ExtractionTemplatesOutput, error)
+
+	// UpdateTemplate replaces the description, language, preset and fields of a template.
+	UpdateTemplate(ctx context.Context, name string, body types.ExtractionTemplateBody) (*types.ExtractionTemplateOutput, error)
+
+	// DeleteTemplate removes a template.
+	DeleteTemplate(ctx context.Context, name string) error
+
+	// Extract OCRs a form image (of the given pixel size) with the template's language and preset and
+	// returns the template's fields as typed, validated values.
+	Extract(ctx context.Context, name string, imageData []byte, width, height int) (*types.ExtractOutput, error)
+}
+
+// extractionService is the concrete implementation of ExtractionService.
+type extractionService struct {
+	templateRepo repository.TemplateRepository
+	ocr          OCRService
+	presets      OCRPresetService
+	languages    LanguageService
+	text         TextService
+}
+
+// NewExtractionService creates and returns a new instance of ExtractionService.
+func NewExtractionService(templateRepo repository.TemplateRepository, ocr OCRService, presets OCRPresetService, languages LanguageService, text TextService) ExtractionService {
+	return &extractionService{templateRepo: templateRepo, ocr: ocr, presets: presets, languages: languages, text: text}
+}
+
+// CreateTemplate validates and stores a new template.
+func (s *extractionService) CreateTemplate(ctx context.Context, createdBy string, body types.ExtractionTemplateBody) (*types.ExtractionTemplateOutput, error) {
+	body, err := s.normalizeTemplate(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.templateRepo.GetTemplateByName(ctx, body.Name); err == nil {
+		return nil, huma.Error409Conflict(fmt.Sprintf("template '%s' already exists", body.Name), nil)
+	} else if err.Error() != "template not found" {
+		log.Printf("ERROR: Unexpected error when checking for existing template %s: %v", body.Name, err)
+		return nil, fmt.Errorf("failed to check existing template")
+	}
+
+	now := time.Now()
+	tmpl := &types.ExtractionTemplate{
+		Name:        body.Name,
+		Description: body.Description,
+		Language:    body.Language,
+		Preset:      body.Preset,
+		Fields:      body.Fields,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	created, err := s.templateRepo.CreateTemplate(ctx, tmpl)
+	if err != nil {
+		// The unique index also catches a concurrent create that passed the check above.
+		if err.Error() == "template with this name already exists" {
+			return nil, huma.Error409Conflict(fmt.Sprintf("template '%s' already exists", body.Name), nil)
+		}
+		log.Printf("ERROR: Service failed to create template %s in repository: %v", body.Name, err)
+		return nil, fmt.Errorf("failed to create template")
+	}
+	return &types.ExtractionTemplateOutput{Body: *created}, nil
+}
+
+// GetTemplate returns a template with its fields.
+func (s *extractionService) GetTemplate(ctx context.Context, name string) (*types.ExtractionTemplateOutput, error) {
+	tmpl, err := s.getTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ExtractionTemplateOutput{Body: *tmpl}, nil
+}
+
+// ListTemplates returns summaries of all templates.
+func (s *extractionService) ListTemplates(ctx context.Context) (*types.ExtractionTemplatesOutput, error) {
+	templates, err := s.templateRepo.ListTemplates(ctx)
+	if err != nil {
+		log.Printf("ERROR: Service failed to list templates: %v", err)
+		return nil, fmt.Errorf("failed to list templates")
+	}
+
+	resp := &types.ExtractionTemplatesOutput{}
+	resp.Body.Templates = make([]types.ExtractionTemplateSummary, 0, len(templates))
+	for _, tmpl := range templates {
+		fields := make([]string, 0, len(tmpl.Fields))
+		for _, field := range tmpl.Fields {
+			fields = append(fields, field.Name)
+		}
+		resp.Body.Templates = append(resp.Body.Templates, types.ExtractionTemplateSummary{
+			Name:        tmpl.Name,
+			Description: tmpl.Description,
+			Language:    tmpl.Language,
+			Fields:      fields,
+			UpdatedAt:   tmpl.UpdatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// UpdateTemplate replaces the contents of an existing template. The name itself cannot change.
+func (s *extractionService) UpdateTemplate(ctx context.Context, name string, body types.ExtractionTemplateBody) (*types.ExtractionTemplateOutput, error) {
+	if body.Name != name {
+		return nil, huma.Error400BadRequest("template name in the body must match the path", nil)
+	}
+	body, err := s.normalizeTemplate(body)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := s.getTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Description = body.Description
+	tmpl.Language = body.Language
+	tmpl.Preset = body.Preset
+	tmpl.Fields = body.Fields
+	tmpl.UpdatedAt = time.Now()
+
+	if err := s.templateRepo.UpdateTemplate(ctx, tmpl); err != nil {
+		if err.Error() == "template not found" {
+			return nil, huma.Error404NotFound(fmt.Sprintf("template '%s' not found", name), nil)
+		}
+		log.Printf("ERROR: Service failed to update template %s: %v", name, err)
+		return nil, fmt.Errorf("failed to update template")
+	}
+	return &types.ExtractionTemplateOutput{Body: *tmpl}, nil
+}
+
+// DeleteTemplate removes a template.
+func (s *extractionService) DeleteTemplate(ctx context.Context, name string) error {
+	if err := s.templateRepo.DeleteTemplate(ctx, name); err != nil {
+		if err.Error() == "template not found" {
+			return huma.Error404NotFound(fmt.Sprintf("template '%s' not found", name), nil)
+		}
+		log.Printf("ERROR: Service failed to delete template %s: %v", name, err)
+		return fmt.Errorf("failed to delete template")
+	}
+	return nil
+}
+
+// Extract OCRs a form image and reads the template's fields from the recognized words.
+func (s *extractionService) Extract(ctx context.Context, name string, imageData []byte, width, height int) (*types.ExtractOutput, error) {
+	tmpl, err := s.getTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	// The preset may have been removed from the server since the template was saved.
+	opts, err := s.presets.ResolveOptions(tmpl.Preset, types.OCROptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.ocr.ExtractTextFromImage(ctx, imageData, tmpl.Language, opts)
+	if err != nil {
+		log.Printf("ERROR: Failed to OCR form for template %s: %v", name, err)
+		var statusErr huma.StatusError
+		if errors.As(err, &statusErr) {
+			return nil, err
+		}
+		return nil, huma.Error400BadRequest(fmt.Sprintf("Failed to process image for OCR: %v", err), nil)
+	}
+
+	resp := &types.ExtractOutput{}
+	resp.Body.Template = tmpl.Name
+	resp.Body.Confidence = result.Confidence
+	resp.Body.Valid = true
+	resp.Body.Fields = make([]types.ExtractedField, 0, len(tmpl.Fields))
+	for _, field := range tmpl.Fields {
+		var words []types.OCRWord
+		if field.Region != nil {
+			words = regionWords(result.Words, field.Region, width, height)
+		} else {
+			words = anchoredWords(result, field.Anchor)
+		}
+		extracted := s.readField(field, words, opts)
+		resp.Body.Valid = resp.Body.Valid && extracted.Valid
+		resp.Body.Fields = append(resp.Body.Fields, extracted)
+	}
+	return resp, nil
+}
+
+// readField converts the words found for a field into its typed value and validates it.
+func (s *extractionService) readField(field types.TemplateField, words []types.OCRWord, opts types.OCROptions) types.ExtractedField {
+	extracted := types.ExtractedField{Name: field.Name, Type: field.Type, Valid: true}
+	if len(words) > 0 {
+		extracted.Text = joinOCRWords(words, opts)
+		if field.Anchor != nil {
+			extracted.Text = strings.TrimLeft(extracted.Text, anchorSeparators)
+		}
+		extracted.Confidence = ocrConfidence(words, func(types.OCRWord) bool { return true })
+		var box image.Rectangle
+		for _, word := range words {
+			box = box.Union(image.Rect(word.Box.X, word.Box.Y, word.Box.X+word.Box.Width, word.Box.Y+word.Box.Height))
+		}
+		b := ocrBox(box)
+		extracted.Box = &b
+	}
+	if extracted.Text == "" {
+		if field.Required {
+			extracted.Valid, extracted.Error = false, "required field not found"
+		}
+		return extracted
+	}
+
+	collapsed := strings.Join(strings.Fields(extracted.Text), " ")
+	switch field.Type {
+	case types.FieldTypeNumber:
+		digits := strings.NewReplacer(",", "", " ", "").Replace(utils.ConvertDigits(collapsed, utils.DigitsASCII))
+		number, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			extracted.Valid, extracted.Error = false, "value is not a number"
+			return extracted
+		}
+		extracted.Value = strconv.FormatFloat(number, 'f', -1, 64)
+	case types.FieldTypeDate:
+		dates := s.text.ExtractDates(collapsed)
+		if len(dates) == 0 {
+			extracted.Valid, extracted.Error = false, "value is not a date"
+			return extracted
+		}
+		extracted.Date = &dates[0]
+		// The value is the date in the calendar it is written in.
+		extracted.Value = extracted.Date.AD
+		if extracted.Date.Calendar == "BS" {
+			extracted.Value = extracted.Date.BS
+		}
+		if extracted.Value == "" {
+			extracted.Valid, extracted.Error = false, "date is outside the supported range"
+			return extracted
+		}
+	case types.FieldTypeID:
+		// Identifiers keep letters, digits and the separators printed on Nepali documents.
+		extracted.Value = strings.Map(func(r rune) rune {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) || strings.ContainsRune("-/.", r) {
+				return unicode.ToUpper(r)
+			}
+			return -1
+		}, utils.ConvertDigits(collapsed, utils.DigitsASCII))
+		if !strings.ContainsFunc(extracted.Value, unicode.IsDigit) {
+			extracted.Valid, extracted.Error = false, "value is not an identifier"
+			return extracted
+		}
+	default:
+		extracted.Value = collapsed
+	}
+
+	if field.Pattern != "" {
+		// Patterns are checked when a template is saved, but stored templates are not trusted to compile.
+		pattern, err := regexp.Compile(field.Pattern)
+		switch {
+		case err != nil:
+			log.Printf("WARNING: Invalid pattern of template field %s: %v", field.Name, err)
+			extracted.Valid, extracted.Error = false, "the field's pattern is invalid"
+		case !pattern.MatchString(extracted.Value):
+			extracted.Valid, extracted.Error = false, "value does not match the pattern"
+		}
+	}
+	return extracted
+}
+
+// anchorSeparators are the characters printed between a label and its value (':' is often written
+// as a visarga in Nepali forms). They are trimmed from the start of anchored field text.
+const anchorSeparators = ":ः-–—|. \n"
+
+// regionWords returns the words whose centers lie inside a normalized region of the page.
+func regionWords(words []types.OCRWord, region *types.TemplateRegion, width, height int) []types.OCRWord {
+	rect := image.Rect(
+		int(region.X*float64(width)), int(region.Y*float64(height)),
+		int((region.X+region.Width)*float64(width)), int((region.Y+region.Height)*float64(height)),
+	)
+	var selected []types.OCRWord
+	for _, word := range words {
+		if image.Pt(word.Box.X+word.Box.Width/2, word.Box.Y+word.Box.Height/2).In(rect) {
+			selected = append(selected, word)
+		}
+	}
+	return selected
+}
+
+// anchoredWords finds the first line containing the anchor's label and returns the words after it on
+// that line ('right') or on the nearest line below it ('below').
+func anchoredWords(result *types.OCRResult, anchor *types.TemplateAnchor) []types.OCRWord {
+	lineWords := make([][]types.OCRWord, len(result.Lines))
+	for _, word := range result.Words {
+		if word.Line >= 0 && word.Line < len(lineWords) {
+			lineWords[word.Line] = append(lineWords[word.Line], word)
+		}
+	}
+	label := normalizeLabel(anchor.Label)
+
+	for i, words := range lineWords {
+		sort.SliceStable(words, func(a, b int) bool { return words[a].Box.X < words[b].Box.X })
+		first, last, ok := findLabel(words, label)
+		if !ok {
+			continue
+		}
+		if anchor.Direction != types.AnchorBelow {
+			return words[last+1:]
+		}
+
+		// The value is on the closest following line that starts left of the label's end and ends right of its start.
+		labelBox := types.OCRBox{X: words[first].Box.X, Y: words[first].Box.Y}
+		labelBox.Width = words[last].Box.X + words[last].Box.Width - labelBox.X
+		labelBox.Height = words[first].Box.Height
+		best := -1
+		for j, line := range result.Lines {
+			if j == i || line.Box.Y < labelBox.Y+labelBox.Height/2 || line.Box.X >= labelBox.X+labelBox.Width || line.Box.X+line.Box.Width <= labelBox.X {
+				continue
+			}
+			if best < 0 || line.Box.Y < result.Lines[best].Box.Y {
+				best = j
+			}
+		}
+		if best < 0 {
+			return nil
+		}
+		var below []types.OCRWord
+		for _, word := range lineWords[best] {
+			if word.Box.X+word.Box.Width > labelBox.X-labelBox.Height {
+				below = append(below, word)
+			}
+		}
+		return below
+	}
+	return nil
+}
+
+// findLabel returns the range of words (inclusive) whose joined text contains `label`.
+func findLabel(words []types.OCRWord, label string) (first, last int, ok bool) {
+	var text strings.Builder
+	starts := make([]int, len(words))
+	for i, word := range words {
+		if i > 0 {
+			text.WriteByte(' ')
+		}
+		starts[i] = text.Len()
+		text.WriteString(normalizeLabel(word.Text))
+	}
+	at := strings.Index(text.String(), label)
+	if label == "" || at < 0 {
+		return 0, 0, false
+	}
+	end := at + len(label)
+	first = sort.SearchInts(starts, at+1) - 1
+	last = sort.SearchInts(starts, end) - 1
+	return first, last, true
+}
+
+// normalizeLabel prepares text for case-insensitive label matching.
+func normalizeLabel(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(utils.NormalizeDevanagari(text)), " "))
+}
+
+// getTemplate fetches a template, converting repository errors into API errors.
+func (s *extractionService) getTemplate(ctx context.Context, name string) (*types.ExtractionTemplate, error) {
+	tmpl, err := s.templateRepo.GetTemplateByName(ctx, name)
+	if err != nil {
+		if err.Error() == "template not found" {
+			return nil, huma.Error404NotFound(fmt.Sprintf("template '%s' not found", name), nil)
+		}
+		log.Printf("ERROR: Service failed to get template %s: %v", name, err)
+		return nil, fmt.Errorf("failed to retrieve template")
+	}
+	return tmpl, nil
+}
+
+// normalizeTemplate fills in defaults and validates the language, preset and fields of a template.
+func (s *extractionService) normalizeTemplate(body types.ExtractionTemplateBody) (types.ExtractionTemplateBody, error) {
+	invalid := func(format string, args ...any) (types.ExtractionTemplateBody, error) {
+		return body, huma.Error400BadRequest("Invalid template: "+fmt.Sprintf(format, args...), nil)
+	}
+
+	body.Language = strings.TrimSpace(body.Language)
+	if body.Language == "" {
+		body.Language = types.LangEnglish
+	}
+	for _, code := range strings.Split(body.Language, "+") {
+		if !s.languages.IsSupported(code) {
+			return invalid("language '%s' is not installed (see GET /ocr/languages)", code)
+		}
+	}
+	body.Preset = strings.TrimSpace(body.Preset)
+	if _, err := s.presets.ResolveOptions(body.Preset, types.OCROptions{}); err != nil {
+		return body, err
+	}
+
+	seen := make(map[string]bool, len(body.Fields))
+	fields := make([]types.TemplateField, len(body.Fields))
+	for i, field := range body.Fields {
+		if seen[field.Name] {
+			return invalid("field '%s' is defined twice", field.Name)
+		}
+		seen[field.Name] = true
+		if field.Type == "" {
+			field.Type = types.FieldTypeText
+		}
+		if (field.Region == nil) == (field.Anchor == nil) {
+			return invalid("field '%s' needs exactly one of 'region' and 'anchor'", field.Name)
+		}
+		if r := field.Region; r != nil && (r.X+r.Width > 1 || r.Y+r.Height > 1) {
+			return invalid("region of field '%s' extends beyond the page", field.Name)
+		}
+		if a := field.Anchor; a != nil {
+			anchor := *a
+			anchor.Label = strings.TrimSpace(anchor.Label)
+			if normalizeLabel(anchor.Label) == "" {
+				return invalid("anchor label of field '%s' is empty", field.Name)
+			}
+			if anchor.Direction == "" {
+				anchor.Direction = types.AnchorRight
+			}
+			field.Anchor = &anchor
+		}
+		if field.Pattern != "" {
+			if _, err := regexp.Compile(field.Pattern); err != nil {
+				return invalid("pattern of field '%s': %v", field.Name, err)
+			}
+		}
+		fields[i] = field
+	}
+	body.Fields = fields
+	return body, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// defaultTessdataDirs are probed, in order, when no tessdata directory is configured.
+var defaultTessdataDirs = []string{
+	"/usr/share/tesseract-ocr/5/tessdata",
+	"/usr/share/tesseract-ocr/4.00/tessdata",
+	"/usr/share/tessdata",
+	"/usr/local/share/tessdata",
+	"/opt/homebrew/share/tessdata",
+}
+
+// ignoredTraineddata are models that are installed alongside languages but cannot be used for recognition.
+var ignoredTraineddata = map[string]bool{
+	"osd": true, // Orientation and script detection only
+	"equ": true, // Legacy math/equation model
+}
+
+// LanguageService exposes the set of OCR languages installed on this server.
+// The set is discovered once at startup from the tessdata directory.
+type LanguageService interface {
+	// ListLanguages returns all installed languages, sorted by code.
+	ListLanguages() []types.OCRLanguage
+
+	// IsSupported reports whether a single language code is installed.
+	IsSupported(code string) bool
+
+	// Codes returns the sorted list of installed language codes.
+	Codes() []string
+
+	// TessdataDir returns the directory the languages were discovered in.
+	TessdataDir() string
+}
+
+// languageService is the concrete, read-only implementation of LanguageService.
+type languageService struct {
+	tessdataDir string
+	languages   []types.OCRLanguage
+	codes       map[string]bool
+}
+
+// NewLanguageService discovers installed traineddata files in `tessdataDir`
+// (or a well-known default location when empty) and returns a LanguageService.
+// A missing directory is not fatal: the service then reports no languages and
+// every scan is rejected with a descriptive error.
+func NewLanguageService(tessdataDir string) LanguageService {
+	if tessdataDir == "" {
+		tessdataDir = detectTessdataDir()
+	}
+
+	codes, err := discoverLanguageCodes(tessdataDir)
+	if err != nil {
+		log.Printf("WARNING: Failed to discover OCR languages in '%s': %v", tessdataDir, err)
+	}
+
+	s := &languageService{
+		tessdataDir: tessdataDir,
+		codes:       make(map[string]bool, len(codes)),
+	}
+	for _, code := range codes {
+		info, ok := types.KnownOCRLanguages[code]
+		if !ok {
+			info = types.OCRLanguageInfo{Name: code, Script: "Unknown"}
+		}
+		s.codes[code] = true
+		s.languages = append(s.languages, types.OCRLanguage{Code: code, Name: info.Name, Script: info.Script})
+	}
+
+	log.Printf("INFO: Discovered %d OCR language(s) in '%s': %s", len(codes), tessdataDir, strings.Join(codes, ", "))
+	return s
+}
+
+// ListLanguages returns all installed languages, sorted by code.
+func (s *languageService) ListLanguages() []types.OCRLanguage {
+	return append([]types.OCRLanguage(nil), s.languages...)
+}
+
+// IsSupported reports whether a single language code is installed.
+func (s *languageService) IsSupported(code string) bool {
+	return s.codes[code]
+}
+
+// Codes returns the sorted list of installed language codes.
+func (s *languageService) Codes() []string {
+	codes := make([]string, 0, len(s.languages))
+	for _, lang := range s.languages {
+		codes = append(codes, lang.Code)
+	}
+	return codes
+}
+
+// TessdataDir returns the directory the languages were discovered in.
+func (s *languageService) TessdataDir() string {
+	return s.tessdataDir
+}
+
+// detectTessdataDir returns the first well-known tessdata directory that exists.
+func detectTessdataDir() string {
+	for _, dir := range defaultTessdataDirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return defaultTessdataDirs[0]
+}
+
+// discoverLanguageCodes lists the *.traineddata models in `dir` and its `script/` subdirectory.
+// Script models are reported as "script/<Name>", which is how Tesseract expects them.
+func discoverLanguageCodes(dir string) ([]string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("tessdata directory is not accessible: %w", err)
+	}
+
+	var codes []string
+	for _, pattern := range []string{"*.traineddata", filepath.Join("script", "*.traineddata")} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list traineddata files: %w", err)
+		}
+		for _, match := range matches {
+			rel, err := filepath.Rel(dir, match)
+			if err != nil {
+				continue
+			}
+			code := filepath.ToSlash(strings.TrimSuffix(rel, ".traineddata"))
+			if ignoredTraineddata[code] {
+				continue
+			}
+			codes = append(codes, code)
+		}
+	}
+
+	sort.Strings(codes)
+	return codes, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path to your module
+)
+
+// LayoutService analyzes the layout of page images together with their OCR results.
+type LayoutService interface {
+	// ExtractTables detects the tables on a page image and fills their cells with the OCR words of `result`.
+	// Cell text is post-processed like the page text. The result is never nil.
+	ExtractTables(imageData []byte, result *types.OCRResult, opts types.OCROptions) ([]types.DetectedTable, error)
+
+	// FlagUnreliableText classifies the lines of `result` by word confidence and shape (word height spread,
+	// baseline jitter, stray symbols) and warns about regions of likely handwriting or low-quality print.
+	// With `exclude`, it returns the result's text without those regions, post-processed like page text;
+	// otherwise it returns result.Text unchanged.
+	FlagUnreliableText(result *types.OCRResult, opts types.OCROptions, exclude bool) (string, []types.ScanWarning)
+
+	// ReadBarcodes decodes the QR codes and barcodes on a page image. The result is never nil.
+	ReadBarcodes(imageData []byte) ([]types.DetectedBarcode, error)
+}
+
+// layoutService is the concrete, stateless implementation of LayoutService.
+type layoutService struct{}
+
+// NewLayoutService creates and returns a new instance of LayoutService.
+func NewLayoutService() LayoutService {
+	return &layoutService{}
+}
+
+// ExtractTables detects tables on the page and reconstructs their cells.
+func (s *layoutService) ExtractTables(imageData []byte, result *types.OCRResult, opts types.OCROptions) ([]types.DetectedTable, error) {
+	img, _, err := utils.DecodeImage(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot detect tables: %w", err)
+	}
+
+	wordBoxes := make([]image.Rectangle, len(result.Words))
+	heights := make([]int, len(result.Words))
+	for i, word := range result.Words {
+		wordBoxes[i] = image.Rect(word.Box.X, word.Box.Y, word.Box.X+word.Box.Width, word.Box.Y+word.Box.Height)
+		heights[i] = word.Box.Height
+	}
+	textHeight := 0
+	if len(heights) > 0 {
+		sort.Ints(heights)
+		textHeight = heights[len(heights)/2]
+	}
+
+	grids := utils.DetectTables(utils.Grayscale(img), wordBoxes, textHeight)
+	tables := make([]types.DetectedTable, 0, len(grids))
+	for _, grid := range grids {
+		table, err := buildTable(grid, result.Words, opts)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// buildTable assigns the words to the cells of a grid by their centers and renders the rows as CSV.
+func buildTable(grid utils.TableGrid, words []types.OCRWord, opts types.OCROptions) (types.DetectedTable, error) {
+	rows, columns := len(grid.Rows)-1, len(grid.Columns)-1
+	cellWords := make([][][]types.OCRWord, rows)
+	for r := range cellWords {
+		cellWords[r] = make([][]types.OCRWord, columns)
+	}
+
+	var inTable []types.OCRWord
+	for _, word := range words {
+		cx, cy := word.Box.X+word.Box.Width/2, word.Box.Y+word.Box.Height/2
+		r := sort.SearchInts(grid.Rows, cy+1) - 1
+		c := sort.SearchInts(grid.Columns, cx+1) - 1
+		if r < 0 || r >= rows || c < 0 || c >= columns {
+			continue
+		}
+		cellWords[r][c] = append(cellWords[r][c], word)
+		inTable = append(inTable, word)
+	}
+
+	table := types.DetectedTable{
+		Box:         ocrBox(grid.Bounds),
+		Source:      types.TableSourceGrid,
+		Rows:        make([][]string, rows),
+		RowCount:    rows,
+		ColumnCount: columns,
+		Confidence:  ocrConfidence(inTable, func(types.OCRWord) bool { return true }),
+	}
+	if grid.Ruled {
+		table.Source = types.TableSourceRuled
+	}
+	for r := range cellWords {
+		table.Rows[r] = make([]string, columns)
+		for c, cell := range cellWords[r] {
+			table.Rows[r][c] = joinOCRWords(cell, opts)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(table.Rows); err != nil {
+		return table, fmt.Errorf("failed to write table CSV: %w", err)
+	}
+	table.CSV = buf.String()
+	return table, nil
+}
+
+// joinOCRWords joins words in reading order: words of a line with spaces, lines with newlines. The words
+// are sorted in place, and the text is post-processed like page text.
+func joinOCRWords(words []types.OCRWord, opts types.OCROptions) string {
+	sort.SliceStable(words, func(i, j int) bool {
+		if words[i].Line != words[j].Line {
+			return words[i].Line < words[j].Line
+		}
+		return words[i].Box.X < words[j].Box.X
+	})
+	var text strings.Builder
+	for i, word := range words {
+		if i > 0 {
+			if word.Line != words[i-1].Line {
+				text.WriteByte('\n')
+			} else {
+				text.WriteByte(' ')
+			}
+		}
+		text.WriteString(word.Text)
+	}
+	return strings.TrimSpace(PostProcessText(text.String(), opts))
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"unicode"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// Line classification thresholds. Tesseract reads clean print at 80-95% confidence; handwriting
+// typically scores below 60 and, unlike print, has irregular word heights and a wavy baseline.
+const (
+	// trustedLineConfidence is the confidence from which a line counts as print whatever its shape.
+	trustedLineConfidence = 75
+	// lowLineConfidence is the confidence below which a print-shaped line is flagged as low quality.
+	lowLineConfidence = 50
+	// handwritingScore is the handwriting score (0-1) from which an untrusted line counts as handwriting.
+	handwritingScore = 0.45
+)
+
+// lineFeatures are the measurements the handwriting score is computed from.
+type lineFeatures struct {
+	confidence     float64
+	heightSpread   float64 // Coefficient of variation of the word heights
+	baselineJitter float64 // Deviation of the word bottoms from a straight baseline, in text heights
+	junk           float64 // Fraction of characters that are neither letters, marks, digits nor common punctuation
+	sizeDeviation  float64 // |log| of the line's text height relative to the page's
+}
+
+// FlagUnreliableText classifies the lines of an OCR result and returns warnings for regions of
+// consecutive handwriting or low-quality lines.
+func (s *layoutService) FlagUnreliableText(result *types.OCRResult, opts types.OCROptions, exclude bool) (string, []types.ScanWarning) {
+	if len(result.Lines) == 0 {
+		return result.Text, nil
+	}
+
+	lineWords := make([][]types.OCRWord, len(result.Lines))
+	var heights []float64
+	for _, word := range result.Words {
+		if word.Line >= 0 && word.Line < len(lineWords) {
+			lineWords[word.Line] = append(lineWords[word.Line], word)
+			heights = append(heights, float64(word.Box.Height))
+		}
+	}
+	pageHeight := median(heights)
+
+	kinds := make([]string, len(result.Lines))
+	for i, line := range result.Lines {
+		kinds[i] = classifyLine(measureLine(line, lineWords[i], pageHeight))
+	}
+
+	var warnings []types.ScanWarning
+	var regionLines []int
+	flush := func() {
+		if len(regionLines) == 0 {
+			return
+		}
+		warnings = append(warnings, lineRegionWarning(result, kinds[regionLines[0]], regionLines, exclude))
+		regionLines = nil
+	}
+	for i, line := range result.Lines {
+		if kinds[i] == "" {
+			flush()
+			continue
+		}
+		// A region continues with the next line of the same kind unless there is a clear vertical gap.
+		if len(regionLines) > 0 {
+			last := result.Lines[regionLines[len(regionLines)-1]]
+			gap := float64(line.Box.Y - (last.Box.Y + last.Box.Height))
+			if kinds[i] != kinds[regionLines[0]] || gap > 1.5*pageHeight {
+				flush()
+			}
+		}
+		regionLines = append(regionLines, i)
+	}
+	flush()
+
+	if !exclude || len(warnings) == 0 {
+		return result.Text, warnings
+	}
+	return PostProcessText(ocrLinesText(result.Lines, func(i int) bool { return kinds[i] == "" }), opts), warnings
+}
+
+// measureLine computes the shape and confidence features of a line.
+func measureLine(line types.OCRLine, words []types.OCRWord, pageHeight float64) lineFeatures {
+	features := lineFeatures{confidence: line.Confidence}
+
+	heights := make([]float64, len(words))
+	for i, word := range words {
+		heights[i] = float64(word.Box.Height)
+	}
+	lineHeight := median(heights)
+	if len(words) >= 2 && lineHeight > 0 {
+		mean, std := meanStd(heights)
+		features.heightSpread = std / mean
+
+		// Fit a straight baseline through the word bottoms (least squares) and measure the residuals.
+		var sx, sy, sxx, sxy float64
+		for _, word := range words {
+			x, y := float64(word.Box.X)+float64(word.Box.Width)/2, float64(word.Box.Y+word.Box.Height)
+			sx, sy, sxx, sxy = sx+x, sy+y, sxx+x*x, sxy+x*y
+		}
+		n := float64(len(words))
+		slope := 0.0
+		if d := n*sxx - sx*sx; d != 0 {
+			slope = (n*sxy - sx*sy) / d
+		}
+		intercept := (sy - slope*sx) / n
+		var residuals float64
+		for _, word := range words {
+			x, y := float64(word.Box.X)+float64(word.Box.Width)/2, float64(word.Box.Y+word.Box.Height)
+			residuals += math.Pow(y-(slope*x+intercept), 2)
+		}
+		features.baselineJitter = math.Sqrt(residuals/n) / lineHeight
+	}
+	if lineHeight > 0 && pageHeight > 0 {
+		features.sizeDeviation = math.Abs(math.Log(lineHeight / pageHeight))
+	}
+
+	var runes, junk int
+	for _, r := range line.Text {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		runes++
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r) && !unicode.In(r, unicode.Po, unicode.Pd, unicode.Ps, unicode.Pe) {
+			junk++
+		}
+	}
+	if runes > 0 {
+		features.junk = float64(junk) / float64(runes)
+	}
+	return features
+}
+
+// classifyLine returns the warning kind of a line, or "" for print.
+func classifyLine(f lineFeatures) string {
+	if f.confidence >= trustedLineConfidence {
+		return ""
+	}
+	// Each feature contributes 0-1; low confidence and irregular shape weigh most.
+	score := (clamp01((trustedLineConfidence-f.confidence)/trustedLineConfidence)*1.5 +
+		clamp01(f.heightSpread/0.35) +
+		clamp01(f.baselineJitter/0.25) +
+		clamp01(f.junk/0.3) +
+		clamp01(f.sizeDeviation/math.Log(1.8))*0.5) / 5
+	switch {
+	case score >= handwritingScore:
+		return types.WarningHandwriting
+	case f.confidence < lowLineConfidence:
+		return types.WarningLowQuality
+	}
+	return ""
+}
+
+// lineRegionWarning describes a region made of the given lines of a result.
+func lineRegionWarning(result *types.OCRResult, kind string, lines []int, excluded bool) types.ScanWarning {
+	var box image.Rectangle
+	var words []types.OCRWord
+	inRegion := make(map[int]bool, len(lines))
+	for _, i := range lines {
+		b := result.Lines[i].Box
+		box = box.Union(image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height))
+		inRegion[i] = true
+	}
+	for _, word := range result.Words {
+		if inRegion[word.Line] {
+			words = append(words, word)
+		}
+	}
+
+	warning := types.ScanWarning{
+		Kind:       kind,
+		Box:        ocrBox(box),
+		Lines:      len(lines),
+		Confidence: ocrConfidence(words, func(types.OCRWord) bool { return true }),
+		Text:       ocrLinesText(result.Lines, func(i int) bool { return inRegion[i] }),
+		Excluded:   excluded,
+	}
+	description := "Likely handwriting"
+	if kind == types.WarningLowQuality {
+		description = "Low-confidence text"
+	}
+	warning.Message = fmt.Sprintf("%s (%d line(s), confidence %.1f); its text is unreliable.", description, warning.Lines, warning.Confidence)
+	if excluded {
+		warning.Message += " It was left out of the scan text."
+	}
+	return warning
+}
+
+// median returns the median of values, or 0 for none. The slice is sorted in place.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	return values[len(values)/2]
+}
+
+// meanStd returns the mean and standard deviation of values.
+func meanStd(values []float64) (mean, std float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(values)))
+}
+
+// clamp01 limits v to the range 0-1.
+func clamp01(v float64) float64 {
+	return min(max(v, 0), 1)
+}
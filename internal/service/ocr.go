@@ -2,57 +2,147 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
-	"github.com/otiai10/gosseract/v2"
+	"github.com/danielgtaylor/huma/v2" // For Huma-specific error types
+
+	"github.com/axyut/niyamAPI/internal/config" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"  // Adjust import path to your module
 )
 
+// StatusClientClosedRequest is the non-standard (nginx-style) status code reported
+// when the client disconnects before the OCR scan has finished.
+const StatusClientClosedRequest = 499
+
+// ocrPipelineVersion is bumped whenever a code change makes the service return different text
+// for the same image and options, so results cached by older versions are not reused.
+const ocrPipelineVersion = 2
+
+// ocrResultVersion identifies the engine build and pipeline version producing OCR results.
+func ocrResultVersion(engine OCREngine) string {
+	return fmt.Sprintf("%s %s; pipeline %d", engine.Name(), engine.Version(), ocrPipelineVersion)
+}
+
 // OCRService defines the interface for OCR-related business logic.
 type OCRService interface {
-	// ExtractTextFromImage now accepts raw image data as a byte slice and a language string.
-	ExtractTextFromImage(ctx context.Context, imageData []byte, language string) (string, error)
+	// ExtractTextFromImage now accepts raw image data as a byte slice, a language string
+	// and validated Tesseract tuning options (see OCRPresetService.ResolveOptions).
+	// `opts.Engine` selects the OCR engine; empty means the server default.
+	// It honors cancellation of ctx and the service's configured maximum scan duration.
+	ExtractTextFromImage(ctx context.Context, imageData []byte, language string, opts types.OCROptions) (*types.OCRResult, error)
 }
 
 // ocrService implements the OCRService interface.
 type ocrService struct {
-	// Add any dependencies for the OCR service here, e.g., configuration for Tesseract
+	timeout time.Duration     // Maximum duration of a single scan
+	slots   chan struct{}     // Semaphore bounding concurrently running OCR jobs, across all engines
+	engines OCREngineRegistry // Engines scans are run with
 }
 
 // NewOCRService creates a new instance of OCRService.
-func NewOCRService() OCRService {
-	return &ocrService{}
+// `cfg.OCRTimeout` caps how long a single scan may take, and `cfg.OCRMaxConcurrency` caps how many
+// OCR jobs may run at the same time (including ones abandoned after a timeout).
+// Scans run on the engine selected from `engines`.
+func NewOCRService(cfg *config.AppConfig, engines OCREngineRegistry) OCRService {
+	maxConcurrency := cfg.OCRMaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	return &ocrService{
+		timeout: cfg.OCRTimeout,
+		slots:   make(chan struct{}, maxConcurrency),
+		engines: engines,
+	}
+}
+
+// OCRProgressFunc receives the pipeline stage (types.ScanStageQueued, ...Preprocessing or
+// ...Recognizing) each time an OCR scan advances. It may be called from another goroutine.
+type OCRProgressFunc func(stage string)
+
+// ocrProgressKey is the private context key under which an OCRProgressFunc is stored.
+type ocrProgressKey struct{}
+
+// WithOCRProgress returns a context that makes ExtractTextFromImage report its progress to fn.
+func WithOCRProgress(ctx context.Context, fn OCRProgressFunc) context.Context {
+	return context.WithValue(ctx, ocrProgressKey{}, fn)
+}
+
+// ocrProgress returns the progress callback stored in ctx, or a no-op.
+func ocrProgress(ctx context.Context) OCRProgressFunc {
+	if fn, ok := ctx.Value(ocrProgressKey{}).(OCRProgressFunc); ok && fn != nil {
+		return fn
+	}
+	return func(string) {}
+}
+
+// ocrResult carries the outcome of an engine run back to the waiting request.
+type ocrResult struct {
+	result *types.OCRResult
+	err    error
 }
 
 // ExtractTextFromImage performs OCR on raw image data using the specified language(s).
-func (s *ocrService) ExtractTextFromImage(ctx context.Context, imageData []byte, language string) (string, error) {
-	// Ensure that image data is not empty to avoid errors with gosseract.
+func (s *ocrService) ExtractTextFromImage(ctx context.Context, imageData []byte, language string, opts types.OCROptions) (*types.OCRResult, error) {
+	// Ensure that image data is not empty to avoid errors with the engine.
 	if len(imageData) == 0 {
-		return "", fmt.Errorf("empty image data provided")
+		return nil, fmt.Errorf("empty image data provided")
+	}
+	engine, err := s.engines.Engine(opts.Engine)
+	if err != nil {
+		return nil, err
 	}
 
-	client := gosseract.NewClient()
-	defer client.Close() // Crucial: Ensure the Tesseract client is closed after use to release resources.
-
-	// Set the OCR language(s). Tesseract accepts comma or plus-separated codes (e.g., "eng", "nep", "hin", "eng+nep").
-	// This must be done before setting the image.
-	if err := client.SetLanguage(language); err != nil {
-		log.Printf("ERROR: Failed to set OCR language '%s': %v", language, err)
-		return "", fmt.Errorf("unsupported OCR language or missing language data for '%s'", language)
+	// Bound the whole scan (waiting for a slot + recognition) by the configured timeout.
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
 	}
 
-	// Set the image for OCR directly from the byte slice.
-	if err := client.SetImageFromBytes(imageData); err != nil {
-		log.Printf("ERROR: Failed to set image for OCR from bytes: %v", err)
-		return "", fmt.Errorf("failed to prepare image for OCR processing")
+	progress := ocrProgress(ctx)
+
+	// Wait for a free OCR slot, giving up if the request is cancelled meanwhile.
+	progress(types.ScanStageQueued)
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, s.contextError(ctx, language)
 	}
 
-	// Perform the Optical Character Recognition.
-	text, err := client.Text()
-	if err != nil {
-		log.Printf("ERROR: Failed to extract text from image using Tesseract (language: %s): %v", language, err)
-		return "", fmt.Errorf("failed to extract text from image")
+	// Engines may not be interruptible (the CGO call is not), so run the engine in its own goroutine.
+	// If the request is cancelled or times out, we stop waiting; the goroutine releases its slot
+	// once the engine returns. The buffered channel lets it finish without a receiver.
+	done := make(chan ocrResult, 1)
+	go func() {
+		defer func() { <-s.slots }()
+		result, err := engine.Recognize(ctx, imageData, language, opts)
+		done <- ocrResult{result: result, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			if ctx.Err() != nil { // An interruptible engine was stopped by the deadline or cancellation.
+				return nil, s.contextError(ctx, language)
+			}
+			return nil, res.err
+		}
+		res.result.Text = PostProcessText(res.result.Text, opts)
+		return res.result, nil
+	case <-ctx.Done():
+		return nil, s.contextError(ctx, language)
 	}
+}
 
-	return text, nil
+// contextError converts a finished context into the Problem JSON error returned to the client.
+func (s *ocrService) contextError(ctx context.Context, language string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		log.Printf("WARNING: OCR scan (language: %s) exceeded the maximum duration of %s", language, s.timeout)
+		return huma.Error504GatewayTimeout(fmt.Sprintf("OCR scan did not finish within %s", s.timeout), nil)
+	}
+	log.Printf("INFO: OCR scan (language: %s) cancelled by client: %v", language, ctx.Err())
+	return huma.NewError(StatusClientClosedRequest, "OCR scan cancelled because the client closed the request", nil)
 }
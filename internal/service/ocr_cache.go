@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/axyut/niyamAPI/internal/repository" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+)
+
+// cachedOCRService wraps an OCRService with a result cache, so re-uploading the same page with
+// the same settings does not run Tesseract again. Cache tiers are consulted fastest first
+// (e.g., in-process LRU, then MongoDB); a hit in a slower tier is copied into the faster ones.
+type cachedOCRService struct {
+	inner   OCRService
+	tiers   []repository.OCRCache
+	ttl     time.Duration
+	engines OCREngineRegistry
+}
+
+// NewCachedOCRService returns an OCRService that serves results from `tiers` when possible and
+// otherwise calls `inner`, caching successful results for `ttl`. The version of the engine selected
+// from `engines` is part of every cache key, so upgrading Tesseract or the post-processing pipeline
+// invalidates old entries.
+func NewCachedOCRService(inner OCRService, ttl time.Duration, engines OCREngineRegistry, tiers ...repository.OCRCache) OCRService {
+	return &cachedOCRService{inner: inner, tiers: tiers, ttl: ttl, engines: engines}
+}
+
+// OCRCacheKey derives the cache key of a scan from the image's SHA-256, the language set,
+// the OCR options and the engine version.
+func OCRCacheKey(imageData []byte, language string, opts types.OCROptions, engineVersion string) string {
+	imageSum := sha256.Sum256(imageData)
+	// Struct fields marshal in a fixed order, so equal options always give equal JSON.
+	optsJSON, _ := json.Marshal(opts)
+
+	h := sha256.New()
+	h.Write(imageSum[:])
+	for _, part := range []string{language, string(optsJSON), engineVersion} {
+		h.Write([]byte{0}) // Separator, so adjacent parts cannot run together
+		h.Write([]byte(part))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ExtractTextFromImage returns a cached result when one exists, and otherwise performs OCR and caches the result.
+// Cache failures are logged and never fail the scan.
+func (s *cachedOCRService) ExtractTextFromImage(ctx context.Context, imageData []byte, language string, opts types.OCROptions) (*types.OCRResult, error) {
+	engine, err := s.engines.Engine(opts.Engine)
+	if len(imageData) == 0 || err != nil {
+		return s.inner.ExtractTextFromImage(ctx, imageData, language, opts) // Reports the error.
+	}
+	key := OCRCacheKey(imageData, language, opts, ocrResultVersion(engine))
+
+	for i, tier := range s.tiers {
+		result, found, err := tier.Get(ctx, key)
+		if err != nil {
+			log.Printf("WARNING: OCR cache lookup failed: %v", err)
+			continue
+		}
+		if !found {
+			continue
+		}
+		for _, faster := range s.tiers[:i] {
+			if err := faster.Set(ctx, key, result, s.ttl); err != nil {
+				log.Printf("WARNING: Failed to populate OCR cache: %v", err)
+			}
+		}
+		log.Printf("INFO: OCR result served from cache (language: %s)", language)
+		result.Cached = true
+		return result, nil
+	}
+
+	result, err := s.inner.ExtractTextFromImage(ctx, imageData, language, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, tier := range s.tiers {
+		if err := tier.Set(ctx, key, result, s.ttl); err != nil {
+			log.Printf("WARNING: Failed to store OCR result in cache: %v", err)
+		}
+	}
+	return result, nil
+}
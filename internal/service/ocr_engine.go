@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/config" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"  // Adjust import path to your module
+)
+
+// OCR engine names, as used by the OCR_ENGINE(S) settings and the 'engine' field of /scan.
+const (
+	OCREngineTesseract    = "tesseract"     // Tesseract linked through CGO (gosseract)
+	OCREngineTesseractCLI = "tesseract-cli" // The tesseract command-line program
+	OCREngineFake         = "fake"          // Deterministic stand-in for tests; never enabled by default
+)
+
+// OCREngine recognizes the text of a single image. Implementations are blocking; they should stop
+// early when ctx is cancelled if they can, but ocrService bounds them with its timeout either way.
+// Progress is reported through ocrProgress(ctx).
+type OCREngine interface {
+	// Name returns the registry name of the engine.
+	Name() string
+	// Version identifies the engine build; it is part of OCR cache keys.
+	Version() string
+	// Recognize returns the raw (not post-processed) text of the image with its lines, words and confidences.
+	Recognize(ctx context.Context, imageData []byte, language string, opts types.OCROptions) (*types.OCRResult, error)
+}
+
+// ocrEngineSettings holds what engine constructors may need from the configuration.
+type ocrEngineSettings struct {
+	tessdataDir  string // Directory traineddata is loaded from
+	tesseractCmd string // Path or name of the tesseract program
+}
+
+// ocrEngineFactory creates an engine. An error means the engine cannot run on this machine.
+type ocrEngineFactory func(settings ocrEngineSettings) (OCREngine, error)
+
+// ocrEngineFactories holds the engines compiled into this binary. Engine files register themselves
+// from init(), so build tags decide which engines exist (the CGO engine needs `cgo` and not `notesseract`).
+var ocrEngineFactories = map[string]ocrEngineFactory{}
+
+// registerOCREngine makes an engine available to NewOCREngineRegistry.
+func registerOCREngine(name string, factory ocrEngineFactory) {
+	ocrEngineFactories[name] = factory
+}
+
+// OCREngineRegistry holds the OCR engines enabled on this server.
+type OCREngineRegistry interface {
+	// ListEngines describes the enabled engines, sorted by name.
+	ListEngines() []types.OCREngineInfo
+	// Engine returns the named engine, or the default one for an empty name.
+	// Unknown or disabled engines are reported as a 400 error.
+	Engine(name string) (OCREngine, error)
+	// DefaultEngine returns the name of the default engine ("" when none is available).
+	DefaultEngine() string
+}
+
+// ocrEngineRegistry is the concrete, read-only implementation of OCREngineRegistry.
+type ocrEngineRegistry struct {
+	engines       map[string]OCREngine
+	defaultEngine string
+}
+
+// NewOCREngineRegistry creates the engines listed in `cfg.OCREngines`. Engines that are not compiled
+// into the binary or cannot start (e.g., no tesseract program installed) are skipped with a warning.
+// `cfg.OCREngine` selects the default; when it is unset or unavailable, the first available engine
+// in `cfg.OCREngines` is used.
+func NewOCREngineRegistry(cfg *config.AppConfig, tessdataDir string) OCREngineRegistry {
+	settings := ocrEngineSettings{tessdataDir: tessdataDir, tesseractCmd: cfg.TesseractCmd}
+	r := &ocrEngineRegistry{engines: make(map[string]OCREngine)}
+
+	for _, name := range cfg.OCREngines {
+		factory, ok := ocrEngineFactories[name]
+		if !ok {
+			log.Printf("WARNING: OCR engine '%s' is not compiled into this binary (available: %s)", name, strings.Join(compiledOCREngines(), ", "))
+			continue
+		}
+		engine, err := factory(settings)
+		if err != nil {
+			log.Printf("WARNING: OCR engine '%s' is not available: %v", name, err)
+			continue
+		}
+		r.engines[name] = engine
+		if r.defaultEngine == "" {
+			r.defaultEngine = name
+		}
+	}
+
+	if cfg.OCREngine != "" {
+		if _, ok := r.engines[cfg.OCREngine]; ok {
+			r.defaultEngine = cfg.OCREngine
+		} else {
+			log.Printf("WARNING: Default OCR engine '%s' is not available; using '%s' instead", cfg.OCREngine, r.defaultEngine)
+		}
+	}
+
+	if r.defaultEngine == "" {
+		log.Println("WARNING: No OCR engine is available; every scan will fail.")
+	} else {
+		log.Printf("INFO: OCR engines enabled: %s (default: %s)", strings.Join(r.names(), ", "), r.defaultEngine)
+	}
+	return r
+}
+
+// ListEngines describes the enabled engines, sorted by name.
+func (r *ocrEngineRegistry) ListEngines() []types.OCREngineInfo {
+	infos := make([]types.OCREngineInfo, 0, len(r.engines))
+	for _, name := range r.names() {
+		infos = append(infos, types.OCREngineInfo{Name: name, Version: r.engines[name].Version(), Default: name == r.defaultEngine})
+	}
+	return infos
+}
+
+// Engine returns the named engine, or the default one for an empty name.
+func (r *ocrEngineRegistry) Engine(name string) (OCREngine, error) {
+	if name == "" {
+		name = r.defaultEngine
+	}
+	if engine, ok := r.engines[name]; ok {
+		return engine, nil
+	}
+	if name == "" {
+		return nil, huma.Error503ServiceUnavailable("No OCR engine is available on this server.", nil)
+	}
+	return nil, huma.Error400BadRequest(fmt.Sprintf("Unknown OCR engine '%s'. Available engines are: %s.", name, strings.Join(r.names(), ", ")), nil)
+}
+
+// DefaultEngine returns the name of the default engine.
+func (r *ocrEngineRegistry) DefaultEngine() string {
+	return r.defaultEngine
+}
+
+// names returns the sorted names of the enabled engines.
+func (r *ocrEngineRegistry) names() []string {
+	names := make([]string, 0, len(r.engines))
+	for name := range r.engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// compiledOCREngines returns the sorted names of the engines compiled into this binary.
+func compiledOCREngines() []string {
+	names := make([]string, 0, len(ocrEngineFactories))
+	for name := range ocrEngineFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ocrWordBox is a word as reported by an engine, before it is grouped into lines.
+type ocrWordBox struct {
+	text                   string
+	confidence             float64
+	box                    image.Rectangle
+	block, paragraph, line int // Engine numbering; words with equal numbers are on the same line
+}
+
+// setOCRLayout groups engine words (in reading order) into the lines and words of `result`
+// and computes the line and overall confidences.
+func setOCRLayout(result *types.OCRResult, words []ocrWordBox) {
+	type lineKey struct{ block, paragraph, line int }
+	var prev lineKey
+	var lineBoxes []image.Rectangle
+	paragraph := 0
+	for _, w := range words {
+		text := strings.TrimSpace(w.text)
+		if text == "" {
+			continue
+		}
+		key := lineKey{w.block, w.paragraph, w.line}
+		if len(result.Lines) == 0 || key != prev {
+			if len(result.Lines) > 0 && (key.block != prev.block || key.paragraph != prev.paragraph) {
+				paragraph++
+			}
+			result.Lines = append(result.Lines, types.OCRLine{Paragraph: paragraph})
+			lineBoxes = append(lineBoxes, w.box)
+			prev = key
+		}
+		index := len(result.Lines) - 1
+		line := &result.Lines[index]
+		if line.Text != "" {
+			line.Text += " "
+		}
+		line.Text += text
+		lineBoxes[index] = lineBoxes[index].Union(w.box)
+		result.Words = append(result.Words, types.OCRWord{Text: text, Confidence: w.confidence, Box: ocrBox(w.box), Line: index})
+	}
+
+	for i := range result.Lines {
+		result.Lines[i].Box = ocrBox(lineBoxes[i])
+		result.Lines[i].Confidence = ocrConfidence(result.Words, func(w types.OCRWord) bool { return w.Line == i })
+	}
+	result.Confidence = ocrConfidence(result.Words, func(types.OCRWord) bool { return true })
+}
+
+// ocrLinesText joins the text of lines in order, with a newline between the lines of a paragraph
+// and a blank line between paragraphs. Lines for which `keep` returns false are left out; a nil
+// `keep` keeps all lines.
+func ocrLinesText(lines []types.OCRLine, keep func(int) bool) string {
+	var text strings.Builder
+	prev := -1
+	for i, line := range lines {
+		if keep != nil && !keep(i) {
+			continue
+		}
+		if prev >= 0 {
+			if line.Paragraph != lines[prev].Paragraph {
+				text.WriteString("\n\n")
+			} else {
+				text.WriteString("\n")
+			}
+		}
+		text.WriteString(line.Text)
+		prev = i
+	}
+	return text.String()
+}
+
+// ocrBox converts an image rectangle to an OCRBox.
+func ocrBox(r image.Rectangle) types.OCRBox {
+	return types.OCRBox{X: r.Min.X, Y: r.Min.Y, Width: r.Dx(), Height: r.Dy()}
+}
+
+// ocrConfidence returns the mean confidence of the selected words, weighted by their length so a
+// misread long word counts more than a stray character. The result is rounded to one decimal.
+func ocrConfidence(words []types.OCRWord, selected func(types.OCRWord) bool) float64 {
+	var sum, weight float64
+	for _, w := range words {
+		if selected(w) {
+			n := float64(utf8.RuneCountInString(w.Text))
+			sum += w.Confidence * n
+			weight += n
+		}
+	}
+	if weight == 0 {
+		return 0
+	}
+	return math.Round(sum/weight*10) / 10
+}
+
+// writeOCRUserFiles writes the user words and patterns of `opts` (if any) to files in `dir` and returns
+// their paths; a path is empty when the corresponding list is.
+func writeOCRUserFiles(dir string, opts types.OCROptions) (wordsPath, patternsPath string, err error) {
+	if len(opts.UserWords) > 0 {
+		wordsPath = filepath.Join(dir, "user.words")
+		if err := os.WriteFile(wordsPath, []byte(strings.Join(opts.UserWords, "\n")+"\n"), 0o600); err != nil {
+			return "", "", fmt.Errorf("failed to write user words file: %w", err)
+		}
+	}
+	if len(opts.UserPatterns) > 0 {
+		patternsPath = filepath.Join(dir, "user.patterns")
+		if err := os.WriteFile(patternsPath, []byte(strings.Join(opts.UserPatterns, "\n")+"\n"), 0o600); err != nil {
+			return "", "", fmt.Errorf("failed to write user patterns file: %w", err)
+		}
+	}
+	return wordsPath, patternsPath, nil
+}
+
+// removeOCRTempDir removes a temporary directory created for an OCR run.
+func removeOCRTempDir(dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		log.Printf("WARNING: Failed to remove temporary OCR config directory '%s': %v", dir, err)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// The fake engine is always compiled in, but only enabled when listed in OCR_ENGINES.
+func init() {
+	registerOCREngine(OCREngineFake, newFakeOCREngine)
+}
+
+// fakeOCREngine returns text derived only from its inputs, so tests can run without Tesseract
+// and assert on exact output.
+type fakeOCREngine struct{}
+
+// newFakeOCREngine creates the fake engine.
+func newFakeOCREngine(ocrEngineSettings) (OCREngine, error) {
+	return fakeOCREngine{}, nil
+}
+
+// Name returns the registry name of the engine.
+func (fakeOCREngine) Name() string { return OCREngineFake }
+
+// Version returns a fixed version.
+func (fakeOCREngine) Version() string { return "fake 1" }
+
+// Recognize returns "fake <language> <first 12 hex digits of the image SHA-256>", followed by the page
+// segmentation mode when one is set, as a single line. Words are laid out on a fixed grid, with
+// confidences derived from the text, so different inputs give different but repeatable confidences.
+func (fakeOCREngine) Recognize(ctx context.Context, imageData []byte, language string, opts types.OCROptions) (*types.OCRResult, error) {
+	progress := ocrProgress(ctx)
+	progress(types.ScanStagePreprocessing)
+	progress(types.ScanStageRecognizing)
+
+	sum := sha256.Sum256(imageData)
+	text := fmt.Sprintf("fake %s %s", language, hex.EncodeToString(sum[:])[:12])
+	if opts.PageSegMode != nil {
+		text += fmt.Sprintf(" psm=%d", *opts.PageSegMode)
+	}
+
+	textSum := sha256.Sum256([]byte(text))
+	var words []ocrWordBox
+	for i, word := range strings.Fields(text) {
+		words = append(words, ocrWordBox{
+			text:       word,
+			confidence: float64(50 + int(textSum[i%len(textSum)])%50),
+			box:        image.Rect(i*100, 0, i*100+90, 30),
+		})
+	}
+	result := &types.OCRResult{Text: text + "\n"}
+	setOCRLayout(result, words)
+	return result, nil
+}
@@ -0,0 +1,167 @@
+//go:build cgo && !notesseract
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/otiai10/gosseract/v2"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// The CGO engine needs the Tesseract and Leptonica development libraries at build time.
+// Build with `-tags notesseract` (or CGO_ENABLED=0) to leave it out.
+func init() {
+	registerOCREngine(OCREngineTesseract, newTesseractEngine)
+}
+
+// tesseractEngine runs Tesseract in-process through gosseract.
+type tesseractEngine struct {
+	tessdataDir string
+}
+
+// newTesseractEngine creates the CGO Tesseract engine.
+func newTesseractEngine(settings ocrEngineSettings) (OCREngine, error) {
+	return &tesseractEngine{tessdataDir: settings.tessdataDir}, nil
+}
+
+// Name returns the registry name of the engine.
+func (e *tesseractEngine) Name() string { return OCREngineTesseract }
+
+// Version returns the version of the linked Tesseract library.
+func (e *tesseractEngine) Version() string { return "tesseract " + gosseract.Version() }
+
+// Recognize performs the blocking Tesseract recognition for a single image.
+// The CGO call cannot be interrupted, so ctx is only used for progress reporting.
+func (e *tesseractEngine) Recognize(ctx context.Context, imageData []byte, language string, opts types.OCROptions) (*types.OCRResult, error) {
+	progress := ocrProgress(ctx)
+	progress(types.ScanStagePreprocessing)
+
+	client := gosseract.NewClient()
+	defer client.Close() // Crucial: Ensure the Tesseract client is closed after use to release resources.
+
+	// Load models from the same directory the language registry was discovered in.
+	if e.tessdataDir != "" {
+		if err := client.SetTessdataPrefix(e.tessdataDir); err != nil {
+			log.Printf("ERROR: Failed to set tessdata directory '%s': %v", e.tessdataDir, err)
+			return nil, fmt.Errorf("failed to configure OCR language data directory")
+		}
+	}
+
+	// Set the OCR language(s). Tesseract accepts comma or plus-separated codes (e.g., "eng", "nep", "hin", "eng+nep").
+	// This must be done before setting the image.
+	if err := client.SetLanguage(language); err != nil {
+		log.Printf("ERROR: Failed to set OCR language '%s': %v", language, err)
+		return nil, fmt.Errorf("unsupported OCR language or missing language data for '%s'", language)
+	}
+
+	// Apply per-scan tuning options on top of gosseract defaults.
+	cleanup, err := applyOCROptions(client, opts)
+	defer cleanup()
+	if err != nil {
+		log.Printf("ERROR: Failed to apply OCR options: %v", err)
+		return nil, fmt.Errorf("failed to apply OCR options")
+	}
+
+	// Set the image for OCR directly from the byte slice.
+	if err := client.SetImageFromBytes(imageData); err != nil {
+		log.Printf("ERROR: Failed to set image for OCR from bytes: %v", err)
+		return nil, fmt.Errorf("failed to prepare image for OCR processing")
+	}
+
+	// Perform the Optical Character Recognition. The word boxes are read first: that call runs the
+	// recognition, and Text() then reuses its result instead of recognizing the image again.
+	progress(types.ScanStageRecognizing)
+	boxes, err := client.GetBoundingBoxesVerbose()
+	if err != nil {
+		log.Printf("ERROR: Failed to recognize image using Tesseract (language: %s): %v", language, err)
+		return nil, fmt.Errorf("failed to extract text from image")
+	}
+	text, err := client.Text()
+	if err != nil {
+		log.Printf("ERROR: Failed to extract text from image using Tesseract (language: %s): %v", language, err)
+		return nil, fmt.Errorf("failed to extract text from image")
+	}
+
+	result := &types.OCRResult{Text: text}
+	words := make([]ocrWordBox, 0, len(boxes))
+	for _, b := range boxes {
+		words = append(words, ocrWordBox{text: b.Word, confidence: b.Confidence, box: b.Box, block: b.BlockNum, paragraph: b.ParNum, line: b.LineNum})
+	}
+	setOCRLayout(result, words)
+	return result, nil
+}
+
+// applyOCROptions configures the Tesseract client with the given options.
+// Init-only parameters (engine mode, user words/patterns) can only be passed through a
+// config file, so they are written to a temporary directory; the returned cleanup
+// function removes it and must always be called, even when an error is returned.
+func applyOCROptions(client *gosseract.Client, opts types.OCROptions) (func(), error) {
+	cleanup := func() {}
+
+	if opts.PageSegMode != nil {
+		if err := client.SetPageSegMode(gosseract.PageSegMode(*opts.PageSegMode)); err != nil {
+			return cleanup, fmt.Errorf("failed to set page segmentation mode: %w", err)
+		}
+	}
+	if opts.CharWhitelist != "" {
+		if err := client.SetWhitelist(opts.CharWhitelist); err != nil {
+			return cleanup, fmt.Errorf("failed to set character whitelist: %w", err)
+		}
+	}
+	if opts.CharBlacklist != "" {
+		if err := client.SetBlacklist(opts.CharBlacklist); err != nil {
+			return cleanup, fmt.Errorf("failed to set character blacklist: %w", err)
+		}
+	}
+	if opts.PreserveInterwordSpaces != nil {
+		value := "0"
+		if *opts.PreserveInterwordSpaces {
+			value = "1"
+		}
+		if err := client.SetVariable("preserve_interword_spaces", value); err != nil {
+			return cleanup, fmt.Errorf("failed to set preserve_interword_spaces: %w", err)
+		}
+	}
+
+	if opts.EngineMode == nil && len(opts.UserWords) == 0 && len(opts.UserPatterns) == 0 {
+		return cleanup, nil
+	}
+
+	dir, err := os.MkdirTemp("", "niyam-ocr-*")
+	if err != nil {
+		return cleanup, fmt.Errorf("failed to create temporary config directory: %w", err)
+	}
+	cleanup = func() { removeOCRTempDir(dir) }
+
+	var configLines []string
+	if opts.EngineMode != nil {
+		configLines = append(configLines, "tessedit_ocr_engine_mode "+strconv.Itoa(*opts.EngineMode))
+	}
+	wordsPath, patternsPath, err := writeOCRUserFiles(dir, opts)
+	if err != nil {
+		return cleanup, err
+	}
+	if wordsPath != "" {
+		configLines = append(configLines, "user_words_file "+wordsPath)
+	}
+	if patternsPath != "" {
+		configLines = append(configLines, "user_patterns_file "+patternsPath)
+	}
+
+	configPath := filepath.Join(dir, "niyam.config")
+	if err := os.WriteFile(configPath, []byte(strings.Join(configLines, "\n")+"\n"), 0o600); err != nil {
+		return cleanup, fmt.Errorf("failed to write Tesseract config file: %w", err)
+	}
+	if err := client.SetConfigFile(configPath); err != nil {
+		return cleanup, fmt.Errorf("failed to set Tesseract config file: %w", err)
+	}
+	return cleanup, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// The CLI engine needs only the tesseract program at run time, so it works in CGO-free builds.
+func init() {
+	registerOCREngine(OCREngineTesseractCLI, newTesseractCLIEngine)
+}
+
+// maxTesseractStderr bounds how much of the program's error output ends up in logs.
+const maxTesseractStderr = 2048
+
+// tesseractCLIEngine runs the tesseract program once per image, feeding the image on stdin.
+type tesseractCLIEngine struct {
+	cmd         string // Resolved path of the tesseract program
+	version     string
+	tessdataDir string
+}
+
+// newTesseractCLIEngine locates the tesseract program and reads its version.
+func newTesseractCLIEngine(settings ocrEngineSettings) (OCREngine, error) {
+	name := settings.tesseractCmd
+	if name == "" {
+		name = "tesseract"
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return nil, fmt.Errorf("tesseract program not found: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run '%s --version': %w", path, err)
+	}
+	// The first line reads like "tesseract 5.3.0"; the rest lists the linked libraries.
+	version, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+
+	return &tesseractCLIEngine{cmd: path, version: strings.TrimSpace(version), tessdataDir: settings.tessdataDir}, nil
+}
+
+// Name returns the registry name of the engine.
+func (e *tesseractCLIEngine) Name() string { return OCREngineTesseractCLI }
+
+// Version returns the version line printed by the tesseract program.
+func (e *tesseractCLIEngine) Version() string { return e.version + " (cli)" }
+
+// Recognize runs tesseract on the image. Cancelling ctx kills the process.
+func (e *tesseractCLIEngine) Recognize(ctx context.Context, imageData []byte, language string, opts types.OCROptions) (*types.OCRResult, error) {
+	progress := ocrProgress(ctx)
+	progress(types.ScanStagePreprocessing)
+
+	// Output goes to files: the "txt" and "tsv" configs write <base>.txt and <base>.tsv (word boxes).
+	dir, err := os.MkdirTemp("", "niyam-ocr-*")
+	if err != nil {
+		log.Printf("ERROR: Failed to create temporary OCR directory: %v", err)
+		return nil, fmt.Errorf("failed to prepare image for OCR processing")
+	}
+	defer removeOCRTempDir(dir)
+	base := filepath.Join(dir, "out")
+
+	args, err := e.args(dir, base, language, opts)
+	if err != nil {
+		log.Printf("ERROR: Failed to apply OCR options: %v", err)
+		return nil, fmt.Errorf("failed to apply OCR options")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.cmd, args...)
+	cmd.Stdin = bytes.NewReader(imageData)
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second // Do not hang on output pipes after the process was killed.
+
+	// The program loads the image and recognizes it in one go.
+	progress(types.ScanStageRecognizing)
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr // ocrService reports the timeout or cancellation.
+		}
+		message := strings.TrimSpace(stderr.String())
+		if len(message) > maxTesseractStderr {
+			message = message[:maxTesseractStderr]
+		}
+		log.Printf("ERROR: Tesseract program failed (language: %s): %v: %s", language, err, message)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(message, "Failed loading language") {
+			return nil, fmt.Errorf("unsupported OCR language or missing language data for '%s'", language)
+		}
+		return nil, fmt.Errorf("failed to extract text from image")
+	}
+
+	text, err := os.ReadFile(base + ".txt")
+	if err != nil {
+		log.Printf("ERROR: Tesseract program wrote no text output: %v", err)
+		return nil, fmt.Errorf("failed to extract text from image")
+	}
+	result := &types.OCRResult{Text: string(text)}
+	if tsv, err := os.ReadFile(base + ".tsv"); err == nil {
+		setOCRLayout(result, parseTesseractTSV(string(tsv)))
+	} else {
+		log.Printf("WARNING: Tesseract program wrote no word boxes: %v", err)
+	}
+	return result, nil
+}
+
+// args builds the command line for one run writing to `base`. User words and patterns are written to `dir`.
+func (e *tesseractCLIEngine) args(dir, base, language string, opts types.OCROptions) ([]string, error) {
+	args := []string{"stdin", base, "-l", language}
+	if e.tessdataDir != "" {
+		args = append(args, "--tessdata-dir", e.tessdataDir)
+	}
+	if opts.PageSegMode != nil {
+		args = append(args, "--psm", strconv.Itoa(*opts.PageSegMode))
+	}
+	if opts.EngineMode != nil {
+		args = append(args, "--oem", strconv.Itoa(*opts.EngineMode))
+	}
+	if opts.CharWhitelist != "" {
+		args = append(args, "-c", "tessedit_char_whitelist="+opts.CharWhitelist)
+	}
+	if opts.CharBlacklist != "" {
+		args = append(args, "-c", "tessedit_char_blacklist="+opts.CharBlacklist)
+	}
+	if opts.PreserveInterwordSpaces != nil {
+		value := "0"
+		if *opts.PreserveInterwordSpaces {
+			value = "1"
+		}
+		args = append(args, "-c", "preserve_interword_spaces="+value)
+	}
+
+	wordsPath, patternsPath, err := writeOCRUserFiles(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	if wordsPath != "" {
+		args = append(args, "--user-words", wordsPath)
+	}
+	if patternsPath != "" {
+		args = append(args, "--user-patterns", patternsPath)
+	}
+	// Config names come last on the tesseract command line.
+	return append(args, "txt", "tsv"), nil
+}
+
+// parseTesseractTSV reads the word rows (level 5) of tesseract's TSV output. Its columns are:
+// level, page_num, block_num, par_num, line_num, word_num, left, top, width, height, conf, text.
+func parseTesseractTSV(tsv string) []ocrWordBox {
+	var words []ocrWordBox
+	for _, row := range strings.Split(tsv, "\n") {
+		cols := strings.Split(strings.TrimRight(row, "\r"), "\t")
+		if len(cols) < 12 || cols[0] != "5" {
+			continue
+		}
+		// page_num through height are integers.
+		var nums [9]int
+		valid := true
+		for i := range nums {
+			n, err := strconv.Atoi(cols[i+1])
+			valid = valid && err == nil
+			nums[i] = n
+		}
+		conf, err := strconv.ParseFloat(cols[10], 64)
+		if !valid || err != nil {
+			continue
+		}
+		left, top, width, height := nums[5], nums[6], nums[7], nums[8]
+		words = append(words, ocrWordBox{
+			text:       cols[11],
+			confidence: max(conf, 0),
+			box:        image.Rect(left, top, left+width, top+height),
+			block:      nums[1],
+			paragraph:  nums[2],
+			line:       nums[3],
+		})
+	}
+	return words
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axyut/niyamAPI/internal/config" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"  // Adjust import path to your module
+)
+
+// fakeTesseractCmd writes a stand-in tesseract program that only answers --version, so the CLI
+// engine can start without Tesseract installed.
+func fakeTesseractCmd(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the stand-in tesseract program is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "tesseract")
+	script := "#!/bin/sh\necho 'tesseract 5.9.9'\necho ' leptonica-1.84.0'\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestOCREngineRegistry(t *testing.T) {
+	missingCmd := filepath.Join(t.TempDir(), "no-tesseract")
+	tests := []struct {
+		name          string
+		engines       []string
+		defaultEngine string
+		cmd           string
+		wantEngines   []string
+		wantDefault   string
+	}{
+		{"first available is the default", []string{"unknown", OCREngineFake}, "", missingCmd, []string{OCREngineFake}, OCREngineFake},
+		{"configured default", []string{OCREngineTesseractCLI, OCREngineFake}, OCREngineFake, "", []string{OCREngineFake, OCREngineTesseractCLI}, OCREngineFake},
+		{"unavailable default falls back", []string{OCREngineTesseractCLI, OCREngineFake}, OCREngineTesseractCLI, missingCmd, []string{OCREngineFake}, OCREngineFake},
+		{"unavailable engines are skipped", []string{OCREngineTesseractCLI, OCREngineFake}, "", missingCmd, []string{OCREngineFake}, OCREngineFake},
+		{"nothing available", []string{"unknown", OCREngineTesseractCLI}, "", missingCmd, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := tt.cmd
+			if cmd == "" {
+				cmd = fakeTesseractCmd(t)
+			}
+			registry := NewOCREngineRegistry(&config.AppConfig{OCREngines: tt.engines, OCREngine: tt.defaultEngine, TesseractCmd: cmd}, "")
+
+			if got := registry.DefaultEngine(); got != tt.wantDefault {
+				t.Errorf("DefaultEngine() = %q, want %q", got, tt.wantDefault)
+			}
+			infos := registry.ListEngines()
+			if len(infos) != len(tt.wantEngines) {
+				t.Fatalf("ListEngines() = %+v, want %v", infos, tt.wantEngines)
+			}
+			for i, info := range infos {
+				if info.Name != tt.wantEngines[i] || info.Default != (info.Name == tt.wantDefault) || info.Version == "" {
+					t.Errorf("ListEngines()[%d] = %+v, want engine %q (default %q)", i, info, tt.wantEngines[i], tt.wantDefault)
+				}
+			}
+
+			engine, err := registry.Engine("")
+			if tt.wantDefault == "" {
+				if !isStatus(err, http.StatusServiceUnavailable) {
+					t.Errorf("Engine(\"\") without engines: got %v, want 503", err)
+				}
+			} else if err != nil || engine.Name() != tt.wantDefault {
+				t.Errorf("Engine(\"\") = %v, %v; want %q", engine, err, tt.wantDefault)
+			}
+			if _, err := registry.Engine("unknown"); !isStatus(err, http.StatusBadRequest) {
+				t.Errorf("Engine(\"unknown\"): got %v, want 400", err)
+			}
+		})
+	}
+}
+
+func TestTesseractCLIVersion(t *testing.T) {
+	registry := NewOCREngineRegistry(&config.AppConfig{OCREngines: []string{OCREngineTesseractCLI}, TesseractCmd: fakeTesseractCmd(t)}, "")
+	engine, err := registry.Engine(OCREngineTesseractCLI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := engine.Version(), "tesseract 5.9.9 (cli)"; got != want {
+		t.Errorf("Version() = %q, want %q", got, want)
+	}
+}
+
+func TestFakeOCREngine(t *testing.T) {
+	engine, err := newFakeOCREngine(ocrEngineSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	psm := 6
+	first, err := engine.Recognize(context.Background(), []byte("image"), "nep", types.OCROptions{PageSegMode: &psm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "fake nep 6105d6cc76af psm=6\n"; first.Text != want {
+		t.Errorf("Text = %q, want %q", first.Text, want)
+	}
+	if len(first.Lines) != 1 || len(first.Words) != 4 || first.Confidence < 50 || first.Confidence >= 100 {
+		t.Errorf("got %d line(s), %d word(s), confidence %v; want 1, 4 and 50-99", len(first.Lines), len(first.Words), first.Confidence)
+	}
+
+	second, _ := engine.Recognize(context.Background(), []byte("image"), "nep", types.OCROptions{PageSegMode: &psm})
+	if second.Text != first.Text || second.Confidence != first.Confidence {
+		t.Errorf("second run gave %q (%v), want %q (%v)", second.Text, second.Confidence, first.Text, first.Confidence)
+	}
+	if other, _ := engine.Recognize(context.Background(), []byte("other image"), "nep", types.OCROptions{}); other.Text == first.Text {
+		t.Error("different images gave the same text")
+	}
+}
+
+func TestOCRServiceWithFakeEngine(t *testing.T) {
+	cfg := &config.AppConfig{OCREngines: []string{OCREngineFake}, OCRTimeout: time.Minute, OCRMaxConcurrency: 1}
+	ocr := NewOCRService(cfg, NewOCREngineRegistry(cfg, ""))
+	result, err := ocr.ExtractTextFromImage(context.Background(), []byte("image"), "eng", types.OCROptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result.Text, "fake eng ") {
+		t.Errorf("Text = %q, want the fake engine's output", result.Text)
+	}
+	if _, err := ocr.ExtractTextFromImage(context.Background(), []byte("image"), "eng", types.OCROptions{Engine: OCREngineTesseract}); !isStatus(err, http.StatusBadRequest) {
+		t.Errorf("disabled engine: got %v, want 400", err)
+	}
+}
+
+func TestParseTesseractTSV(t *testing.T) {
+	tsv := strings.Join([]string{
+		"level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext",
+		"1\t1\t0\t0\t0\t0\t0\t0\t800\t600\t-1\t",
+		"2\t1\t1\t0\t0\t0\t10\t10\t300\t60\t-1\t",
+		"4\t1\t1\t1\t1\t0\t10\t10\t300\t25\t-1\t",
+		"5\t1\t1\t1\t1\t1\t10\t10\t80\t25\t96.5\tनेपालको",
+		"5\t1\t1\t1\t1\t2\t100\t12\t90\t23\t91.25\tसंविधान",
+		"5\t1\t1\t1\t2\t1\t10\t45\t40\t25\t88\tदफा",
+		"5\t1\t1\t1\t2\t2\t60\t45\t20\t25\t-1\t ",
+		"5\t1\t2\t1\t1\t1\t10\t200\t50\t25\t42\tHandwr1tten\r",
+		"5\t1\tx\t1\t1\t1\t10\t200\t50\t25\t42\tbroken",
+		"5\t1\t2\t1\t1\t2\t70\t200",
+		"",
+	}, "\n")
+
+	words := parseTesseractTSV(tsv)
+	if len(words) != 5 {
+		t.Fatalf("parsed %d word rows, want 5: %+v", len(words), words)
+	}
+	if w := words[1]; w.text != "संविधान" || w.confidence != 91.25 || w.box.Min.X != 100 || w.box.Max.Y != 35 || w.line != 1 {
+		t.Errorf("second word = %+v", w)
+	}
+	if w := words[3]; w.confidence != 0 {
+		t.Errorf("confidence -1 parsed as %v, want 0", w.confidence)
+	}
+
+	result := &types.OCRResult{}
+	setOCRLayout(result, words)
+	wantLines := []types.OCRLine{
+		{Text: "नेपालको संविधान", Paragraph: 0},
+		{Text: "दफा", Paragraph: 0},
+		{Text: "Handwr1tten", Paragraph: 1},
+	}
+	if len(result.Lines) != len(wantLines) {
+		t.Fatalf("got lines %+v, want %+v", result.Lines, wantLines)
+	}
+	for i, want := range wantLines {
+		if got := result.Lines[i]; got.Text != want.Text || got.Paragraph != want.Paragraph {
+			t.Errorf("line %d = %q (paragraph %d), want %q (paragraph %d)", i, got.Text, got.Paragraph, want.Text, want.Paragraph)
+		}
+	}
+	if box := result.Lines[0].Box; box != (types.OCRBox{X: 10, Y: 10, Width: 180, Height: 25}) {
+		t.Errorf("first line box = %+v", box)
+	}
+	if got, want := ocrLinesText(result.Lines, nil), "नेपालको संविधान\nदफा\n\nHandwr1tten"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path to your module
+)
+
+// Ensemble tuning.
+const (
+	// ensembleUpscaleBelow is the shorter image side (in pixels) under which an enlarged variant is tried.
+	ensembleUpscaleBelow = 2000
+	// ensembleMaxUpscaledPixels bounds the size of the enlarged variant.
+	ensembleMaxUpscaledPixels = 40_000_000
+	// ensembleMinLineOverlap is the vertical overlap (intersection over union) at which lines of two
+	// variants are taken to be the same line.
+	ensembleMinLineOverlap = 0.5
+)
+
+// ensembleVariant is one configuration of an ensemble scan.
+type ensembleVariant struct {
+	name        string
+	description string
+	language    string
+	opts        types.OCROptions
+	image       []byte
+	scale       int // Factor the image was enlarged by; the variant's boxes are divided by it
+}
+
+// ensembleOCRService is an OCRService that runs several configurations of another OCRService over
+// the same image and merges their results line by line, keeping the most confident reading.
+type ensembleOCRService struct {
+	inner OCRService
+}
+
+// NewEnsembleOCRService returns the "accurate" OCRService. For every image it runs `inner` with the
+// requested options and with variants (other page segmentation modes, a binarized and an enlarged
+// image, each language of a combined language set), then merges the results: the variant with the
+// highest overall confidence provides the lines, and each line is replaced by the matching line of
+// another variant when that one was read with higher confidence. The result's Ensemble field
+// reports the variants and which one each line came from.
+func NewEnsembleOCRService(inner OCRService) OCRService {
+	return &ensembleOCRService{inner: inner}
+}
+
+// ExtractTextFromImage runs all variants concurrently (bounded by the inner service's OCR slots)
+// and merges their results. Failed variants are ignored unless all of them fail.
+func (s *ensembleOCRService) ExtractTextFromImage(ctx context.Context, imageData []byte, language string, opts types.OCROptions) (*types.OCRResult, error) {
+	if len(imageData) == 0 {
+		return nil, fmt.Errorf("empty image data provided")
+	}
+	variants := ensembleVariants(imageData, language, opts)
+
+	results := make([]*types.OCRResult, len(variants))
+	errs := make([]error, len(variants))
+	var wg sync.WaitGroup
+	for i, variant := range variants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = s.inner.ExtractTextFromImage(ctx, variant.image, variant.language, variant.opts)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			log.Printf("WARNING: Ensemble OCR variant '%s' failed: %v", variants[i].name, err)
+		}
+	}
+	if failed == len(variants) {
+		// Report the error of the requested configuration, as a plain scan would.
+		return nil, errs[0]
+	}
+
+	result := mergeEnsembleResults(variants, results, errs, opts)
+	log.Printf("INFO: Ensemble OCR ran %d variant(s) (%d failed); winner '%s', %d line(s) replaced",
+		len(variants), failed, result.Ensemble.Winner, result.Ensemble.Replaced)
+	return result, nil
+}
+
+// ensembleVariants lists the configurations of an ensemble scan; the requested one comes first.
+func ensembleVariants(imageData []byte, language string, opts types.OCROptions) []ensembleVariant {
+	variants := []ensembleVariant{{name: "default", description: "Requested options", language: language, opts: opts, image: imageData, scale: 1}}
+
+	// Page segmentation: a single uniform block of text, and a single column of text of variable sizes.
+	for _, psm := range []struct {
+		mode        int
+		description string
+	}{{6, "Single uniform block of text (psm 6)"}, {4, "Single column of variable-size text (psm 4)"}} {
+		if opts.PageSegMode != nil && *opts.PageSegMode == psm.mode {
+			continue
+		}
+		variantOpts := opts
+		variantOpts.PageSegMode = intPtr(psm.mode)
+		variants = append(variants, ensembleVariant{name: fmt.Sprintf("psm-%d", psm.mode), description: psm.description,
+			language: language, opts: variantOpts, image: imageData, scale: 1})
+	}
+
+	// Preprocessing needs a decodable image (PNG, JPEG or GIF).
+	if img, _, err := utils.DecodeImage(imageData); err == nil {
+		gray := utils.Grayscale(img)
+		if data, err := utils.EncodePNG(utils.Binarize(gray)); err == nil {
+			variants = append(variants, ensembleVariant{name: "binarized", description: "Otsu-binarized image",
+				language: language, opts: opts, image: data, scale: 1})
+		}
+		w, h := gray.Bounds().Dx(), gray.Bounds().Dy()
+		if min(w, h) < ensembleUpscaleBelow && int64(w)*int64(h)*4 <= ensembleMaxUpscaledPixels {
+			if data, err := utils.EncodePNG(utils.Upscale(gray, 2)); err == nil {
+				variants = append(variants, ensembleVariant{name: "upscaled", description: "Grayscale image enlarged 2x",
+					language: language, opts: opts, image: data, scale: 2})
+			}
+		}
+	} else {
+		log.Printf("INFO: Ensemble OCR skips preprocessing variants: %v", err)
+	}
+
+	// Each language of a combined set on its own, for pages in a single language.
+	if codes := strings.Split(language, "+"); len(codes) > 1 {
+		for _, code := range codes {
+			variants = append(variants, ensembleVariant{name: "lang-" + code, description: "Language '" + code + "' only",
+				language: code, opts: opts, image: imageData, scale: 1})
+		}
+	}
+	return variants
+}
+
+// mergeEnsembleResults combines the successful variant results into one result with an ensemble report.
+func mergeEnsembleResults(variants []ensembleVariant, results []*types.OCRResult, errs []error, opts types.OCROptions) *types.OCRResult {
+	report := &types.OCREnsembleReport{}
+	winner := -1
+	cached := true
+	for i, variant := range variants {
+		info := types.OCREnsembleVariant{Name: variant.name, Description: variant.description}
+		if errs[i] != nil {
+			info.Error = errs[i].Error()
+		} else {
+			info.Confidence, info.Lines = results[i].Confidence, len(results[i].Lines)
+			cached = cached && results[i].Cached
+			if winner < 0 || results[i].Confidence > results[winner].Confidence {
+				winner = i
+			}
+		}
+		report.Variants = append(report.Variants, info)
+	}
+	report.Winner = variants[winner].name
+
+	// Engines without a layout cannot be merged line by line; the winner is used as is.
+	if len(results[winner].Lines) == 0 {
+		merged := *results[winner]
+		merged.Ensemble, merged.Cached = report, cached
+		return &merged
+	}
+
+	// Bring every layout into the coordinates of the original image.
+	layouts := make([]*types.OCRResult, len(results))
+	for i, result := range results {
+		if errs[i] == nil {
+			layouts[i] = scaleOCRLayout(result, variants[i].scale)
+		}
+	}
+
+	merged := &types.OCRResult{Cached: cached, Ensemble: report}
+	for lineIndex, line := range layouts[winner].Lines {
+		best, bestLine := winner, lineIndex
+		for i, layout := range layouts {
+			if layout == nil || i == winner {
+				continue
+			}
+			if j := matchingOCRLine(layout.Lines, line.Box); j >= 0 && layout.Lines[j].Confidence > layouts[best].Lines[bestLine].Confidence {
+				best, bestLine = i, j
+			}
+		}
+		if best != winner {
+			report.Replaced++
+		}
+
+		chosen := layouts[best].Lines[bestLine]
+		chosen.Paragraph = line.Paragraph // The winner's paragraphs structure the merged text.
+
+		for _, word := range layouts[best].Words {
+			if word.Line == bestLine {
+				word.Line = len(merged.Lines)
+				merged.Words = append(merged.Words, word)
+			}
+		}
+		merged.Lines = append(merged.Lines, chosen)
+		report.Lines = append(report.Lines, types.OCREnsembleLine{Text: chosen.Text, Confidence: chosen.Confidence, Variant: variants[best].name})
+	}
+
+	merged.Text = PostProcessText(ocrLinesText(merged.Lines, nil), opts)
+	merged.Confidence = ocrConfidence(merged.Words, func(types.OCRWord) bool { return true })
+	return merged
+}
+
+// scaleOCRLayout returns a copy of the lines and words of `result` with boxes divided by `scale`.
+func scaleOCRLayout(result *types.OCRResult, scale int) *types.OCRResult {
+	scaled := &types.OCRResult{
+		Lines: append([]types.OCRLine(nil), result.Lines...),
+		Words: append([]types.OCRWord(nil), result.Words...),
+	}
+	if scale > 1 {
+		for i := range scaled.Lines {
+			scaled.Lines[i].Box = scaleOCRBox(scaled.Lines[i].Box, scale)
+		}
+		for i := range scaled.Words {
+			scaled.Words[i].Box = scaleOCRBox(scaled.Words[i].Box, scale)
+		}
+	}
+	return scaled
+}
+
+// scaleOCRBox divides a box by `scale`.
+func scaleOCRBox(box types.OCRBox, scale int) types.OCRBox {
+	return types.OCRBox{X: box.X / scale, Y: box.Y / scale, Width: box.Width / scale, Height: box.Height / scale}
+}
+
+// matchingOCRLine returns the index of the line that overlaps `box` the most vertically (and at
+// all horizontally), or -1 when no line overlaps enough to be the same line.
+func matchingOCRLine(lines []types.OCRLine, box types.OCRBox) int {
+	best, bestOverlap := -1, ensembleMinLineOverlap
+	for i, line := range lines {
+		if line.Box.X >= box.X+box.Width || box.X >= line.Box.X+line.Box.Width {
+			continue
+		}
+		top, bottom := max(line.Box.Y, box.Y), min(line.Box.Y+line.Box.Height, box.Y+box.Height)
+		union := max(line.Box.Y+line.Box.Height, box.Y+box.Height) - min(line.Box.Y, box.Y)
+		if bottom <= top || union <= 0 {
+			continue
+		}
+		if overlap := float64(bottom-top) / float64(union); overlap >= bestOverlap {
+			best, bestOverlap = i, overlap
+		}
+	}
+	return best
+}
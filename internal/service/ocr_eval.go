@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path to your module
+)
+
+// OCREvalGroundTruthSuffix ends the name of ground-truth files: "page.png" is compared with "page.gt.txt",
+// as in Tesseract's training data.
+const OCREvalGroundTruthSuffix = ".gt.txt"
+
+// OCREvalOverall is the language of the summary of all samples of a report.
+const OCREvalOverall = "all"
+
+// ocrEvalImageExtensions are the image files looked for next to ground-truth files, in order.
+var ocrEvalImageExtensions = []string{".png", ".jpg", ".jpeg", ".tif", ".tiff", ".webp", ".bmp"}
+
+// ocrEvalWorstSamples is how many of the worst samples the Markdown report lists.
+const ocrEvalWorstSamples = 10
+
+// OCREvalCase is an image with the text it should be recognized as.
+type OCREvalCase struct {
+	Image       string // Name in reports, such as the path relative to the corpus directory
+	Language    string // Tesseract language string, e.g. "nep" or "eng+nep"
+	Data        []byte
+	GroundTruth string
+}
+
+// LoadOCREvalCorpus reads the images of dir that have ground truth next to them (see
+// OCREvalGroundTruthSuffix). Images in a top-level subdirectory are in the language that directory is
+// named after (e.g., "nep/notice.png" or "eng+nep/form.jpg"); images directly in dir are in defaultLanguage.
+func LoadOCREvalCorpus(dir, defaultLanguage string) ([]OCREvalCase, error) {
+	var cases []OCREvalCase
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), OCREvalGroundTruthSuffix) {
+			return nil
+		}
+		groundTruth, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		base := strings.TrimSuffix(path, OCREvalGroundTruthSuffix)
+		for _, ext := range ocrEvalImageExtensions {
+			data, err := os.ReadFile(base + ext)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(dir, base+ext)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			language := defaultLanguage
+			if top, _, nested := strings.Cut(rel, "/"); nested {
+				language = top
+			}
+			cases = append(cases, OCREvalCase{Image: rel, Language: language, Data: data, GroundTruth: string(groundTruth)})
+			return nil
+		}
+		return fmt.Errorf("no image found for ground truth '%s'", path)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read OCR corpus '%s': %w", dir, err)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("OCR corpus '%s' has no images with ground truth", dir)
+	}
+	return cases, nil
+}
+
+// EvaluateOCR recognizes the cases one by one with ocr and measures the character and word error rates
+// against their ground truth, per sample, per language and overall. A failed recognition is recorded
+// on its sample (and counts as empty output); only cancellation of ctx stops the evaluation.
+func EvaluateOCR(ctx context.Context, ocr OCRService, cases []OCREvalCase, opts types.OCROptions) (*types.OCREvalReport, error) {
+	report := &types.OCREvalReport{GeneratedAt: time.Now().UTC(), Options: opts}
+	summaries := make(map[string]*types.OCREvalSummary)
+	overall := &types.OCREvalSummary{Language: OCREvalOverall}
+
+	for _, c := range cases {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("OCR evaluation stopped: %w", err)
+		}
+		sample := types.OCREvalSample{Image: c.Image, Language: c.Language}
+		start := time.Now()
+		result, err := ocr.ExtractTextFromImage(ctx, c.Data, c.Language, opts)
+		sample.Millis = time.Since(start).Milliseconds()
+		if err != nil {
+			sample.Error = err.Error()
+		} else {
+			sample.Text, sample.Confidence = result.Text, result.Confidence
+		}
+		sample.CharacterErrors, sample.Characters = utils.CharacterErrors(c.GroundTruth, sample.Text)
+		sample.WordErrors, sample.Words = utils.WordErrors(c.GroundTruth, sample.Text)
+		sample.CER = errorRate(sample.CharacterErrors, sample.Characters)
+		sample.WER = errorRate(sample.WordErrors, sample.Words)
+		report.Samples = append(report.Samples, sample)
+
+		summary := summaries[c.Language]
+		if summary == nil {
+			summary = &types.OCREvalSummary{Language: c.Language}
+			summaries[c.Language] = summary
+		}
+		addOCREvalSample(summary, sample)
+		addOCREvalSample(overall, sample)
+	}
+
+	for _, summary := range summaries {
+		report.Languages = append(report.Languages, finishOCREvalSummary(summary))
+	}
+	sort.Slice(report.Languages, func(i, j int) bool { return report.Languages[i].Language < report.Languages[j].Language })
+	sort.Slice(report.Samples, func(i, j int) bool { return report.Samples[i].Image < report.Samples[j].Image })
+	report.Overall = finishOCREvalSummary(overall)
+	return report, nil
+}
+
+// addOCREvalSample adds the counts of a sample to a summary. The confidence is summed until
+// finishOCREvalSummary averages it.
+func addOCREvalSample(summary *types.OCREvalSummary, sample types.OCREvalSample) {
+	summary.Samples++
+	if sample.Error != "" {
+		summary.Failed++
+	} else {
+		summary.Confidence += sample.Confidence
+	}
+	summary.Characters += sample.Characters
+	summary.CharacterErrors += sample.CharacterErrors
+	summary.Words += sample.Words
+	summary.WordErrors += sample.WordErrors
+	summary.Millis += sample.Millis
+}
+
+// finishOCREvalSummary computes the rates and the mean confidence of a summary.
+func finishOCREvalSummary(summary *types.OCREvalSummary) types.OCREvalSummary {
+	summary.CER = errorRate(summary.CharacterErrors, summary.Characters)
+	summary.WER = errorRate(summary.WordErrors, summary.Words)
+	if recognized := summary.Samples - summary.Failed; recognized > 0 {
+		summary.Confidence /= float64(recognized)
+	}
+	return *summary
+}
+
+// errorRate divides errors by the reference length. An empty reference has a rate of 1 if anything
+// was recognized at all, and 0 otherwise.
+func errorRate(errors, length int) float64 {
+	if length == 0 {
+		return float64(min(errors, 1))
+	}
+	return float64(errors) / float64(length)
+}
+
+// CompareOCREvalReports compares the overall and per-language error rates of report with those of
+// baseline. A rate that grew by more than tolerance (e.g., 0.005 for half a percentage point) is
+// flagged as regressed. Languages that are not in both reports are skipped.
+func CompareOCREvalReports(baseline, report *types.OCREvalReport, tolerance float64) []types.OCREvalChange {
+	before := map[string]types.OCREvalSummary{OCREvalOverall: baseline.Overall}
+	for _, summary := range baseline.Languages {
+		before[summary.Language] = summary
+	}
+
+	var changes []types.OCREvalChange
+	for _, current := range append([]types.OCREvalSummary{report.Overall}, report.Languages...) {
+		previous, ok := before[current.Language]
+		if !ok {
+			continue
+		}
+		for _, metric := range []struct {
+			name              string
+			baseline, current float64
+		}{{"cer", previous.CER, current.CER}, {"wer", previous.WER, current.WER}} {
+			delta := metric.current - metric.baseline
+			changes = append(changes, types.OCREvalChange{
+				Language: current.Language, Metric: metric.name,
+				Baseline: metric.baseline, Current: metric.current, Delta: delta,
+				Regressed: delta > tolerance,
+			})
+		}
+	}
+	return changes
+}
+
+// FormatOCREvalMarkdown renders a report as Markdown: a table of the error rates per language, the
+// comparison with a baseline when changes is not empty, and the samples with the highest CER.
+func FormatOCREvalMarkdown(report *types.OCREvalReport, changes []types.OCREvalChange) string {
+	var b strings.Builder
+	b.WriteString("# OCR accuracy\n\n")
+	fmt.Fprintf(&b, "Corpus `%s`, engine %s", report.Corpus, report.Engine)
+	if report.Preset != "" {
+		fmt.Fprintf(&b, ", preset `%s`", report.Preset)
+	}
+	fmt.Fprintf(&b, ", %s.\n\n", report.GeneratedAt.Format(time.RFC3339))
+
+	b.WriteString("| Language | Samples | Failed | CER | WER | Confidence | Time |\n")
+	b.WriteString("|---|---:|---:|---:|---:|---:|---:|\n")
+	for _, summary := range append(report.Languages, report.Overall) {
+		language := summary.Language
+		if language == OCREvalOverall {
+			language = "**" + language + "**"
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %s | %s | %.1f | %.1f s |\n", language, summary.Samples, summary.Failed,
+			percent(summary.CER), percent(summary.WER), summary.Confidence, float64(summary.Millis)/1000)
+	}
+
+	if len(changes) > 0 {
+		b.WriteString("\n## Compared with the baseline\n\n")
+		b.WriteString("| Language | Metric | Baseline | Current | Change |\n")
+		b.WriteString("|---|---|---:|---:|---:|\n")
+		for _, change := range changes {
+			verdict := ""
+			if change.Regressed {
+				verdict = " **regressed**"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %+.2f pt%s |\n", change.Language, strings.ToUpper(change.Metric),
+				percent(change.Baseline), percent(change.Current), change.Delta*100, verdict)
+		}
+	}
+
+	worst := append([]types.OCREvalSample(nil), report.Samples...)
+	sort.SliceStable(worst, func(i, j int) bool { return worst[i].CER > worst[j].CER })
+	worst = worst[:min(len(worst), ocrEvalWorstSamples)]
+	if len(worst) > 0 {
+		b.WriteString("\n## Worst samples\n\n")
+		b.WriteString("| Image | Language | CER | WER | Error |\n")
+		b.WriteString("|---|---|---:|---:|---|\n")
+		for _, sample := range worst {
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", sample.Image, sample.Language,
+				percent(sample.CER), percent(sample.WER), strings.ReplaceAll(sample.Error, "|", "\\|"))
+		}
+	}
+	return b.String()
+}
+
+// percent formats a rate as a percentage with two decimals.
+func percent(rate float64) string {
+	return fmt.Sprintf("%.2f%%", rate*100)
+}
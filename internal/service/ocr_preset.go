@@ -0,0 +1,254 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/danielgtaylor/huma/v2" // For Huma-specific error types
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path to your module
+)
+
+// Limits applied to per-request OCR options so a single scan cannot bloat Tesseract's dictionaries.
+const (
+	maxCharListLength   = 1024
+	maxUserWords        = 10000
+	maxUserWordRunes    = 64
+	maxUserPatterns     = 1000
+	maxUserPatternRunes = 128
+)
+
+// builtinOCRPresets are always available. Entries from OCR_PRESETS_FILE may override them by name.
+var builtinOCRPresets = []types.OCRPreset{
+	{
+		Name:        "default",
+		Description: "Tesseract defaults (fully automatic page segmentation)",
+	},
+	{
+		Name:        "prose",
+		Description: "Running text in one or more columns",
+		Options: types.OCROptions{
+			PageSegMode: intPtr(3),
+			PostProcess: types.TextPostProcessOptions{RepairLineBreaks: boolPtr(true), FixConfusions: boolPtr(true)},
+		},
+	},
+	{
+		Name:        "legal-form",
+		Description: "Single uniform block with preserved spacing, for printed forms",
+		Options: types.OCROptions{
+			PageSegMode:             intPtr(6),
+			PreserveInterwordSpaces: boolPtr(true),
+			PostProcess:             types.TextPostProcessOptions{FixConfusions: boolPtr(true)},
+		},
+	},
+	{
+		Name:        "single-line",
+		Description: "A single line of text (e.g., a cropped heading or field)",
+		Options:     types.OCROptions{PageSegMode: intPtr(7)},
+	},
+	{
+		Name:        "sparse",
+		Description: "Find as much text as possible in no particular order (notices, stamps)",
+		Options:     types.OCROptions{PageSegMode: intPtr(11)},
+	},
+	{
+		Name:        "numeric",
+		Description: "Numbers and dates in ASCII or Devanagari digits",
+		Options: types.OCROptions{
+			PageSegMode:   intPtr(6),
+			CharWhitelist: "0123456789०१२३४५६७८९/-.,: ",
+		},
+	},
+}
+
+// OCRPresetService manages named OCR option presets and validates per-request options.
+type OCRPresetService interface {
+	// ListPresets returns all available presets, sorted by name.
+	ListPresets() []types.OCRPreset
+
+	// ResolveOptions applies `overrides` on top of the named preset (if any) and validates the result.
+	// Returns a huma 400 error for unknown presets or invalid option values.
+	ResolveOptions(preset string, overrides types.OCROptions) (types.OCROptions, error)
+}
+
+// ocrPresetService is the concrete, read-only implementation of OCRPresetService.
+type ocrPresetService struct {
+	presets map[string]types.OCRPreset
+}
+
+// NewOCRPresetService creates an OCRPresetService from the built-in presets plus,
+// when `presetsFile` is set, the presets defined in that JSON file (an array of OCRPreset).
+// An unreadable or invalid file is logged and ignored so the built-ins stay usable.
+func NewOCRPresetService(presetsFile string) OCRPresetService {
+	s := &ocrPresetService{presets: make(map[string]types.OCRPreset)}
+	for _, preset := range builtinOCRPresets {
+		s.presets[preset.Name] = preset
+	}
+
+	if presetsFile != "" {
+		if err := s.loadFile(presetsFile); err != nil {
+			log.Printf("WARNING: Failed to load OCR presets from '%s': %v", presetsFile, err)
+		}
+	}
+
+	log.Printf("INFO: %d OCR preset(s) available.", len(s.presets))
+	return s
+}
+
+// loadFile reads and validates presets from a JSON file, adding them to the service.
+func (s *ocrPresetService) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read presets file: %w", err)
+	}
+
+	var presets []types.OCRPreset
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return fmt.Errorf("failed to parse presets file: %w", err)
+	}
+
+	for _, preset := range presets {
+		preset.Name = strings.TrimSpace(preset.Name)
+		if preset.Name == "" {
+			return fmt.Errorf("preset without a name")
+		}
+		if err := ValidateOCROptions(preset.Options); err != nil {
+			return fmt.Errorf("preset '%s': %w", preset.Name, err)
+		}
+		s.presets[preset.Name] = preset
+	}
+	return nil
+}
+
+// ListPresets returns all available presets, sorted by name.
+func (s *ocrPresetService) ListPresets() []types.OCRPreset {
+	presets := make([]types.OCRPreset, 0, len(s.presets))
+	for _, preset := range s.presets {
+		presets = append(presets, preset)
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets
+}
+
+// ResolveOptions applies `overrides` on top of the named preset (if any) and validates the result.
+func (s *ocrPresetService) ResolveOptions(preset string, overrides types.OCROptions) (types.OCROptions, error) {
+	var base types.OCROptions
+	if preset != "" {
+		p, ok := s.presets[preset]
+		if !ok {
+			names := make([]string, 0, len(s.presets))
+			for name := range s.presets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return types.OCROptions{}, huma.Error400BadRequest(fmt.Sprintf("Unknown OCR preset '%s'. Available presets are: %s.", preset, strings.Join(names, ", ")), nil)
+		}
+		base = p.Options
+	}
+
+	opts := MergeOCROptions(base, overrides)
+	if err := ValidateOCROptions(opts); err != nil {
+		return types.OCROptions{}, huma.Error400BadRequest(fmt.Sprintf("Invalid OCR options: %v", err), nil)
+	}
+	return opts, nil
+}
+
+// MergeOCROptions returns `base` with every field that is set in `overrides` replaced.
+// User words and patterns are combined rather than replaced.
+func MergeOCROptions(base, overrides types.OCROptions) types.OCROptions {
+	merged := base
+	if overrides.Engine != "" {
+		merged.Engine = overrides.Engine
+	}
+	if overrides.PageSegMode != nil {
+		merged.PageSegMode = overrides.PageSegMode
+	}
+	if overrides.EngineMode != nil {
+		merged.EngineMode = overrides.EngineMode
+	}
+	if overrides.CharWhitelist != "" {
+		merged.CharWhitelist = overrides.CharWhitelist
+	}
+	if overrides.CharBlacklist != "" {
+		merged.CharBlacklist = overrides.CharBlacklist
+	}
+	if overrides.PreserveInterwordSpaces != nil {
+		merged.PreserveInterwordSpaces = overrides.PreserveInterwordSpaces
+	}
+	if overrides.PostProcess.NormalizeUnicode != nil {
+		merged.PostProcess.NormalizeUnicode = overrides.PostProcess.NormalizeUnicode
+	}
+	if overrides.PostProcess.Digits != "" {
+		merged.PostProcess.Digits = overrides.PostProcess.Digits
+	}
+	if overrides.PostProcess.RepairLineBreaks != nil {
+		merged.PostProcess.RepairLineBreaks = overrides.PostProcess.RepairLineBreaks
+	}
+	if overrides.PostProcess.FixConfusions != nil {
+		merged.PostProcess.FixConfusions = overrides.PostProcess.FixConfusions
+	}
+	merged.UserWords = append(append([]string(nil), base.UserWords...), overrides.UserWords...)
+	merged.UserPatterns = append(append([]string(nil), base.UserPatterns...), overrides.UserPatterns...)
+	return merged
+}
+
+// ValidateOCROptions checks that option values are within the ranges Tesseract accepts
+// and within the per-request size limits.
+func ValidateOCROptions(opts types.OCROptions) error {
+	if opts.PageSegMode != nil {
+		psm := *opts.PageSegMode
+		// 0 (OSD only) and 2 (segmentation only) never produce text.
+		if psm < 1 || psm > 13 || psm == 2 {
+			return fmt.Errorf("page segmentation mode must be 1 or 3-13, got %d", psm)
+		}
+	}
+	if opts.EngineMode != nil {
+		if oem := *opts.EngineMode; oem < 0 || oem > 3 {
+			return fmt.Errorf("OCR engine mode must be 0-3, got %d", oem)
+		}
+	}
+	switch opts.PostProcess.Digits {
+	case utils.DigitsUnchanged, utils.DigitsASCII, utils.DigitsDevanagari:
+	default:
+		return fmt.Errorf("digits must be '%s' or '%s', got '%s'", utils.DigitsASCII, utils.DigitsDevanagari, opts.PostProcess.Digits)
+	}
+	if utf8.RuneCountInString(opts.CharWhitelist) > maxCharListLength {
+		return fmt.Errorf("whitelist exceeds %d characters", maxCharListLength)
+	}
+	if utf8.RuneCountInString(opts.CharBlacklist) > maxCharListLength {
+		return fmt.Errorf("blacklist exceeds %d characters", maxCharListLength)
+	}
+	if err := validateEntries("user word", opts.UserWords, maxUserWords, maxUserWordRunes); err != nil {
+		return err
+	}
+	return validateEntries("user pattern", opts.UserPatterns, maxUserPatterns, maxUserPatternRunes)
+}
+
+// validateEntries checks the count and length of user word/pattern lists.
+// Entries are written one per line to Tesseract files, so they must not contain line breaks.
+func validateEntries(kind string, entries []string, maxEntries, maxRunes int) error {
+	if len(entries) > maxEntries {
+		return fmt.Errorf("too many %ss: %d (maximum %d)", kind, len(entries), maxEntries)
+	}
+	for _, entry := range entries {
+		if entry == "" || strings.ContainsAny(entry, "\r\n") {
+			return fmt.Errorf("%s entries must be non-empty single lines", kind)
+		}
+		if utf8.RuneCountInString(entry) > maxRunes {
+			return fmt.Errorf("%s '%s' exceeds %d characters", kind, entry, maxRunes)
+		}
+	}
+	return nil
+}
+
+// intPtr returns a pointer to v, for optional integer option fields.
+func intPtr(v int) *int { return &v }
+
+// boolPtr returns a pointer to v, for optional boolean option fields.
+func boolPtr(v bool) *bool { return &v }
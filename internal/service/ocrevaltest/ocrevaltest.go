@@ -0,0 +1,40 @@
+// Package ocrevaltest lets tests require a minimum OCR accuracy on a corpus of images with ground truth,
+// like cmd/ocr-eval does. It is separate from package service so that "testing" is not linked into the
+// server.
+package ocrevaltest
+
+import (
+	"testing"
+
+	"github.com/axyut/niyamAPI/internal/service" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"   // Adjust import path to your module
+)
+
+// RequireOCRAccuracy recognizes the corpus in dir (see service.LoadOCREvalCorpus; images directly in dir
+// are English) with svc and the default options, and fails the test if the overall character error rate
+// is above maxCER (e.g., 0.05 for 5%). The samples that failed or missed the target are logged.
+func RequireOCRAccuracy(t testing.TB, svc service.OCRService, dir string, maxCER float64) {
+	t.Helper()
+	cases, err := service.LoadOCREvalCorpus(dir, types.LangEnglish)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := service.EvaluateOCR(t.Context(), svc, cases, types.OCROptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Overall.CER <= maxCER {
+		return
+	}
+	for _, sample := range report.Samples {
+		switch {
+		case sample.Error != "":
+			t.Logf("%s: recognition failed: %s", sample.Image, sample.Error)
+		case sample.CER > maxCER:
+			t.Logf("%s: CER %.2f%%, recognized %q", sample.Image, sample.CER*100, sample.Text)
+		}
+	}
+	t.Errorf("OCR of '%s' has a CER of %.2f%% (%d of %d characters wrong, %d of %d images failed), want at most %.2f%%",
+		dir, report.Overall.CER*100, report.Overall.CharacterErrors, report.Overall.Characters,
+		report.Overall.Failed, report.Overall.Samples, maxCER*100)
+}
@@ -0,0 +1,51 @@
+package ocrevaltest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axyut/niyamAPI/internal/config"  // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/service" // Adjust import path to your module
+)
+
+// recordingTB records the failures of a test instead of failing it.
+type recordingTB struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+// fakeOCRService recognizes images with the fake engine, whose output the ground truth in testdata is:
+// "fake <language> <start of the image's SHA-256>". Only eng/smudged.gt.txt does not match.
+func fakeOCRService() service.OCRService {
+	cfg := &config.AppConfig{OCREngines: []string{service.OCREngineFake}, OCRTimeout: time.Minute, OCRMaxConcurrency: 1}
+	return service.NewOCRService(cfg, service.NewOCREngineRegistry(cfg, ""))
+}
+
+func TestRequireOCRAccuracy(t *testing.T) {
+	// Two of the three samples are right; 12 of the 63 characters are wrong.
+	RequireOCRAccuracy(t, fakeOCRService(), "testdata", 0.25)
+}
+
+func TestRequireOCRAccuracyFails(t *testing.T) {
+	recorder := &recordingTB{TB: t}
+	RequireOCRAccuracy(recorder, fakeOCRService(), "testdata", 0.1)
+	if len(recorder.errors) != 1 || !strings.Contains(recorder.errors[0], "want at most 10.00%") {
+		t.Errorf("errors = %q, want one about the 10%% target", recorder.errors)
+	}
+	if len(recorder.logs) != 1 || !strings.HasPrefix(recorder.logs[0], "eng/smudged.png: CER ") {
+		t.Errorf("logs = %q, want eng/smudged.png only", recorder.logs)
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path to your module
+)
+
+// PostProcessText runs the configured clean-up stages over raw Tesseract output, in order:
+// Unicode normalization, confusion-pair fixes, digit conversion and line-break repair.
+func PostProcessText(text string, opts types.OCROptions) string {
+	pp := opts.PostProcess
+
+	if pp.NormalizeUnicode == nil || *pp.NormalizeUnicode {
+		text = utils.NormalizeDevanagari(text)
+	}
+	if pp.FixConfusions != nil && *pp.FixConfusions {
+		text = utils.FixDevanagariConfusions(text)
+	}
+	text = utils.ConvertDigits(text, pp.Digits)
+	if pp.RepairLineBreaks != nil && *pp.RepairLineBreaks {
+		preserveSpaces := opts.PreserveInterwordSpaces != nil && *opts.PreserveInterwordSpaces
+		text = utils.RepairLineBreaks(text, preserveSpaces)
+	}
+	return text
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path to your module
+)
+
+// qualityAnalysisSide is the longer side images are reduced to before measuring them, so a phone photo
+// and a 600 DPI scan of the same page get comparable sharpness and skew values.
+const qualityAnalysisSide = 1600
+
+// textLineInches is the assumed height of a body text line (about 11 pt with ascenders and descenders,
+// or with the Devanagari headline and vowel signs), used to estimate the resolution from the text.
+const textLineInches = 0.15
+
+// Quality thresholds; each measurement maps linearly to a 0-1 component between its bad and good values.
+const (
+	blurrySharpness, sharpSharpness = 60.0, 250.0
+	darkPaper, goodPaper            = 80.0, 150.0
+	flatContrast, goodContrast      = 40.0, 120.0
+	lowDPI, goodDPI                 = 100.0, 200.0
+	goodSkew, badSkew               = 1.0, 10.0
+	retakeScore                     = 60
+)
+
+// QualityService assesses whether images are good enough for OCR.
+type QualityService interface {
+	// AssessImage measures the sharpness, exposure, resolution and skew of a PNG or JPEG image and returns
+	// a score with advice. Images in other formats yield an error.
+	AssessImage(imageData []byte) (*types.ImageQuality, error)
+}
+
+// qualityService is the concrete, stateless implementation of QualityService.
+type qualityService struct{}
+
+// NewQualityService creates and returns a new instance of QualityService.
+func NewQualityService() QualityService {
+	return &qualityService{}
+}
+
+// AssessImage measures an image and scores its fitness for OCR.
+func (s *qualityService) AssessImage(imageData []byte) (*types.ImageQuality, error) {
+	img, format, err := utils.DecodeImage(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot assess image quality: %w", err)
+	}
+	gray := utils.Grayscale(img)
+	small, factor := utils.ShrinkGray(gray, qualityAnalysisSide)
+
+	quality := &types.ImageQuality{
+		Width:     gray.Bounds().Dx(),
+		Height:    gray.Bounds().Dy(),
+		Sharpness: math.Round(utils.LaplacianVariance(small)*10) / 10,
+		Skew:      utils.EstimateSkew(small),
+	}
+	paper, ink := utils.PaperAndInk(small)
+	quality.Brightness, quality.Contrast = math.Round(paper*10)/10, math.Round((paper-ink)*10)/10
+	quality.TextHeight = utils.EstimateTextHeight(small, quality.Skew) * factor
+
+	// Scanners record their resolution; phone cameras write a meaningless 72 or 96 DPI, so low values
+	// are ignored in favor of the estimate.
+	quality.DPISource = "unknown"
+	if dpi := utils.ImageDPI(imageData, format); dpi >= 100 {
+		quality.DPI, quality.DPISource = dpi, "metadata"
+	} else if quality.TextHeight > 0 {
+		quality.DPI, quality.DPISource = int(math.Round(float64(quality.TextHeight)/textLineInches)), "estimated"
+	}
+
+	sharpness := linearScore(quality.Sharpness, blurrySharpness, sharpSharpness)
+	exposure := linearScore(quality.Brightness, darkPaper, goodPaper)
+	// Faint text on a washed-out page is as unreadable as text on a dark one; contrast captures both.
+	contrastScore := linearScore(quality.Contrast, flatContrast, goodContrast)
+	skew := linearScore(-math.Abs(quality.Skew), -badSkew, -goodSkew)
+	resolution := 1.0
+	if quality.DPI > 0 {
+		resolution = linearScore(float64(quality.DPI), lowDPI, goodDPI)
+	}
+	quality.Score = int(math.Round(100 * (0.35*sharpness + 0.15*exposure + 0.15*contrastScore + 0.2*resolution + 0.15*skew)))
+
+	issue := func(code string, component float64, advice string) {
+		if component >= 0.5 {
+			return
+		}
+		severity := types.QualitySeverityWarning
+		if component < 0.25 {
+			severity = types.QualitySeverityCritical
+		}
+		quality.Issues = append(quality.Issues, types.QualityIssue{Code: code, Severity: severity, Advice: advice})
+	}
+	quality.Issues = []types.QualityIssue{}
+	issue(types.QualityIssueBlurry, sharpness, "The image is blurry. Hold the phone steady, tap the page to focus, and make sure there is enough light.")
+	issue(types.QualityIssueDark, exposure, "The image is too dark. Move to a brighter place or use the flash, avoiding glare on the page.")
+	if quality.Brightness > 245 {
+		issue(types.QualityIssueOverexposed, contrastScore, "The image is overexposed and the text is washed out. Avoid direct light or flash glare on the page.")
+	} else {
+		issue(types.QualityIssueLowContrast, contrastScore, "The text has little contrast with the paper. Use even lighting without shadows, or scan in grayscale rather than color.")
+	}
+	if quality.DPI > 0 {
+		issue(types.QualityIssueLowResolution, resolution, fmt.Sprintf("The text is small in the image (about %d DPI). Move closer so the page fills the frame, or scan at 300 DPI.", quality.DPI))
+	}
+	issue(types.QualityIssueSkewed, skew, fmt.Sprintf("The page is tilted by %.1f°. Align the page edges with the edges of the camera frame.", math.Abs(quality.Skew)))
+	if quality.TextHeight == 0 {
+		quality.Issues = append(quality.Issues, types.QualityIssue{Code: types.QualityIssueNoText, Severity: types.QualitySeverityWarning,
+			Advice: "No lines of text were found. Make sure the whole page is in the frame and in focus."})
+	}
+	sort.SliceStable(quality.Issues, func(i, j int) bool {
+		return quality.Issues[i].Severity == types.QualitySeverityCritical && quality.Issues[j].Severity != types.QualitySeverityCritical
+	})
+
+	quality.Retake = quality.Score < retakeScore
+	for _, issue := range quality.Issues {
+		quality.Retake = quality.Retake || issue.Severity == types.QualitySeverityCritical
+	}
+	return quality, nil
+}
+
+// linearScore maps v to 0 at `bad` and 1 at `good`, clamped to 0-1.
+func linearScore(v, bad, good float64) float64 {
+	return clamp01((v - bad) / (good - bad))
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/axyut/niyamAPI/internal/repository" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils"      // Adjust import path to your module
+)
+
+// Thumbnail settings for stored scan images.
+const (
+	thumbnailMaxSide = 256 // Longer side of a thumbnail, in pixels
+	thumbnailQuality = 80  // JPEG quality of thumbnails
+)
+
+// ScanService stores OCR results (and the scanned images) so they can be referenced later,
+// e.g., as document pages.
+type ScanService interface {
+	// RecordScan stores a scan record (ID, owner, language, preset and text set by the caller; the ID
+	// may be preallocated) together with the uploaded page images. The images of anonymous scans (empty
+	// owner), which no one but admins could read back, are not kept.
+	RecordScan(ctx context.Context, scan *types.ScanRecord, pages [][]byte) (*types.ScanRecord, error)
+	// GetScanImage returns a page image of a scan (1-based), or the thumbnail of its first page,
+	// if the user may see the scan.
+	GetScanImage(ctx context.Context, user *types.AuthClaims, id string, page int, thumbnail bool) (*types.ScanImageOutput, error)
+}
+
+// scanService is the concrete implementation of ScanService.
+type scanService struct {
+	scanRepo    repository.ScanRepository
+	searchIndex repository.SearchIndex
+	blobStore   repository.BlobStore
+}
+
+// NewScanService creates and returns a new instance of ScanService.
+// Stored scans are added to `searchIndex` so they can be found through /search,
+// and their images are kept in `blobStore`.
+func NewScanService(scanRepo repository.ScanRepository, searchIndex repository.SearchIndex, blobStore repository.BlobStore) ScanService {
+	return &scanService{scanRepo: scanRepo, searchIndex: searchIndex, blobStore: blobStore}
+}
+
+// RecordScan stores a scan. The images are stored first so the record can reference them;
+// failing to store them (or the thumbnail) does not prevent storing the text.
+func (s *scanService) RecordScan(ctx context.Context, record *types.ScanRecord, pages [][]byte) (*types.ScanRecord, error) {
+	record.CreatedAt = time.Now()
+	record.Pages = len(pages)
+	if len(pages) > 0 && record.OwnerID != "" {
+		s.storeImages(ctx, record, pages)
+	}
+
+	scan, err := s.scanRepo.CreateScan(ctx, record)
+	if err != nil {
+		log.Printf("ERROR: Service failed to store scan: %v", err)
+		return nil, fmt.Errorf("failed to store scan")
+	}
+
+	indexSearchItem(ctx, s.searchIndex, &types.SearchItem{
+		Kind:      types.SearchKindScan,
+		ItemID:    scan.ID.Hex(),
+		OwnerID:   scan.OwnerID,
+		Language:  scan.Language,
+		Text:      scan.Text,
+		CreatedAt: scan.CreatedAt,
+	})
+	return scan, nil
+}
+
+// storeImages puts the page images and a JPEG thumbnail of the first page into the blob store and
+// records their keys on `record`. Formats the standard library cannot decode (e.g., TIFF) are stored
+// without a thumbnail.
+func (s *scanService) storeImages(ctx context.Context, record *types.ScanRecord, pages [][]byte) {
+	if s.blobStore == nil {
+		return
+	}
+	if len(pages) > 1 {
+		for i, page := range pages {
+			key, err := s.blobStore.Put(ctx, page)
+			if err != nil {
+				log.Printf("WARNING: Failed to store image of scan page %d: %v", i+1, err)
+				record.PageImageKeys = nil
+				break
+			}
+			record.PageImageKeys = append(record.PageImageKeys, key)
+		}
+	}
+
+	// The first page is also the scan's image; it is already stored if the page images are.
+	image := pages[0]
+	var key string
+	var err error
+	if len(record.PageImageKeys) > 0 {
+		key = record.PageImageKeys[0]
+	} else if key, err = s.blobStore.Put(ctx, image); err != nil {
+		log.Printf("WARNING: Failed to store scan image: %v", err)
+		return
+	}
+	record.ImageKey = key
+	record.ImageType = utils.ImageFormatMIMEType(utils.DetectImageFormat(image))
+	if record.ImageType == "" {
+		record.ImageType = http.DetectContentType(image)
+	}
+	record.ImageSize = int64(len(image))
+
+	img, _, err := utils.DecodeImage(image)
+	if err != nil {
+		log.Printf("INFO: No thumbnail for scan image %s: %v", key, err)
+		return
+	}
+	bounds := img.Bounds()
+	record.ImageWidth, record.ImageHeight = bounds.Dx(), bounds.Dy()
+
+	thumb, err := utils.EncodeJPEG(utils.Thumbnail(img, thumbnailMaxSide), thumbnailQuality)
+	if err != nil {
+		log.Printf("WARNING: Failed to create thumbnail for scan image %s: %v", key, err)
+		return
+	}
+	if record.ThumbnailKey, err = s.blobStore.Put(ctx, thumb); err != nil {
+		log.Printf("WARNING: Failed to store thumbnail for scan image %s: %v", key, err)
+	}
+}
+
+// GetScanImage returns a page image of a scan, or the thumbnail of its first page. Scans of other users
+// (and anonymous scans) are reported as not found unless the caller is an admin.
+func (s *scanService) GetScanImage(ctx context.Context, user *types.AuthClaims, id string, page int, thumbnail bool) (*types.ScanImageOutput, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid scan ID format", nil)
+	}
+
+	scan, err := s.scanRepo.GetScanByID(ctx, objID)
+	if err != nil {
+		if err.Error() == "scan not found" {
+			return nil, huma.Error404NotFound("scan not found", nil)
+		}
+		log.Printf("ERROR: Service failed to get scan by ID %s: %v", id, err)
+		return nil, fmt.Errorf("failed to retrieve scan")
+	}
+	if scan.OwnerID != user.UserID && user.Role != "admin" {
+		return nil, huma.Error404NotFound("scan not found", nil)
+	}
+
+	if page > max(scan.Pages, 1) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("scan has no page %d", page), nil)
+	}
+	key, contentType := scan.ImageKey, scan.ImageType
+	switch {
+	case thumbnail:
+		key, contentType = scan.ThumbnailKey, "image/jpeg"
+	case page > 1:
+		key, contentType = "", "" // Detected from the data below.
+		if page <= len(scan.PageImageKeys) {
+			key = scan.PageImageKeys[page-1]
+		}
+	}
+	if key == "" || s.blobStore == nil {
+		return nil, huma.Error404NotFound("no image stored for this scan", nil)
+	}
+
+	data, err := s.blobStore.Get(ctx, key)
+	if err != nil {
+		if err.Error() == "blob not found" {
+			return nil, huma.Error404NotFound("no image stored for this scan", nil)
+		}
+		log.Printf("ERROR: Service failed to read image %s of scan %s: %v", key, id, err)
+		return nil, fmt.Errorf("failed to retrieve scan image")
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	return &types.ScanImageOutput{
+		ContentType:  contentType,
+		ETag:         `"` + key + `"`,
+		CacheControl: "private, max-age=31536000, immutable", // Content-addressed, so it never changes.
+		Body:         data,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// scanJobRetention is how long the events of a finished scan job stay available for (re)connecting clients.
+const scanJobRetention = time.Hour
+
+// ScanJobService tracks the progress of asynchronous scans. Each job keeps an ordered event log
+// that subscribers receive from any point, so clients can reconnect with Last-Event-ID.
+// Jobs live in memory only; they are lost on restart (the stored scan itself is not).
+type ScanJobService interface {
+	// CreateJob registers a job for the scan with the given ID and logs its "queued" event.
+	CreateJob(id, ownerID string, pages int)
+	// Publish appends an event to a job's log. Events after a Completed or Failed event are dropped.
+	Publish(id string, event types.ScanEvent)
+	// CheckAccess reports a 404 error if the job does not exist or belongs to another user (admins see all jobs).
+	CheckAccess(user *types.AuthClaims, id string) error
+	// Subscribe streams the job's events after `lastEventID` until the job finishes or ctx is cancelled.
+	Subscribe(ctx context.Context, user *types.AuthClaims, id string, lastEventID int) (<-chan types.ScanEvent, error)
+}
+
+// scanJob is the state of one asynchronous scan.
+type scanJob struct {
+	ownerID  string
+	events   []types.ScanEvent
+	finished time.Time     // Zero while the job is running
+	changed  chan struct{} // Closed (and replaced) whenever an event is added
+}
+
+// scanJobService is the in-memory implementation of ScanJobService.
+type scanJobService struct {
+	mu   sync.Mutex
+	jobs map[string]*scanJob
+}
+
+// NewScanJobService creates and returns a new instance of ScanJobService.
+func NewScanJobService() ScanJobService {
+	return &scanJobService{jobs: make(map[string]*scanJob)}
+}
+
+// CreateJob registers a job and removes jobs that finished more than scanJobRetention ago.
+func (s *scanJobService) CreateJob(id, ownerID string, pages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for jobID, job := range s.jobs {
+		if !job.finished.IsZero() && time.Since(job.finished) > scanJobRetention {
+			delete(s.jobs, jobID)
+		}
+	}
+
+	s.jobs[id] = &scanJob{
+		ownerID: ownerID,
+		events: []types.ScanEvent{{ID: 1, Progress: &types.ScanProgressEvent{
+			Stage: types.ScanStageQueued, Pages: pages, Message: "scan queued",
+		}}},
+		changed: make(chan struct{}),
+	}
+}
+
+// Publish appends an event and wakes up the job's subscribers.
+func (s *scanJobService) Publish(id string, event types.ScanEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || !job.finished.IsZero() {
+		return // Unknown job, or late progress from an abandoned OCR run.
+	}
+	event.ID = len(job.events) + 1
+	job.events = append(job.events, event)
+	if event.Completed != nil || event.Failed != nil {
+		job.finished = time.Now()
+	}
+	close(job.changed)
+	job.changed = make(chan struct{})
+}
+
+// CheckAccess verifies that the job exists and that the user may follow it.
+func (s *scanJobService) CheckAccess(user *types.AuthClaims, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.jobFor(user, id)
+	return err
+}
+
+// jobFor returns the job if the user may see it. The caller must hold s.mu.
+func (s *scanJobService) jobFor(user *types.AuthClaims, id string) (*scanJob, error) {
+	job, ok := s.jobs[id]
+	if !ok || (job.ownerID != user.UserID && user.Role != "admin") {
+		return nil, huma.Error404NotFound(fmt.Sprintf("no scan job with ID '%s' (jobs are kept for %s after they finish)", id, scanJobRetention), nil)
+	}
+	return job, nil
+}
+
+// Subscribe returns a channel delivering the events after `lastEventID`. The channel is closed
+// after the final event, or when ctx is cancelled.
+func (s *scanJobService) Subscribe(ctx context.Context, user *types.AuthClaims, id string, lastEventID int) (<-chan types.ScanEvent, error) {
+	s.mu.Lock()
+	job, err := s.jobFor(user, id)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan types.ScanEvent)
+	go func() {
+		defer close(out)
+		next := max(lastEventID, 0) // Index of the next event to deliver
+		for {
+			s.mu.Lock()
+			pending := append([]types.ScanEvent(nil), job.events[min(next, len(job.events)):]...)
+			finished := !job.finished.IsZero()
+			changed := job.changed
+			s.mu.Unlock()
+
+			for _, event := range pending {
+				select {
+				case out <- event:
+					next = event.ID
+				case <-ctx.Done():
+					return
+				}
+			}
+			if finished {
+				return
+			}
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
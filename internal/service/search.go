@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2" // For Huma-specific error types
+
+	"github.com/axyut/niyamAPI/internal/repository" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils"      // Adjust import path to your module
+)
+
+// snippetRunes is the approximate length of the excerpt returned with each search result.
+const snippetRunes = 240
+
+// SearchService answers full-text queries over stored scans and documents.
+type SearchService interface {
+	// Search runs a query for `user`. Non-admins only see their own items; admins see all items
+	// and may filter by owner.
+	Search(ctx context.Context, user *types.AuthClaims, input *types.SearchInput) (*types.SearchOutput, error)
+}
+
+// searchService is the concrete implementation of SearchService.
+type searchService struct {
+	index repository.SearchIndex
+}
+
+// NewSearchService creates and returns a new instance of SearchService.
+func NewSearchService(index repository.SearchIndex) SearchService {
+	return &searchService{index: index}
+}
+
+// Search runs a full-text query and builds highlighted snippets for the hits.
+func (s *searchService) Search(ctx context.Context, user *types.AuthClaims, input *types.SearchInput) (*types.SearchOutput, error) {
+	query := utils.ParseSearchQuery(input.Query)
+	if query.IsEmpty() {
+		return nil, huma.Error400BadRequest("search query contains no words", nil)
+	}
+
+	filter := types.SearchFilter{
+		OwnerID:  user.UserID,
+		Language: strings.TrimSpace(input.Language),
+		Kind:     input.Kind,
+		Limit:    input.Limit,
+		Offset:   input.Offset,
+	}
+	if user.Role == "admin" {
+		filter.OwnerID = strings.TrimSpace(input.Owner)
+	} else if input.Owner != "" && input.Owner != user.UserID {
+		return nil, huma.Error403Forbidden("only admins can search other users' items", nil)
+	}
+
+	var err error
+	if filter.From, err = parseSearchDate("from", input.From); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseSearchDate("to", input.To); err != nil {
+		return nil, err
+	}
+	if !filter.To.IsZero() {
+		filter.To = filter.To.AddDate(0, 0, 1) // Make the end date inclusive.
+	}
+
+	hits, total, err := s.index.Search(ctx, query, filter)
+	if err != nil {
+		log.Printf("ERROR: Service failed to search for %q: %v", input.Query, err)
+		return nil, fmt.Errorf("failed to search")
+	}
+
+	resp := &types.SearchOutput{}
+	resp.Body.Total = total
+	resp.Body.Results = make([]types.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		resp.Body.Results = append(resp.Body.Results, types.SearchResult{
+			Kind:      hit.Item.Kind,
+			ID:        hit.Item.ItemID,
+			Title:     hit.Item.Title,
+			Language:  hit.Item.Language,
+			Score:     hit.Score,
+			Snippet:   utils.Highlight(hit.Item.Text, query, snippetRunes, "<mark>", "</mark>", html.EscapeString),
+			CreatedAt: hit.Item.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// parseSearchDate parses an optional YYYY-MM-DD query parameter.
+func parseSearchDate(name, value string) (time.Time, error) {
+	if value = strings.TrimSpace(value); value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, huma.Error400BadRequest(fmt.Sprintf("%s must be a date in YYYY-MM-DD format, got '%s'", name, value), nil)
+	}
+	return date, nil
+}
+
+// indexSearchItem adds an item to the search index. Indexing is best effort: a failure is
+// logged but never fails the request that stored the scan or document.
+func indexSearchItem(ctx context.Context, index repository.SearchIndex, item *types.SearchItem) {
+	if index == nil {
+		return
+	}
+	if err := index.IndexItem(ctx, item); err != nil {
+		log.Printf("WARNING: Failed to index %s %s for search: %v", item.Kind, item.ItemID, err)
+	}
+}
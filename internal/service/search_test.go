@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/axyut/niyamAPI/internal/repository" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+)
+
+// newTestSearchService returns a search service over an in-memory index holding a few scans and documents.
+func newTestSearchService(t *testing.T) SearchService {
+	t.Helper()
+	index := repository.NewMemorySearchIndex()
+	created := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	items := []types.SearchItem{
+		{Kind: types.SearchKindScan, ItemID: "scan-1", OwnerID: "alice", Language: "nep",
+			Text: "दफा ५ को उपदफा (२) बमोजिम सजाय हुनेछ।", CreatedAt: created},
+		{Kind: types.SearchKindScan, ItemID: "scan-2", OwnerID: "alice", Language: "eng+nep",
+			Text: "दफा ६ को संशोधन। ५ वर्ष कैद।", CreatedAt: created.AddDate(0, 0, 1)},
+		{Kind: types.SearchKindDocument, ItemID: "doc-1", OwnerID: "bob", Language: "nep", Title: "मुलुकी ऐन",
+			Text: "दफा ५ संशोधित। संशोधनको मिति।", CreatedAt: created.AddDate(0, 0, 2)},
+	}
+	for i := range items {
+		if err := index.IndexItem(context.Background(), &items[i]); err != nil {
+			t.Fatalf("IndexItem: %v", err)
+		}
+	}
+	return NewSearchService(index)
+}
+
+func TestSearch(t *testing.T) {
+	svc := newTestSearchService(t)
+	alice := &types.AuthClaims{UserID: "alice", Role: "user"}
+	admin := &types.AuthClaims{UserID: "root", Role: "admin"}
+
+	tests := []struct {
+		name  string
+		user  *types.AuthClaims
+		input types.SearchInput
+		want  []string // Result IDs, best first
+	}{
+		{"words in any order, newest first on ties", alice, types.SearchInput{Query: "५ दफा"}, []string{"scan-2", "scan-1"}},
+		{"phrase", alice, types.SearchInput{Query: `"दफा ५"`}, []string{"scan-1"}},
+		{"phrase with Devanagari digits folded", alice, types.SearchInput{Query: `"दफा 5"`}, []string{"scan-1"}},
+		{"prefix", alice, types.SearchInput{Query: "संशोध*"}, []string{"scan-2"}},
+		{"own items only", alice, types.SearchInput{Query: "संशोधित"}, nil},
+		{"admin sees all", admin, types.SearchInput{Query: `"दफा ५"`}, []string{"doc-1", "scan-1"}},
+		{"admin filters by owner", admin, types.SearchInput{Query: "दफा", Owner: "bob"}, []string{"doc-1"}},
+		{"kind", admin, types.SearchInput{Query: "दफा", Kind: types.SearchKindDocument}, []string{"doc-1"}},
+		{"language", alice, types.SearchInput{Query: "दफा", Language: "eng"}, []string{"scan-2"}},
+		{"date range", alice, types.SearchInput{Query: "दफा", From: "2024-07-02", To: "2024-07-02"}, []string{"scan-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := svc.Search(context.Background(), tt.user, &tt.input)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			var got []string
+			for _, result := range out.Body.Results {
+				got = append(got, result.ID)
+			}
+			if len(got) != len(tt.want) || out.Body.Total != len(tt.want) {
+				t.Fatalf("got %v (total %d), want %v", got, out.Body.Total, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	svc := newTestSearchService(t)
+	out, err := svc.Search(context.Background(), &types.AuthClaims{UserID: "alice"}, &types.SearchInput{Query: `"दफा ५" बमोजिम`})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(out.Body.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(out.Body.Results))
+	}
+	want := "<mark>दफा</mark> <mark>५</mark> को उपदफा (२) <mark>बमोजिम</mark> सजाय हुनेछ।"
+	if got := out.Body.Results[0].Snippet; got != want {
+		t.Errorf("snippet = %q, want %q", got, want)
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	svc := newTestSearchService(t)
+	tests := []struct {
+		name   string
+		input  types.SearchInput
+		status int
+	}{
+		{"no words", types.SearchInput{Query: `"" ।`}, http.StatusBadRequest},
+		{"other owner", types.SearchInput{Query: "दफा", Owner: "bob"}, http.StatusForbidden},
+		{"bad date", types.SearchInput{Query: "दफा", From: "2024/07/01"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		_, err := svc.Search(context.Background(), &types.AuthClaims{UserID: "alice"}, &tt.input)
+		var statusErr huma.StatusError
+		if !errors.As(err, &statusErr) || statusErr.GetStatus() != tt.status {
+			t.Errorf("%s: got %v, want status %d", tt.name, err, tt.status)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"log"
+
 	"github.com/axyut/niyamAPI/internal/config"     // Adjust import path to your module
 	"github.com/axyut/niyamAPI/internal/db"         // Adjust import path to your module
 	"github.com/axyut/niyamAPI/internal/repository" // Adjust import path to your module
@@ -18,6 +20,36 @@ type Services struct {
 	// and then a concrete implementation (e.g., userService struct) that uses the db.Client.
 	UserService UserService
 	OCRService  OCRService // Assuming you have an OCR service for image processing
+	// EnsembleOCRService is the slower "accurate" OCR mode, merging several configurations of OCRService.
+	EnsembleOCRService OCRService
+	// OCREngines holds the OCR engine backends enabled on this server.
+	OCREngines OCREngineRegistry
+	// LanguageService lists the OCR languages installed on this server.
+	LanguageService LanguageService
+	// OCRPresetService resolves named OCR option presets and validates per-scan options.
+	OCRPresetService OCRPresetService
+	// DictionaryService manages per-domain custom OCR vocabularies stored in MongoDB.
+	DictionaryService DictionaryService
+	// ExtractionService reads the fields of fixed-layout forms using templates stored in MongoDB.
+	ExtractionService ExtractionService
+	// TextService transforms extracted text (e.g., transliteration).
+	TextService TextService
+	// LayoutService analyzes page layout, such as tables, using the page image and its OCR words.
+	LayoutService LayoutService
+	// QualityService scores how well images are suited for OCR, such as blur, exposure and skew.
+	QualityService QualityService
+	// SpellService corrects near-miss words recognized with low confidence, using word frequency lists.
+	SpellService SpellService
+	// ScanService stores OCR results so documents can reference them.
+	ScanService ScanService
+	// ScanJobService tracks the progress events of asynchronous scans.
+	ScanJobService ScanJobService
+	// WebhookService notifies users' webhook URLs when their scans finish.
+	WebhookService WebhookService
+	// DocumentService manages documents assembled from stored scans.
+	DocumentService DocumentService
+	// SearchService answers full-text queries over stored scans and documents.
+	SearchService SearchService
 	// GoodsService   GoodsService
 	// TransactionService TransactionService
 	// ProductionService ProductionService
@@ -35,14 +67,73 @@ func NewServices(dbClient *db.Client, config *config.AppConfig /*, add other glo
 	// Each specific service (e.g., UserService) would typically have its own
 	// constructor (e.g., NewUserService) that takes dependencies like the MongoDB client
 	// (or a specific collection from it).
-	userRepo := repository.NewMongoUserRepository(dbClient.Mongo.Database("niyamAPIDB")) // Use your actual DB name
+	database := dbClient.Mongo.Database("niyamAPIDB") // Use your actual DB name
+	userRepo := repository.NewMongoUserRepository(database)
+	dictRepo := repository.NewMongoDictionaryRepository(database)
+	scanRepo := repository.NewMongoScanRepository(database)
+	docRepo := repository.NewMongoDocumentRepository(database)
+	webhookRepo := repository.NewMongoWebhookRepository(database)
+	templateRepo := repository.NewMongoTemplateRepository(database)
+
+	// Full-text search index shared by the services that store text and the search service.
+	var searchIndex repository.SearchIndex
+	if config.SearchIndex == "memory" {
+		log.Println("INFO: Using the in-process search index; it starts empty after every restart.")
+		searchIndex = repository.NewMemorySearchIndex()
+	} else {
+		searchIndex = repository.NewMongoSearchIndex(database)
+	}
+
+	// Blob store for uploaded scan images. Without one, scans are still stored, just without images.
+	var blobStore repository.BlobStore
+	var err error
+	if config.BlobStore == "gridfs" {
+		blobStore, err = repository.NewGridFSBlobStore(database)
+	} else {
+		blobStore, err = repository.NewLocalBlobStore(config.BlobDir)
+	}
+	if err != nil {
+		log.Printf("WARNING: Scan images will not be stored: %v", err)
+		blobStore = nil
+	}
+
+	// Discover installed OCR languages once at startup; the OCR service loads models from the same directory.
+	languageService := NewLanguageService(config.TessdataDir)
+
+	// OCR engines enabled by configuration (and compiled into this binary).
+	ocrEngines := NewOCREngineRegistry(config, languageService.TessdataDir())
+
+	// OCR results are cached in process first, then in MongoDB so the cache survives restarts.
+	ocrService := NewOCRService(config, ocrEngines)
+	if config.OCRCacheTTL > 0 {
+		ocrService = NewCachedOCRService(ocrService, config.OCRCacheTTL, ocrEngines,
+			repository.NewMemoryOCRCache(config.OCRCacheEntries), repository.NewMongoOCRCache(database))
+	}
+
+	ocrPresetService := NewOCRPresetService(config.OCRPresetsFile)
+	textService := NewTextService()
 
 	return &Services{
 		// Example:
 		// Assuming you have a `user` package within `internal/service` or `internal/repository`
 		// and a `NewUserService` function that takes a mongo.Database or mongo.Collection.
-		UserService: NewUserService(userRepo, config.JWTSecret),
-		OCRService:  NewOCRService(), // Assuming you have an OCR service
+		UserService:        NewUserService(userRepo, config.JWTSecret),
+		OCRService:         ocrService,
+		EnsembleOCRService: NewEnsembleOCRService(ocrService),
+		OCREngines:         ocrEngines,
+		LanguageService:    languageService,
+		OCRPresetService:   ocrPresetService,
+		DictionaryService:  NewDictionaryService(dictRepo),
+		ExtractionService:  NewExtractionService(templateRepo, ocrService, ocrPresetService, languageService, textService),
+		TextService:        textService,
+		LayoutService:      NewLayoutService(),
+		QualityService:     NewQualityService(),
+		SpellService:       NewSpellService(config.SpellWordListDir),
+		ScanService:        NewScanService(scanRepo, searchIndex, blobStore),
+		ScanJobService:     NewScanJobService(),
+		WebhookService:     NewWebhookService(webhookRepo, config.WebhookAllowPrivateNetworks),
+		DocumentService:    NewDocumentService(docRepo, scanRepo, searchIndex),
+		SearchService:      NewSearchService(searchIndex),
 	}
 }
 
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"embed"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path to your module
+)
+
+// bundledWordLists holds the word frequency lists compiled into the binary, one "<language>.txt" each.
+//
+//go:embed wordfreq/*.txt
+var bundledWordLists embed.FS
+
+// spellTrustedConfidence is the word confidence from which a word is left as recognized.
+// Tesseract reads clean print at 80-95%; near misses usually score well below.
+const spellTrustedConfidence = 75
+
+// spellCustomLanguage is the language reported for corrections to words of the selected dictionary.
+const spellCustomLanguage = "custom"
+
+// spellCustomCount ranks words of the selected dictionary above any corpus word at the same distance.
+const spellCustomCount = 1 << 30
+
+// spellLanguage holds how words of a language are corrected.
+type spellLanguage struct {
+	maxDistance int         // Largest correction, in edits; a word of n runes gets at most (n-1)/2
+	suffixes    []string    // Postpositions written attached to words, longest first
+	confusions  [][2]string // OCR misreads (seen, meant) tried as a single edit
+}
+
+// spellLanguages configures the languages with bundled word lists. Lists for other languages (see
+// NewSpellService) use defaultSpellLanguage.
+var spellLanguages = map[string]spellLanguage{
+	types.LangEnglish: {
+		maxDistance: 2,
+		confusions:  [][2]string{{"rn", "m"}, {"cl", "d"}, {"vv", "w"}, {"1", "l"}, {"0", "o"}, {"5", "s"}},
+	},
+	// The bundled Nepali list is small, so corrections stay close to the recognized word.
+	types.LangNepali: {
+		maxDistance: 1,
+		suffixes: []string{"हरूबाट", "हरूलाई", "हरूको", "हरूका", "हरूकी", "हरूले", "हरूमा", "हरू",
+			"द्वारा", "भन्दा", "अनुसार", "बमोजिम", "सम्बन्धी", "देखि", "सम्म", "बाट", "लाई", "सँग", "तिर",
+			"को", "का", "की", "ले", "मा"},
+	},
+}
+
+// defaultSpellLanguage is used for word lists of languages without settings.
+var defaultSpellLanguage = spellLanguage{maxDistance: 2}
+
+// SpellService corrects near-miss words in OCR results with per-language word frequency lists.
+type SpellService interface {
+	// Languages lists the languages with a word list, sorted.
+	Languages() []string
+	// CorrectResult replaces the words of result recognized below the trusted confidence that are in none
+	// of the word lists of `language` (a Tesseract language string such as "eng+nep") by the most frequent
+	// known word within a few edits. The words of opts.UserWords count as known and are preferred as
+	// corrections. result is not modified, since it may be shared with the OCR cache; a corrected copy
+	// is returned with the corrections made, or result itself when nothing changed.
+	CorrectResult(result *types.OCRResult, language string, opts types.OCROptions) (*types.OCRResult, []types.SpellCorrection)
+}
+
+// spellService is the concrete implementation of SpellService. Word lists are loaded on first use.
+type spellService struct {
+	sources map[string]func() (io.ReadCloser, error) // Language -> opener of its word list
+
+	mu           sync.Mutex
+	dictionaries map[string]*utils.SpellDictionary // Loaded lists; nil for lists that failed to load
+}
+
+// NewSpellService creates a SpellService with the bundled English and Nepali word lists. When `dir` is
+// set, its "<language>.txt" files ("word count" lines) replace the bundled list of that language or
+// add a language.
+func NewSpellService(dir string) SpellService {
+	s := &spellService{sources: make(map[string]func() (io.ReadCloser, error)), dictionaries: make(map[string]*utils.SpellDictionary)}
+	bundled, _ := fs.Glob(bundledWordLists, "wordfreq/*.txt")
+	for _, path := range bundled {
+		s.sources[strings.TrimSuffix(filepath.Base(path), ".txt")] = func() (io.ReadCloser, error) { return bundledWordLists.Open(path) }
+	}
+
+	if dir != "" {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+		if err != nil || len(paths) == 0 {
+			log.Printf("WARNING: No word lists found in spell correction word list directory '%s'.", dir)
+		}
+		for _, path := range paths {
+			s.sources[strings.TrimSuffix(filepath.Base(path), ".txt")] = func() (io.ReadCloser, error) { return os.Open(path) }
+		}
+	}
+
+	log.Printf("INFO: Spell correction available for: %s", strings.Join(s.Languages(), ", "))
+	return s
+}
+
+// Languages lists the languages with a word list, sorted.
+func (s *spellService) Languages() []string {
+	languages := make([]string, 0, len(s.sources))
+	for language := range s.sources {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// dictionary returns the word list of a language, loading it on first use, or nil if there is none.
+func (s *spellService) dictionary(language string) *utils.SpellDictionary {
+	open, ok := s.sources[language]
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if dict, loaded := s.dictionaries[language]; loaded {
+		return dict
+	}
+
+	dict := utils.NewSpellDictionary(spellSettings(language).maxDistance)
+	err := func() error {
+		file, err := open()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return dict.Load(file)
+	}()
+	if err != nil {
+		log.Printf("WARNING: Spell correction for '%s' is disabled; its word list failed to load: %v", language, err)
+		dict = nil
+	} else {
+		log.Printf("INFO: Loaded %d word(s) for spell correction in '%s'.", dict.Len(), language)
+	}
+	s.dictionaries[language] = dict
+	return dict
+}
+
+// spellSettings returns the correction settings of a language.
+func spellSettings(language string) spellLanguage {
+	if settings, ok := spellLanguages[language]; ok {
+		return settings
+	}
+	return defaultSpellLanguage
+}
+
+// spellList is a word list with the settings it is used with.
+type spellList struct {
+	language string
+	dict     *utils.SpellDictionary
+	settings spellLanguage
+}
+
+// CorrectResult corrects the low-confidence words of an OCR result.
+func (s *spellService) CorrectResult(result *types.OCRResult, language string, opts types.OCROptions) (*types.OCRResult, []types.SpellCorrection) {
+	var lists []spellList
+	for _, code := range strings.Split(language, "+") {
+		if dict := s.dictionary(code); dict != nil {
+			lists = append(lists, spellList{language: code, dict: dict, settings: spellSettings(code)})
+		}
+	}
+	if len(opts.UserWords) > 0 {
+		custom := utils.NewSpellDictionary(defaultSpellLanguage.maxDistance)
+		for _, word := range opts.UserWords {
+			custom.Add(word, spellCustomCount)
+		}
+		lists = append(lists, spellList{language: spellCustomLanguage, dict: custom, settings: defaultSpellLanguage})
+	}
+	if len(lists) == 0 || len(result.Lines) == 0 {
+		return result, nil
+	}
+
+	corrected := *result
+	corrected.Lines = slices.Clone(result.Lines)
+	corrected.Words = slices.Clone(result.Words)
+	var corrections []types.SpellCorrection
+	cursors := make([]int, len(corrected.Lines)) // Byte offset in each line's text after the last word found
+	for i, word := range corrected.Words {
+		if word.Line < 0 || word.Line >= len(corrected.Lines) {
+			continue
+		}
+		// Words are in reading order, so each is searched for after the previous word of its line.
+		line := &corrected.Lines[word.Line]
+		at := strings.Index(line.Text[cursors[word.Line]:], word.Text)
+		if at < 0 {
+			continue
+		}
+		at += cursors[word.Line]
+		cursors[word.Line] = at + len(word.Text)
+		if word.Confidence >= spellTrustedConfidence {
+			continue
+		}
+
+		// Correct each word of the OCR word separately, keeping punctuation such as "(Governrnent),".
+		text := word.Text
+		tokens := utils.Tokenize(text)
+		for t := len(tokens) - 1; t >= 0; t-- {
+			original := text[tokens[t].Start:tokens[t].End]
+			replacement, source, distance, ok := correctSpelling(original, lists)
+			if !ok {
+				continue
+			}
+			text = text[:tokens[t].Start] + replacement + text[tokens[t].End:]
+			corrections = append(corrections, types.SpellCorrection{
+				Original: original, Corrected: replacement, Language: source,
+				Confidence: word.Confidence, Distance: distance, Box: word.Box,
+			})
+		}
+		if text == word.Text {
+			continue
+		}
+		line.Text = line.Text[:at] + text + line.Text[at+len(word.Text):]
+		cursors[word.Line] += len(text) - len(word.Text)
+		corrected.Words[i].Text = text
+	}
+	if len(corrections) == 0 {
+		return result, nil
+	}
+	corrected.Text = PostProcessText(ocrLinesText(corrected.Lines, nil), opts)
+	return &corrected, corrections
+}
+
+// correctSpelling returns the correction of a single word from the first list that knows it or the
+// closest, most frequent candidate across lists. Short words, numbers and likely acronyms are kept.
+func correctSpelling(word string, lists []spellList) (replacement, language string, distance int, ok bool) {
+	runes, digits, upper := 0, 0, 0
+	for _, r := range word {
+		runes++
+		if unicode.IsDigit(r) {
+			digits++
+		}
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	if runes < 3 || 2*digits > runes || (upper == runes && runes <= 4) {
+		return "", "", 0, false
+	}
+	for _, list := range lists {
+		if spellKnown(word, list) {
+			return "", "", 0, false
+		}
+	}
+
+	var best utils.SpellSuggestion
+	for _, list := range lists {
+		maxDistance := min(list.settings.maxDistance, (runes-1)/2)
+		// consider keeps a suggestion that is closer, or as close and more frequent, than the best so far.
+		consider := func(suggestion utils.SpellSuggestion, found bool, extra int, suffix string) {
+			suggestion.Distance += extra
+			if !found || suggestion.Distance > maxDistance {
+				return
+			}
+			if !ok || suggestion.Distance < best.Distance || (suggestion.Distance == best.Distance && suggestion.Count > best.Count) {
+				suggestion.Word += suffix
+				best, language, ok = suggestion, list.language, true
+			}
+		}
+
+		suggestion, found := list.dict.Lookup(word, maxDistance)
+		consider(suggestion, found, 0, "")
+		lower := strings.ToLower(word)
+		for _, confusion := range list.settings.confusions {
+			if variant := strings.ReplaceAll(lower, confusion[0], confusion[1]); variant != lower {
+				suggestion, found := list.dict.Lookup(variant, maxDistance-1)
+				consider(suggestion, found, 1, "")
+			}
+		}
+		for _, suffix := range list.settings.suffixes {
+			if stem, cut := strings.CutSuffix(word, suffix); cut && utf8.RuneCountInString(stem) >= 2 {
+				suggestion, found := list.dict.Lookup(stem, maxDistance)
+				consider(suggestion, found, 0, suffix)
+			}
+		}
+	}
+	if !ok {
+		return "", "", 0, false
+	}
+	return utils.MatchCase(best.Word, word), language, best.Distance, true
+}
+
+// spellKnown reports whether a word is in a list, directly or as a known stem with a postposition.
+func spellKnown(word string, list spellList) bool {
+	if list.dict.Contains(word) {
+		return true
+	}
+	for _, suffix := range list.settings.suffixes {
+		if stem, cut := strings.CutSuffix(word, suffix); cut && stem != "" && list.dict.Contains(stem) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2" // For Huma-specific error types
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/utils" // Adjust import path to your module
+)
+
+// TextService defines text-processing operations that work on already-extracted text.
+type TextService interface {
+	// Transliterate converts `text` between Devanagari and the romanization schemes in utils.
+	// An empty `from` means Devanagari. Unsupported scheme pairs yield a huma 400 error.
+	Transliterate(text, from, to string) (string, error)
+
+	// ExtractDates finds Bikram Sambat and Gregorian dates in `text` and returns each in both calendars.
+	// The result is never nil, so it serializes as an empty JSON array.
+	ExtractDates(text string) []types.DetectedDate
+
+	// ParseStructure segments the text of a legal document into chapters, sections, sub-sections and clauses.
+	ParseStructure(text string) types.DocumentStructure
+}
+
+// textService is the concrete, stateless implementation of TextService.
+type textService struct{}
+
+// NewTextService creates and returns a new instance of TextService.
+func NewTextService() TextService {
+	return &textService{}
+}
+
+// Transliterate converts `text` from one scheme to another.
+func (s *textService) Transliterate(text, from, to string) (string, error) {
+	from = strings.ToLower(strings.TrimSpace(from))
+	to = strings.ToLower(strings.TrimSpace(to))
+	if from == "" {
+		from = utils.SchemeDevanagari
+	}
+
+	result, err := utils.Transliterate(text, from, to)
+	if err != nil {
+		return "", huma.Error400BadRequest(fmt.Sprintf("Cannot transliterate: %v", err), nil)
+	}
+	return result, nil
+}
+
+// ExtractDates finds dates in `text` and converts them between calendars.
+func (s *textService) ExtractDates(text string) []types.DetectedDate {
+	matches := utils.ExtractDates(text)
+	dates := make([]types.DetectedDate, 0, len(matches))
+	for _, match := range matches {
+		date := types.DetectedDate{Text: match.Text, Offset: match.Start, Calendar: match.Calendar}
+		if match.BS.Year != 0 {
+			date.BS = match.BS.String()
+			date.BSFormatted = utils.ConvertDigits(fmt.Sprintf("%d %s %d", match.BS.Day, utils.BSMonthNames[match.BS.Month-1], match.BS.Year), utils.DigitsDevanagari)
+		}
+		if !match.AD.IsZero() {
+			date.AD = match.AD.Format(time.DateOnly)
+		}
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+// ParseStructure segments the text of a legal document into its hierarchy.
+func (s *textService) ParseStructure(text string) types.DocumentStructure {
+	root := utils.ParseLegalStructure(text)
+	structure := types.DocumentStructure{Title: root.Heading, Nodes: make([]types.DocumentNode, 0, len(root.Children))}
+	for _, child := range root.Children {
+		structure.Nodes = append(structure.Nodes, convertLegalNode(child, &structure.Sections))
+	}
+	return structure
+}
+
+// convertLegalNode converts a parsed node and its descendants into API types, counting sections.
+func convertLegalNode(node *utils.LegalNode, sections *int) types.DocumentNode {
+	if node.Kind == utils.LegalNodeSection {
+		*sections++
+	}
+	converted := types.DocumentNode{Kind: node.Kind, Number: node.Number, Heading: node.Heading, Text: node.Text}
+	for _, child := range node.Children {
+		converted.Children = append(converted.Children, convertLegalNode(child, sections))
+	}
+	return converted
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/axyut/niyamAPI/internal/repository" // Adjust import path to your module
+	"github.com/axyut/niyamAPI/internal/types"      // Adjust import path to your module
+)
+
+// errPrivateAddress is returned when a webhook URL resolves to an address that may not be called.
+var errPrivateAddress = errors.New("webhook address is not a public address")
+
+// Headers sent with every webhook request.
+const (
+	WebhookEventHeader     = "X-Niyam-Event"
+	WebhookDeliveryHeader  = "X-Niyam-Delivery"
+	WebhookTimestampHeader = "X-Niyam-Timestamp"
+	WebhookSignatureHeader = "X-Niyam-Signature"
+)
+
+// Delivery schedule: the first retry comes webhookInitialBackoff after a failure, and the wait doubles
+// after every further failure (2s, 4s, 8s, 16s, 32s).
+const (
+	webhookMaxAttempts    = 6
+	webhookInitialBackoff = 2 * time.Second
+	webhookAttemptTimeout = 10 * time.Second
+	webhookRepoTimeout    = 10 * time.Second // For log writes made outside any request
+)
+
+// SignWebhookPayload computes the X-Niyam-Signature header value: "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret. Receivers should recompute it,
+// compare in constant time and reject stale timestamps.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookService manages webhook registrations and delivers signed scan notifications.
+type WebhookService interface {
+	// CreateWebhook registers a webhook for the user, generating a signing secret unless one is given.
+	CreateWebhook(ctx context.Context, user *types.AuthClaims, body types.WebhookBody) (*types.CreateWebhookOutput, error)
+	// ListWebhooks returns the user's webhooks.
+	ListWebhooks(ctx context.Context, user *types.AuthClaims) (*types.WebhooksOutput, error)
+	// DeleteWebhook removes one of the user's webhooks.
+	DeleteWebhook(ctx context.Context, user *types.AuthClaims, id string) error
+
+	// ListDeliveries returns the user's delivery log, optionally for one scan.
+	ListDeliveries(ctx context.Context, user *types.AuthClaims, scanID string, limit int) (*types.WebhookDeliveriesOutput, error)
+	// GetDelivery returns one entry of the user's delivery log.
+	GetDelivery(ctx context.Context, user *types.AuthClaims, id string) (*types.WebhookDeliveryOutput, error)
+	// ReplayDelivery sends a logged delivery again (same payload and delivery ID, new signature) with fresh retries.
+	ReplayDelivery(ctx context.Context, user *types.AuthClaims, id string) (*types.WebhookDeliveryOutput, error)
+
+	// ValidateURL checks a webhook URL (e.g., a per-scan one) before it is used; errors are 400s.
+	ValidateURL(rawURL string) error
+	// NotifyScan sends `payload` to the owner's webhooks subscribed to its event and, if set, to the
+	// per-scan `scanURL` signed with `scanSecret`. Deliveries run in the background.
+	NotifyScan(ctx context.Context, ownerID, scanURL, scanSecret string, payload types.WebhookPayload)
+}
+
+// webhookService is the concrete implementation of WebhookService.
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	client      *http.Client
+	backoff     time.Duration
+}
+
+// NewWebhookService creates and returns a new instance of WebhookService. Unless `allowPrivateNetworks`
+// is set, webhook requests may only connect to public IP addresses, so users cannot make the server
+// call internal services.
+func NewWebhookService(webhookRepo repository.WebhookRepository, allowPrivateNetworks bool) WebhookService {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivateNetworks {
+		// Checked on the resolved address at connect time, so DNS tricks cannot bypass it.
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+				return fmt.Errorf("%w: %s", errPrivateAddress, host)
+			}
+			return nil
+		}
+	}
+
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		client: &http.Client{
+			Timeout:   webhookAttemptTimeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+			// Redirects are not followed; a 3xx counts as a failed attempt.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		backoff: webhookInitialBackoff,
+	}
+}
+
+// CreateWebhook validates and stores a new webhook.
+func (s *webhookService) CreateWebhook(ctx context.Context, user *types.AuthClaims, body types.WebhookBody) (*types.CreateWebhookOutput, error) {
+	if err := s.ValidateURL(body.URL); err != nil {
+		return nil, err
+	}
+	events := body.Events
+	if len(events) == 0 {
+		events = []string{types.WebhookEventScanCompleted, types.WebhookEventScanFailed}
+	}
+	secret := body.Secret
+	if secret == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Printf("ERROR: Failed to generate webhook secret: %v", err)
+			return nil, fmt.Errorf("failed to create webhook")
+		}
+		secret = "whsec_" + hex.EncodeToString(key)
+	}
+
+	hook, err := s.webhookRepo.CreateWebhook(ctx, &types.Webhook{
+		OwnerID:   user.UserID,
+		URL:       body.URL,
+		Events:    events,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("ERROR: Service failed to create webhook for user %s: %v", user.UserID, err)
+		return nil, fmt.Errorf("failed to create webhook")
+	}
+
+	resp := &types.CreateWebhookOutput{}
+	resp.Body.Webhook = *hook
+	resp.Body.Secret = hook.Secret
+	return resp, nil
+}
+
+// ListWebhooks returns the user's webhooks.
+func (s *webhookService) ListWebhooks(ctx context.Context, user *types.AuthClaims) (*types.WebhooksOutput, error) {
+	hooks, err := s.webhookRepo.ListWebhooks(ctx, user.UserID)
+	if err != nil {
+		log.Printf("ERROR: Service failed to list webhooks for user %s: %v", user.UserID, err)
+		return nil, fmt.Errorf("failed to list webhooks")
+	}
+	resp := &types.WebhooksOutput{}
+	resp.Body.Webhooks = hooks
+	return resp, nil
+}
+
+// DeleteWebhook removes a webhook owned by the user (or any webhook, for admins).
+func (s *webhookService) DeleteWebhook(ctx context.Context, user *types.AuthClaims, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return huma.Error400BadRequest("invalid webhook ID format", nil)
+	}
+	hook, err := s.webhookRepo.GetWebhookByID(ctx, objID)
+	if err == nil && hook.OwnerID != user.UserID && user.Role != "admin" {
+		err = fmt.Errorf("webhook not found")
+	}
+	if err == nil {
+		err = s.webhookRepo.DeleteWebhook(ctx, objID)
+	}
+	if err != nil {
+		if err.Error() == "webhook not found" {
+			return huma.Error404NotFound("webhook not found", nil)
+		}
+		log.Printf("ERROR: Service failed to delete webhook %s: %v", id, err)
+		return fmt.Errorf("failed to delete webhook")
+	}
+	return nil
+}
+
+// ListDeliveries returns the user's delivery log.
+func (s *webhookService) ListDeliveries(ctx context.Context, user *types.AuthClaims, scanID string, limit int) (*types.WebhookDeliveriesOutput, error) {
+	deliveries, err := s.webhookRepo.ListDeliveries(ctx, user.UserID, scanID, limit)
+	if err != nil {
+		log.Printf("ERROR: Service failed to list webhook deliveries for user %s: %v", user.UserID, err)
+		return nil, fmt.Errorf("failed to list webhook deliveries")
+	}
+	resp := &types.WebhookDeliveriesOutput{}
+	resp.Body.Deliveries = deliveries
+	return resp, nil
+}
+
+// GetDelivery returns a delivery owned by the user (or any delivery, for admins).
+func (s *webhookService) GetDelivery(ctx context.Context, user *types.AuthClaims, id string) (*types.WebhookDeliveryOutput, error) {
+	delivery, err := s.getDelivery(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	return &types.WebhookDeliveryOutput{Body: *delivery}, nil
+}
+
+// ReplayDelivery restarts a delivery in the background. The returned entry shows it as pending.
+// A pending delivery can only be replayed once it was abandoned (see abandonedAfter).
+func (s *webhookService) ReplayDelivery(ctx context.Context, user *types.AuthClaims, id string) (*types.WebhookDeliveryOutput, error) {
+	delivery, err := s.getDelivery(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ValidateURL(delivery.URL); err != nil {
+		return nil, err
+	}
+	restarted, err := s.webhookRepo.RestartDelivery(ctx, delivery.ID, time.Now().Add(-s.abandonedAfter()))
+	if err != nil {
+		log.Printf("ERROR: Service failed to restart webhook delivery %s: %v", id, err)
+		return nil, fmt.Errorf("failed to replay webhook delivery")
+	}
+	if !restarted {
+		return nil, huma.Error409Conflict("delivery is still being attempted", nil)
+	}
+
+	log.Printf("INFO: Replaying webhook delivery %s to %s", id, delivery.URL)
+	delivery.Status = types.WebhookDeliveryPending
+	go s.deliver(*delivery)
+	return &types.WebhookDeliveryOutput{Body: *delivery}, nil
+}
+
+// abandonedAfter is how long a pending delivery goes without a log write before it counts as abandoned,
+// e.g. because the server restarted mid-retry: longer than the longest wait between two log writes of
+// a running delivery (the last backoff, an attempt and the write itself).
+func (s *webhookService) abandonedAfter() time.Duration {
+	return s.backoff<<(webhookMaxAttempts-2) + webhookAttemptTimeout + webhookRepoTimeout + time.Minute
+}
+
+// getDelivery loads a delivery the user may see; others' deliveries are reported as not found.
+func (s *webhookService) getDelivery(ctx context.Context, user *types.AuthClaims, id string) (*types.WebhookDelivery, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid delivery ID format", nil)
+	}
+	delivery, err := s.webhookRepo.GetDeliveryByID(ctx, objID)
+	if err != nil {
+		if err.Error() == "webhook delivery not found" {
+			return nil, huma.Error404NotFound("webhook delivery not found", nil)
+		}
+		log.Printf("ERROR: Service failed to get webhook delivery %s: %v", id, err)
+		return nil, fmt.Errorf("failed to retrieve webhook delivery")
+	}
+	if delivery.OwnerID != user.UserID && user.Role != "admin" {
+		return nil, huma.Error404NotFound("webhook delivery not found", nil)
+	}
+	return delivery, nil
+}
+
+// ValidateURL accepts absolute http(s) URLs.
+func (s *webhookService) ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return huma.Error400BadRequest(fmt.Sprintf("invalid webhook URL '%s': an absolute http or https URL is required", rawURL), nil)
+	}
+	return nil
+}
+
+// NotifyScan logs one delivery per target and starts delivering them.
+func (s *webhookService) NotifyScan(ctx context.Context, ownerID, scanURL, scanSecret string, payload types.WebhookPayload) {
+	var targets []types.WebhookDelivery
+	if ownerID != "" {
+		hooks, err := s.webhookRepo.ListWebhooks(ctx, ownerID)
+		if err != nil {
+			log.Printf("ERROR: Failed to load webhooks of user %s: %v", ownerID, err)
+		}
+		for _, hook := range hooks {
+			if slices.Contains(hook.Events, payload.Event) {
+				targets = append(targets, types.WebhookDelivery{WebhookID: hook.ID.Hex(), URL: hook.URL, Secret: hook.Secret})
+			}
+		}
+	}
+	if scanURL != "" {
+		targets = append(targets, types.WebhookDelivery{URL: scanURL, Secret: scanSecret})
+	}
+
+	for _, delivery := range targets {
+		delivery.ID = primitive.NewObjectID()
+		payload.DeliveryID = delivery.ID.Hex()
+		body, err := json.Marshal(payload)
+		if err != nil {
+			log.Printf("ERROR: Failed to encode webhook payload for scan %s: %v", payload.ScanID, err)
+			continue
+		}
+
+		now := time.Now()
+		delivery.OwnerID = ownerID
+		delivery.Event = payload.Event
+		delivery.ScanID = payload.ScanID
+		delivery.Payload = string(body)
+		delivery.Status = types.WebhookDeliveryPending
+		delivery.Attempts = []types.WebhookAttempt{}
+		delivery.CreatedAt, delivery.UpdatedAt = now, now
+		if _, err := s.webhookRepo.CreateDelivery(ctx, &delivery); err != nil {
+			log.Printf("ERROR: Failed to log webhook delivery for scan %s: %v", payload.ScanID, err)
+			continue
+		}
+		go s.deliver(delivery)
+	}
+}
+
+// deliver attempts a delivery until it succeeds, fails permanently or runs out of attempts,
+// recording every attempt in the delivery log.
+func (s *webhookService) deliver(delivery types.WebhookDelivery) {
+	backoff := s.backoff
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		result, retry := s.attempt(delivery)
+
+		status := types.WebhookDeliveryPending
+		switch {
+		case result.Error == "":
+			status = types.WebhookDeliverySucceeded
+		case !retry || attempt == webhookMaxAttempts:
+			status = types.WebhookDeliveryFailed
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), webhookRepoTimeout)
+		if err := s.webhookRepo.AddDeliveryAttempt(ctx, delivery.ID, result, status); err != nil {
+			log.Printf("ERROR: Failed to log attempt %d of webhook delivery %s: %v", attempt, delivery.ID.Hex(), err)
+		}
+		cancel()
+
+		if status != types.WebhookDeliveryPending {
+			log.Printf("INFO: Webhook delivery %s to %s %s after %d attempt(s)", delivery.ID.Hex(), delivery.URL, status, attempt)
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// attempt makes one signed POST request. `retry` reports whether a failure may be temporary
+// (network errors, 408, 429 and 5xx responses).
+func (s *webhookService) attempt(delivery types.WebhookDelivery) (result types.WebhookAttempt, retry bool) {
+	start := time.Now()
+	result.At = start
+	defer func() { result.DurationMS = time.Since(start).Milliseconds() }()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid request: %v", err)
+		return result, false
+	}
+	timestamp := start.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "niyamAPI-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(delivery.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result, !errors.Is(err, errPrivateAddress)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // Drain a little so the connection can be reused.
+
+	result.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return result, false
+	}
+	result.Error = fmt.Sprintf("receiver answered %s", resp.Status)
+	return result, resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/axyut/niyamAPI/internal/types" // Adjust import path to your module
+)
+
+// memoryWebhookRepository is an in-memory WebhookRepository. It signals on `finished` whenever a
+// delivery reaches a final status.
+type memoryWebhookRepository struct {
+	mu         sync.Mutex
+	hooks      []types.Webhook
+	deliveries map[primitive.ObjectID]*types.WebhookDelivery
+	finished   chan primitive.ObjectID
+}
+
+func newMemoryWebhookRepository() *memoryWebhookRepository {
+	return &memoryWebhookRepository{deliveries: make(map[primitive.ObjectID]*types.WebhookDelivery), finished: make(chan primitive.ObjectID, 16)}
+}
+
+func (r *memoryWebhookRepository) CreateWebhook(ctx context.Context, hook *types.Webhook) (*types.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hook.ID = primitive.NewObjectID()
+	r.hooks = append(r.hooks, *hook)
+	return hook, nil
+}
+
+func (r *memoryWebhookRepository) GetWebhookByID(ctx context.Context, id primitive.ObjectID) (*types.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, hook := range r.hooks {
+		if hook.ID == id {
+			return &hook, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook not found")
+}
+
+func (r *memoryWebhookRepository) ListWebhooks(ctx context.Context, ownerID string) ([]types.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var hooks []types.Webhook
+	for _, hook := range r.hooks {
+		if hook.OwnerID == ownerID {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (r *memoryWebhookRepository) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	return errors.New("not implemented")
+}
+
+func (r *memoryWebhookRepository) CreateDelivery(ctx context.Context, delivery *types.WebhookDelivery) (*types.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *delivery
+	r.deliveries[delivery.ID] = &stored
+	return delivery, nil
+}
+
+func (r *memoryWebhookRepository) GetDeliveryByID(ctx context.Context, id primitive.ObjectID) (*types.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, fmt.Errorf("webhook delivery not found")
+	}
+	copied := *delivery
+	copied.Attempts = append([]types.WebhookAttempt(nil), delivery.Attempts...)
+	return &copied, nil
+}
+
+func (r *memoryWebhookRepository) ListDeliveries(ctx context.Context, ownerID, scanID string, limit int) ([]types.WebhookDelivery, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *memoryWebhookRepository) AddDeliveryAttempt(ctx context.Context, id primitive.ObjectID, attempt types.WebhookAttempt, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return fmt.Errorf("webhook delivery not found")
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.Status, delivery.UpdatedAt = status, time.Now()
+	if status != types.WebhookDeliveryPending {
+		r.finished <- id
+	}
+	return nil
+}
+
+func (r *memoryWebhookRepository) RestartDelivery(ctx context.Context, id primitive.ObjectID, staleBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery, ok := r.deliveries[id]
+	if !ok || (delivery.Status == types.WebhookDeliveryPending && !delivery.UpdatedAt.Before(staleBefore)) {
+		return false, nil
+	}
+	delivery.Status, delivery.UpdatedAt = types.WebhookDeliveryPending, time.Now()
+	return true, nil
+}
+
+// webhookRequest is a request seen by the test receiver.
+type webhookRequest struct {
+	at     time.Time
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver is an httptest server answering webhook requests with scripted status codes
+// (200 once the script runs out) and recording them.
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []webhookRequest
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) *webhookReceiver {
+	t.Helper()
+	receiver := &webhookReceiver{statuses: statuses}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		receiver.requests = append(receiver.requests, webhookRequest{at: time.Now(), header: r.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(receiver.statuses) > 0 {
+			status, receiver.statuses = receiver.statuses[0], receiver.statuses[1:]
+		}
+		receiver.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (r *webhookReceiver) received() []webhookRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]webhookRequest(nil), r.requests...)
+}
+
+// testWebhookBackoff replaces the 2s initial backoff so retries run quickly.
+const testWebhookBackoff = 20 * time.Millisecond
+
+// newTestWebhookService returns a webhook service that may call the loopback receiver.
+func newTestWebhookService(repo *memoryWebhookRepository) *webhookService {
+	svc := NewWebhookService(repo, true).(*webhookService)
+	svc.backoff = testWebhookBackoff
+	return svc
+}
+
+// notifyAndWait sends a scan notification to a per-scan webhook and waits for its delivery to finish.
+func notifyAndWait(t *testing.T, svc *webhookService, repo *memoryWebhookRepository, url string) *types.WebhookDelivery {
+	t.Helper()
+	payload := types.WebhookPayload{Event: types.WebhookEventScanCompleted, ScanID: "scan-1", OccurredAt: time.Now()}
+	svc.NotifyScan(context.Background(), "alice", url, "test-secret-0123456789", payload)
+	return waitForDelivery(t, repo)
+}
+
+// waitForDelivery waits for the next delivery to reach a final status and returns it.
+func waitForDelivery(t *testing.T, repo *memoryWebhookRepository) *types.WebhookDelivery {
+	t.Helper()
+	select {
+	case id := <-repo.finished:
+		delivery, err := repo.GetDeliveryByID(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return delivery
+	case <-time.After(10 * time.Second):
+		t.Fatal("webhook delivery did not finish")
+		return nil
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	repo := newMemoryWebhookRepository()
+	receiver := newWebhookReceiver(t)
+	delivery := notifyAndWait(t, newTestWebhookService(repo), repo, receiver.URL)
+
+	requests := receiver.received()
+	if delivery.Status != types.WebhookDeliverySucceeded || len(requests) != 1 {
+		t.Fatalf("delivery %s after %d request(s), want succeeded after 1", delivery.Status, len(requests))
+	}
+	req := requests[0]
+	timestamp, err := strconv.ParseInt(req.header.Get(WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid %s header: %v", WebhookTimestampHeader, err)
+	}
+	if got, want := req.header.Get(WebhookSignatureHeader), SignWebhookPayload("test-secret-0123456789", timestamp, req.body); got != want {
+		t.Errorf("%s = %q, want %q", WebhookSignatureHeader, got, want)
+	}
+	if got := req.header.Get(WebhookDeliveryHeader); got != delivery.ID.Hex() {
+		t.Errorf("%s = %q, want %q", WebhookDeliveryHeader, got, delivery.ID.Hex())
+	}
+	if got := req.header.Get(WebhookEventHeader); got != types.WebhookEventScanCompleted {
+		t.Errorf("%s = %q, want %q", WebhookEventHeader, got, types.WebhookEventScanCompleted)
+	}
+	if string(req.body) != delivery.Payload {
+		t.Errorf("body = %s, want the logged payload %s", req.body, delivery.Payload)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		want     string
+		attempts int
+	}{
+		{"5xx and 429 are retried", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusInternalServerError}, types.WebhookDeliverySucceeded, 4},
+		{"4xx is not retried", []int{http.StatusBadRequest}, types.WebhookDeliveryFailed, 1},
+		{"gives up after the last attempt", []int{500, 500, 500, 500, 500, 500, 500}, types.WebhookDeliveryFailed, webhookMaxAttempts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryWebhookRepository()
+			receiver := newWebhookReceiver(t, tt.statuses...)
+			delivery := notifyAndWait(t, newTestWebhookService(repo), repo, receiver.URL)
+
+			if delivery.Status != tt.want || len(delivery.Attempts) != tt.attempts {
+				t.Fatalf("delivery %s after %d logged attempt(s), want %s after %d", delivery.Status, len(delivery.Attempts), tt.want, tt.attempts)
+			}
+			requests := receiver.received()
+			if len(requests) != tt.attempts {
+				t.Fatalf("receiver saw %d request(s), want %d", len(requests), tt.attempts)
+			}
+			for i, attempt := range delivery.Attempts {
+				want := http.StatusOK
+				if i < len(tt.statuses) {
+					want = tt.statuses[i]
+				}
+				if attempt.StatusCode != want || (want == http.StatusOK) != (attempt.Error == "") {
+					t.Errorf("attempt %d logged as %d %q, want status %d", i+1, attempt.StatusCode, attempt.Error, want)
+				}
+			}
+			// The wait before retry n is testWebhookBackoff * 2^(n-1).
+			for i := 1; i < len(requests); i++ {
+				if gap, wait := requests[i].at.Sub(requests[i-1].at), testWebhookBackoff<<(i-1); gap < wait {
+					t.Errorf("retry %d came after %v, want at least %v", i, gap, wait)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookReplay(t *testing.T) {
+	repo := newMemoryWebhookRepository()
+	receiver := newWebhookReceiver(t, http.StatusNotFound)
+	svc := newTestWebhookService(repo)
+	delivery := notifyAndWait(t, svc, repo, receiver.URL)
+	if delivery.Status != types.WebhookDeliveryFailed {
+		t.Fatalf("first delivery %s, want failed", delivery.Status)
+	}
+
+	alice := &types.AuthClaims{UserID: "alice", Role: "user"}
+	if _, err := svc.ReplayDelivery(context.Background(), &types.AuthClaims{UserID: "bob"}, delivery.ID.Hex()); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("replay of another user's delivery: got %v, want 404", err)
+	}
+	out, err := svc.ReplayDelivery(context.Background(), alice, delivery.ID.Hex())
+	if err != nil {
+		t.Fatalf("ReplayDelivery: %v", err)
+	}
+	if out.Body.Status != types.WebhookDeliveryPending {
+		t.Errorf("replayed delivery reported as %s, want pending", out.Body.Status)
+	}
+	replayed := waitForDelivery(t, repo)
+
+	requests := receiver.received()
+	if replayed.Status != types.WebhookDeliverySucceeded || len(replayed.Attempts) != 2 || len(requests) != 2 {
+		t.Fatalf("replayed delivery %s with %d logged attempt(s) and %d request(s), want succeeded with 2 and 2",
+			replayed.Status, len(replayed.Attempts), len(requests))
+	}
+	if first, second := requests[0].header.Get(WebhookDeliveryHeader), requests[1].header.Get(WebhookDeliveryHeader); first != second || second != delivery.ID.Hex() {
+		t.Errorf("delivery IDs %q and %q, want %q for both", first, second, delivery.ID.Hex())
+	}
+	if string(requests[0].body) != string(requests[1].body) {
+		t.Errorf("replayed body %s differs from %s", requests[1].body, requests[0].body)
+	}
+}
+
+func TestWebhookReplayPending(t *testing.T) {
+	repo := newMemoryWebhookRepository()
+	receiver := newWebhookReceiver(t)
+	svc := newTestWebhookService(repo)
+	alice := &types.AuthClaims{UserID: "alice", Role: "user"}
+
+	// A delivery that is still being attempted cannot be replayed...
+	now := time.Now()
+	delivery := &types.WebhookDelivery{
+		ID: primitive.NewObjectID(), OwnerID: "alice", URL: receiver.URL, Secret: "test-secret-0123456789",
+		Event: types.WebhookEventScanCompleted, Payload: `{}`, Status: types.WebhookDeliveryPending, CreatedAt: now, UpdatedAt: now,
+	}
+	repo.CreateDelivery(context.Background(), delivery)
+	if _, err := svc.ReplayDelivery(context.Background(), alice, delivery.ID.Hex()); !isStatus(err, http.StatusConflict) {
+		t.Fatalf("replay of a running delivery: got %v, want 409", err)
+	}
+
+	// ...but one abandoned mid-retry (e.g., by a restart) can.
+	repo.mu.Lock()
+	repo.deliveries[delivery.ID].UpdatedAt = now.Add(-svc.abandonedAfter() - time.Second)
+	repo.mu.Unlock()
+	if _, err := svc.ReplayDelivery(context.Background(), alice, delivery.ID.Hex()); err != nil {
+		t.Fatalf("replay of an abandoned delivery: %v", err)
+	}
+	if replayed := waitForDelivery(t, repo); replayed.Status != types.WebhookDeliverySucceeded {
+		t.Errorf("replayed delivery %s, want succeeded", replayed.Status)
+	}
+}
+
+// isStatus reports whether err is a Huma error with the given HTTP status.
+func isStatus(err error, status int) bool {
+	var statusErr huma.StatusError
+	return errors.As(err, &statusErr) && statusErr.GetStatus() == status
+}
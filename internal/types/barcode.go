@@ -0,0 +1,20 @@
+package types
+
+// Formats of DetectedBarcode.
+const (
+	BarcodeFormatQRCode     = "qr_code"
+	BarcodeFormatCode128    = "code_128"
+	BarcodeFormatEAN13      = "ean_13"
+	BarcodeFormatDataMatrix = "data_matrix"
+	BarcodeFormatPDF417     = "pdf_417"
+)
+
+// DetectedBarcode is a barcode or QR code decoded from a scanned page.
+type DetectedBarcode struct {
+	Page   int    `json:"page,omitempty" doc:"Page of a multi-page scan the barcode is on"`
+	Format string `json:"format" enum:"qr_code,code_128,ean_13,data_matrix,pdf_417" example:"qr_code"`
+	Text   string `json:"text" example:"https://verify.example.gov.np/c/27-01-75-01234" doc:"Decoded payload"`
+	// URL repeats Text when the payload is an http(s) URL, such as the verification link of a certificate.
+	URL string `json:"url,omitempty" example:"https://verify.example.gov.np/c/27-01-75-01234" doc:"The payload, if it is an http(s) URL"`
+	Box OCRBox `json:"box" doc:"Approximate position in page image pixels (for QR codes, the area between the finder pattern centers)"`
+}
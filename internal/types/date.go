@@ -0,0 +1,11 @@
+package types
+
+// DetectedDate is a date found in extracted text, given in both the Bikram Sambat and Gregorian calendars.
+type DetectedDate struct {
+	Text        string `json:"text" example:"२०८१/०३/१५" doc:"Date as written in the text"`
+	Offset      int    `json:"offset" example:"12" doc:"Byte offset of the date in the text"`
+	Calendar    string `json:"calendar" enum:"BS,AD" example:"BS" doc:"Calendar the date is written in"`
+	BS          string `json:"bs,omitempty" example:"2081-03-15" doc:"Bikram Sambat date (YYYY-MM-DD); omitted outside the supported range"`
+	BSFormatted string `json:"bsFormatted,omitempty" example:"१५ असार २०८१" doc:"Bikram Sambat date with the Nepali month name"`
+	AD          string `json:"ad,omitempty" example:"2024-06-28" doc:"Gregorian date (YYYY-MM-DD); omitted outside the supported range"`
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive" // For MongoDB's ObjectID
+)
+
+// OCRDictionary is a per-domain custom vocabulary (user words and patterns) applied to Tesseract
+// when a scan selects its domain, e.g., "legal-np" for Nepali acts and regulations.
+type OCRDictionary struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id" huma:"example:654a93c7e0f2f3f4c5d6e7f8"`
+	Domain      string             `bson:"domain" json:"domain" example:"legal-np" doc:"Unique dictionary name, as accepted by the 'dictionary' field of /scan"`
+	Description string             `bson:"description" json:"description" example:"Nepali legal terms and section markers"`
+	Language    string             `bson:"language" json:"language" example:"nep" doc:"Language the vocabulary is intended for (informational)"`
+	Words       []string           `bson:"words" json:"words" doc:"User words, one entry per word"`
+	Patterns    []string           `bson:"patterns" json:"patterns" doc:"Tesseract user patterns"`
+	CreatedBy   string             `bson:"created_by" json:"createdBy" doc:"ID of the admin who created the dictionary"`
+	CreatedAt   time.Time          `bson:"created_at" json:"createdAt" huma:"example:2024-01-01T12:00:00Z"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updatedAt" huma:"example:2024-01-01T12:00:00Z"`
+}
+
+// OCRDictionarySummary describes a dictionary without its (potentially large) word lists.
+type OCRDictionarySummary struct {
+	Domain       string    `json:"domain" example:"legal-np"`
+	Description  string    `json:"description" example:"Nepali legal terms and section markers"`
+	Language     string    `json:"language" example:"nep"`
+	WordCount    int       `json:"wordCount" example:"1250"`
+	PatternCount int       `json:"patternCount" example:"12"`
+	UpdatedAt    time.Time `json:"updatedAt" huma:"example:2024-01-01T12:00:00Z"`
+}
+
+// OCRDictionaryBody is the request body used to create or replace a dictionary.
+type OCRDictionaryBody struct {
+	Domain      string   `json:"domain" pattern:"^[a-z0-9][a-z0-9_-]{1,63}$" example:"legal-np" doc:"Unique dictionary name (lowercase letters, digits, '-' and '_')"`
+	Description string   `json:"description,omitempty" maxLength:"500" example:"Nepali legal terms and section markers"`
+	Language    string   `json:"language,omitempty" example:"nep" doc:"Language the vocabulary is intended for (informational)"`
+	Words       []string `json:"words,omitempty" example:"[\"ऐन\",\"नियमावली\",\"परिच्छेद\",\"दफा\"]" doc:"User words, one entry per word"`
+	Patterns    []string `json:"patterns,omitempty" doc:"Tesseract user patterns (e.g., 'दफा \\d')"`
+}
+
+// CreateOCRDictionaryInput is the input structure for creating a dictionary.
+type CreateOCRDictionaryInput struct {
+	Body OCRDictionaryBody
+}
+
+// UpdateOCRDictionaryInput is the input structure for replacing a dictionary's contents.
+type UpdateOCRDictionaryInput struct {
+	Domain string `path:"domain" example:"legal-np" doc:"Dictionary domain"`
+	Body   OCRDictionaryBody
+}
+
+// OCRDictionaryDomainInput is the input structure for endpoints addressing a single dictionary.
+type OCRDictionaryDomainInput struct {
+	Domain string `path:"domain" example:"legal-np" doc:"Dictionary domain"`
+}
+
+// OCRDictionaryOutput is the output structure for returning a full dictionary.
+type OCRDictionaryOutput struct {
+	Body OCRDictionary
+}
+
+// OCRDictionariesOutput is the output structure for listing dictionaries.
+type OCRDictionariesOutput struct {
+	Body struct {
+		Dictionaries []OCRDictionarySummary `json:"dictionaries" doc:"Available dictionaries, sorted by domain"`
+	}
+}
@@ -0,0 +1,102 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive" // For MongoDB's ObjectID
+)
+
+// Document is a legal document assembled from stored scans, one scan per page.
+// Every change to the page list increments Version and appends a DocumentVersion.
+type Document struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" huma:"example:654a93c7e0f2f3f4c5d6e7f8"`
+	Title     string             `bson:"title" json:"title" example:"मुलुकी अपराध संहिता, २०७४"`
+	Type      string             `bson:"type" json:"type" example:"act"`
+	Language  string             `bson:"language" json:"language" example:"nep"`
+	Tags      []string           `bson:"tags" json:"tags" example:"[\"criminal\"]"`
+	OwnerID   string             `bson:"owner_id" json:"ownerId" example:"654a93c7e0f2f3f4c5d6e7f8"`
+	Pages     []DocumentPage     `bson:"pages" json:"pages" doc:"Pages in reading order"`
+	Version   int                `bson:"version" json:"version" example:"3" doc:"Current version; increases with every page change"`
+	Versions  []DocumentVersion  `bson:"versions" json:"versions" doc:"History of page changes, oldest first"`
+	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updatedAt"`
+}
+
+// DocumentPage is one page of a document, referencing the scan it was read from.
+type DocumentPage struct {
+	ScanID  string    `bson:"scan_id" json:"scanId" example:"654a93c7e0f2f3f4c5d6e7f8"`
+	AddedAt time.Time `bson:"added_at" json:"addedAt"`
+}
+
+// DocumentVersion records the page order of a document after one change.
+type DocumentVersion struct {
+	Version   int       `bson:"version" json:"version" example:"2"`
+	ScanIDs   []string  `bson:"scan_ids" json:"scanIds" doc:"Page scan IDs in reading order at this version"`
+	Change    string    `bson:"change" json:"change" example:"added 2 page(s)"`
+	ChangedBy string    `bson:"changed_by" json:"changedBy" example:"654a93c7e0f2f3f4c5d6e7f8"`
+	ChangedAt time.Time `bson:"changed_at" json:"changedAt"`
+}
+
+// DocumentBody holds the user-editable metadata of a document.
+type DocumentBody struct {
+	Title    string   `json:"title" minLength:"1" maxLength:"300" example:"मुलुकी अपराध संहिता, २०७४"`
+	Type     string   `json:"type,omitempty" enum:"act,rules,regulation,directive,order,notice,judgment,other" default:"other" doc:"Kind of legal instrument"`
+	Language string   `json:"language,omitempty" maxLength:"64" example:"nep" doc:"Tesseract language code(s) of the document text"`
+	Tags     []string `json:"tags,omitempty" maxItems:"32" doc:"Free-form labels"`
+	ScanIDs  []string `json:"scanIds,omitempty" maxItems:"1000" doc:"Optional initial pages, as scan IDs in reading order"`
+}
+
+// CreateDocumentInput is the input structure for creating a document.
+type CreateDocumentInput struct {
+	Body DocumentBody
+}
+
+// DocumentIDInput identifies a document by the ID in the path.
+type DocumentIDInput struct {
+	ID string `path:"id" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"Document ID"`
+}
+
+// AddDocumentPagesInput is the input structure for attaching scans to a document.
+type AddDocumentPagesInput struct {
+	ID   string `path:"id" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"Document ID"`
+	Body struct {
+		ScanIDs  []string `json:"scanIds" minItems:"1" maxItems:"1000" doc:"Scan IDs to attach, in reading order"`
+		Position int      `json:"position,omitempty" minimum:"0" doc:"1-based page number to insert before; 0 or omitted appends at the end"`
+	}
+}
+
+// ReorderDocumentPagesInput is the input structure for reordering the pages of a document.
+type ReorderDocumentPagesInput struct {
+	ID   string `path:"id" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"Document ID"`
+	Body struct {
+		ScanIDs []string `json:"scanIds" doc:"All current page scan IDs, in the new reading order"`
+	}
+}
+
+// DocumentTextInput is the input structure for getting the assembled text of a document.
+type DocumentTextInput struct {
+	ID      string `path:"id" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"Document ID"`
+	Version int    `query:"version" minimum:"0" doc:"Document version to assemble; 0 or omitted means the current version"`
+}
+
+// DocumentOutput is the output structure for returning a document.
+type DocumentOutput struct {
+	Body Document
+}
+
+// DocumentsOutput is the output structure for listing documents.
+type DocumentsOutput struct {
+	Body struct {
+		Documents []Document `json:"documents"`
+	}
+}
+
+// DocumentTextOutput is the output structure for the assembled text of a document.
+type DocumentTextOutput struct {
+	Body struct {
+		DocumentID string `json:"documentId" example:"654a93c7e0f2f3f4c5d6e7f8"`
+		Version    int    `json:"version" example:"3"`
+		Pages      int    `json:"pages" example:"12"`
+		Text       string `json:"text" doc:"Text of all pages in reading order, separated by blank lines"`
+	}
+}
@@ -0,0 +1,141 @@
+package types
+
+import (
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"           // For FormFile
+	"go.mongodb.org/mongo-driver/bson/primitive" // For MongoDB's ObjectID
+)
+
+// Types of extraction template fields.
+const (
+	FieldTypeText   = "text"
+	FieldTypeNumber = "number"
+	FieldTypeDate   = "date"
+	FieldTypeID     = "id"
+)
+
+// Directions of an anchored field's value relative to its label.
+const (
+	AnchorRight = "right"
+	AnchorBelow = "below"
+)
+
+// ExtractionTemplate describes where the fields of a fixed-layout form (e.g., a citizenship certificate)
+// are, so POST /extract/{template} can return them as typed values.
+type ExtractionTemplate struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id" huma:"example:654a93c7e0f2f3f4c5d6e7f8"`
+	Name        string             `bson:"name" json:"name" example:"citizenship-np" doc:"Unique template name, as used in POST /extract/{template}"`
+	Description string             `bson:"description" json:"description" example:"Nepali citizenship certificate (front)"`
+	Language    string             `bson:"language" json:"language" example:"nep+eng" doc:"OCR language(s) the form is read with"`
+	Preset      string             `bson:"preset,omitempty" json:"preset,omitempty" example:"legal-form" doc:"OCR preset the form is read with"`
+	Fields      []TemplateField    `bson:"fields" json:"fields"`
+	CreatedBy   string             `bson:"created_by" json:"createdBy" doc:"ID of the admin who created the template"`
+	CreatedAt   time.Time          `bson:"created_at" json:"createdAt" huma:"example:2024-01-01T12:00:00Z"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updatedAt" huma:"example:2024-01-01T12:00:00Z"`
+}
+
+// TemplateField is a field of an extraction template. Its value is located either by a fixed region
+// of the page or by the label printed next to it; exactly one of Region and Anchor is set.
+type TemplateField struct {
+	Name     string          `bson:"name" json:"name" pattern:"^[a-zA-Z][a-zA-Z0-9_]{0,63}$" example:"citizenship_number"`
+	Type     string          `bson:"type" json:"type" enum:"text,number,date,id" default:"text" doc:"'text': as read; 'number': a decimal number; 'date': a BS or AD date, returned in both calendars; 'id': an identifier such as a citizenship number, with ASCII digits"`
+	Region   *TemplateRegion `bson:"region,omitempty" json:"region,omitempty" doc:"Fixed area of the page holding the value"`
+	Anchor   *TemplateAnchor `bson:"anchor,omitempty" json:"anchor,omitempty" doc:"Printed label the value follows"`
+	Pattern  string          `bson:"pattern,omitempty" json:"pattern,omitempty" example:"^\\d{2}-\\d{2}-\\d{2}-\\d{5}$" doc:"Regular expression (Go syntax) the normalized value must match"`
+	Required bool            `bson:"required" json:"required,omitempty" doc:"Whether the extraction is invalid without this field"`
+}
+
+// TemplateRegion is a rectangle in normalized page coordinates (0-1, from the top-left corner),
+// so templates work at any scan resolution.
+type TemplateRegion struct {
+	X      float64 `bson:"x" json:"x" minimum:"0" maximum:"1" example:"0.55"`
+	Y      float64 `bson:"y" json:"y" minimum:"0" maximum:"1" example:"0.12"`
+	Width  float64 `bson:"width" json:"width" exclusiveMinimum:"0" maximum:"1" example:"0.4"`
+	Height float64 `bson:"height" json:"height" exclusiveMinimum:"0" maximum:"1" example:"0.06"`
+}
+
+// TemplateAnchor locates a value by the label printed before it.
+type TemplateAnchor struct {
+	Label     string `bson:"label" json:"label" minLength:"1" maxLength:"100" example:"नाम थर" doc:"Label text, matched case-insensitively against the OCR lines"`
+	Direction string `bson:"direction" json:"direction" enum:"right,below" default:"right" doc:"'right': the rest of the label's line; 'below': the next line under the label"`
+}
+
+// ExtractionTemplateSummary describes a template without its fields' locations.
+type ExtractionTemplateSummary struct {
+	Name        string    `json:"name" example:"citizenship-np"`
+	Description string    `json:"description" example:"Nepali citizenship certificate (front)"`
+	Language    string    `json:"language" example:"nep+eng"`
+	Fields      []string  `json:"fields" example:"[\"full_name\",\"citizenship_number\",\"date_of_birth\"]" doc:"Field names, in template order"`
+	UpdatedAt   time.Time `json:"updatedAt" huma:"example:2024-01-01T12:00:00Z"`
+}
+
+// ExtractionTemplateBody is the request body used to create or replace a template.
+type ExtractionTemplateBody struct {
+	Name        string          `json:"name" pattern:"^[a-z0-9][a-z0-9_-]{1,63}$" example:"citizenship-np" doc:"Unique template name (lowercase letters, digits, '-' and '_')"`
+	Description string          `json:"description,omitempty" maxLength:"500" example:"Nepali citizenship certificate (front)"`
+	Language    string          `json:"language,omitempty" example:"nep+eng" doc:"OCR language(s) the form is read with; default 'eng'"`
+	Preset      string          `json:"preset,omitempty" example:"legal-form" doc:"OCR preset the form is read with (see GET /ocr/presets)"`
+	Fields      []TemplateField `json:"fields" minItems:"1" maxItems:"100"`
+}
+
+// CreateExtractionTemplateInput is the input structure for creating a template.
+type CreateExtractionTemplateInput struct {
+	Body ExtractionTemplateBody
+}
+
+// UpdateExtractionTemplateInput is the input structure for replacing a template.
+type UpdateExtractionTemplateInput struct {
+	Name string `path:"name" example:"citizenship-np" doc:"Template name"`
+	Body ExtractionTemplateBody
+}
+
+// ExtractionTemplateNameInput is the input structure for endpoints addressing a single template.
+type ExtractionTemplateNameInput struct {
+	Name string `path:"name" example:"citizenship-np" doc:"Template name"`
+}
+
+// ExtractionTemplateOutput is the output structure for returning a full template.
+type ExtractionTemplateOutput struct {
+	Body ExtractionTemplate
+}
+
+// ExtractionTemplatesOutput is the output structure for listing templates.
+type ExtractionTemplatesOutput struct {
+	Body struct {
+		Templates []ExtractionTemplateSummary `json:"templates" doc:"Available templates, sorted by name"`
+	}
+}
+
+// ExtractInput is the input structure for POST /extract/{template}.
+type ExtractInput struct {
+	Template string `path:"template" example:"citizenship-np" doc:"Name of the extraction template"`
+	RawBody  huma.MultipartFormFiles[struct {
+		Image huma.FormFile `form:"image" contentType:"image/*" required:"true" doc:"Image of the filled-in form"`
+	}]
+}
+
+// ExtractedField is the value of one template field read from a form.
+type ExtractedField struct {
+	Name string `json:"name" example:"citizenship_number"`
+	Type string `json:"type" example:"id"`
+	// Text is the OCR text of the field after post-processing; Value is its normalized form for the
+	// field type (e.g., ASCII digits). Both are empty when the field was not found.
+	Text       string        `json:"text" example:"२७-०१-७५-०१२३४"`
+	Value      string        `json:"value" example:"27-01-75-01234"`
+	Date       *DetectedDate `json:"date,omitempty" doc:"The date in both calendars, for 'date' fields"`
+	Confidence float64       `json:"confidence" doc:"Mean OCR confidence (0-100) of the field's words"`
+	Box        *OCRBox       `json:"box,omitempty" doc:"Area of the image the value was read from"`
+	Valid      bool          `json:"valid" doc:"Whether the value has the field's type and matches its pattern"`
+	Error      string        `json:"error,omitempty" example:"value does not match the pattern" doc:"Why the field is invalid"`
+}
+
+// ExtractOutput is the output structure for POST /extract/{template}.
+type ExtractOutput struct {
+	Body struct {
+		Template   string           `json:"template" example:"citizenship-np"`
+		Fields     []ExtractedField `json:"fields" doc:"Fields in template order"`
+		Valid      bool             `json:"valid" doc:"Whether all required fields were found and all found fields are valid"`
+		Confidence float64          `json:"confidence" doc:"Mean OCR confidence (0-100) of the whole page"`
+	}
+}
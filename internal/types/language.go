@@ -0,0 +1,43 @@
+package types
+
+// OCRLanguage describes a single Tesseract language/script model available on the server.
+type OCRLanguage struct {
+	Code   string `json:"code" example:"nep" doc:"Tesseract language code, as accepted by the 'lang' field of /scan"`
+	Name   string `json:"name" example:"Nepali" doc:"Human-readable display name"`
+	Script string `json:"script" example:"Devanagari" doc:"Writing system recognized by the model"`
+}
+
+// OCRLanguageInfo holds the display metadata for a known Tesseract traineddata code.
+type OCRLanguageInfo struct {
+	Name   string
+	Script string
+}
+
+// KnownOCRLanguages maps Tesseract traineddata codes to display names and scripts.
+// Only codes that are actually installed are exposed; this table just labels them.
+var KnownOCRLanguages = map[string]OCRLanguageInfo{
+	LangEnglish:       {Name: "English", Script: "Latin"},
+	LangNepali:        {Name: "Nepali", Script: "Devanagari"},
+	LangHindi:         {Name: "Hindi", Script: "Devanagari"},
+	LangDevanagari:    {Name: "Devanagari (script model)", Script: "Devanagari"},
+	"san":             {Name: "Sanskrit", Script: "Devanagari"},
+	"mar":             {Name: "Marathi", Script: "Devanagari"},
+	"bod":             {Name: "Tibetan", Script: "Tibetan"},
+	"ben":             {Name: "Bengali", Script: "Bengali"},
+	"urd":             {Name: "Urdu", Script: "Arabic"},
+	"chi_sim":         {Name: "Chinese (Simplified)", Script: "Han"},
+	"script/Latin":    {Name: "Latin (script model)", Script: "Latin"},
+	"script/Tibetan":  {Name: "Tibetan (script model)", Script: "Tibetan"},
+	"script/Bengali":  {Name: "Bengali (script model)", Script: "Bengali"},
+	"script/HanS":     {Name: "Han Simplified (script model)", Script: "Han"},
+	"script/Arabic":   {Name: "Arabic (script model)", Script: "Arabic"},
+	"script/Cyrillic": {Name: "Cyrillic (script model)", Script: "Cyrillic"},
+}
+
+// OCRLanguagesOutput is the output structure for the GET /ocr/languages endpoint.
+type OCRLanguagesOutput struct {
+	Body struct {
+		TessdataDir string        `json:"tessdataDir" example:"/usr/share/tesseract-ocr/5/tessdata" doc:"Directory the languages were discovered in"`
+		Languages   []OCRLanguage `json:"languages" doc:"Installed OCR languages, sorted by code"`
+	}
+}
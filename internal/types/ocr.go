@@ -0,0 +1,81 @@
+package types
+
+// OCRResult is the outcome of recognizing a single image.
+type OCRResult struct {
+	// Text is the recognized text after post-processing.
+	Text string `json:"text" bson:"text"`
+	// Confidence is the mean word confidence (0-100), weighted by word length; 0 when no words were found.
+	Confidence float64 `json:"confidence" bson:"confidence"`
+	// Lines and Words hold the engine's layout in image pixel coordinates. Their text is the
+	// engine's raw output, before post-processing.
+	Lines []OCRLine `json:"lines,omitempty" bson:"lines,omitempty"`
+	Words []OCRWord `json:"words,omitempty" bson:"words,omitempty"`
+	// Ensemble is only set by the ensemble ("accurate") OCR service.
+	Ensemble *OCREnsembleReport `json:"ensemble,omitempty" bson:"-"`
+	// Cached is true when the result came from the OCR result cache instead of a Tesseract run.
+	Cached bool `json:"-" bson:"-"`
+}
+
+// OCRBox is a rectangle in image pixel coordinates.
+type OCRBox struct {
+	X      int `json:"x" bson:"x"`
+	Y      int `json:"y" bson:"y"`
+	Width  int `json:"width" bson:"width"`
+	Height int `json:"height" bson:"height"`
+}
+
+// OCRLine is a recognized text line.
+type OCRLine struct {
+	Text       string  `json:"text" bson:"text"`
+	Confidence float64 `json:"confidence" bson:"confidence" doc:"Mean word confidence, 0-100"`
+	Box        OCRBox  `json:"box" bson:"box"`
+	// Paragraph numbers the paragraphs of the page; consecutive lines with equal numbers belong together.
+	Paragraph int `json:"paragraph" bson:"paragraph"`
+}
+
+// OCRWord is a recognized word.
+type OCRWord struct {
+	Text       string  `json:"text" bson:"text"`
+	Confidence float64 `json:"confidence" bson:"confidence" doc:"Recognition confidence, 0-100"`
+	Box        OCRBox  `json:"box" bson:"box"`
+	Line       int     `json:"line" bson:"line" doc:"Index of the word's line in the result's lines"`
+}
+
+// OCREnsembleReport describes how an ensemble ("accurate") scan combined its variants.
+type OCREnsembleReport struct {
+	Page     int                  `json:"page,omitempty" doc:"Page of a multi-page scan the report belongs to"`
+	Winner   string               `json:"winner" example:"psm-6" doc:"Variant with the highest overall confidence; its lines are the skeleton of the result"`
+	Replaced int                  `json:"replacedLines" doc:"Number of lines taken from another variant because it read them with higher confidence"`
+	Variants []OCREnsembleVariant `json:"variants" doc:"Every variant that was run, in run order"`
+	Lines    []OCREnsembleLine    `json:"lines" doc:"Lines of the merged result with the variant each was taken from"`
+}
+
+// OCREnsembleVariant is the outcome of one configuration of an ensemble scan.
+type OCREnsembleVariant struct {
+	Name        string  `json:"name" example:"binarized"`
+	Description string  `json:"description" example:"Otsu-binarized image"`
+	Confidence  float64 `json:"confidence" doc:"Mean word confidence of this variant, 0-100"`
+	Lines       int     `json:"lines" doc:"Number of lines the variant recognized"`
+	Error       string  `json:"error,omitempty" doc:"Why the variant failed; failed variants are ignored"`
+}
+
+// OCREnsembleLine is a line of a merged ensemble result.
+type OCREnsembleLine struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	Variant    string  `json:"variant" doc:"Variant the line was taken from"`
+}
+
+// OCREngineInfo describes an OCR engine backend enabled on the server.
+type OCREngineInfo struct {
+	Name    string `json:"name" example:"tesseract" doc:"Engine name, as accepted by the 'engine' field of /scan"`
+	Version string `json:"version" example:"tesseract 5.3.0"`
+	Default bool   `json:"default" doc:"Whether scans use this engine when none is selected"`
+}
+
+// OCREnginesOutput is the output structure for the GET /ocr/engines endpoint.
+type OCREnginesOutput struct {
+	Body struct {
+		Engines []OCREngineInfo `json:"engines" doc:"Enabled OCR engines, sorted by name"`
+	}
+}
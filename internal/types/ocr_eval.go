@@ -0,0 +1,59 @@
+package types
+
+import "time"
+
+// OCREvalReport is the result of running OCR over a ground-truth corpus (see cmd/ocr-eval).
+// Reports are written as JSON so a later run can be compared with them.
+type OCREvalReport struct {
+	GeneratedAt time.Time        `json:"generatedAt"`
+	Corpus      string           `json:"corpus"`    // Directory the samples were read from
+	Engine      string           `json:"engine"`    // Engine version, e.g. "tesseract 5.3.0"
+	Preset      string           `json:"preset"`    // OCR preset; empty for the defaults
+	Options     OCROptions       `json:"options"`   // Resolved options the samples were recognized with
+	Overall     OCREvalSummary   `json:"overall"`   // All samples together
+	Languages   []OCREvalSummary `json:"languages"` // One summary per language, sorted by language
+	Samples     []OCREvalSample  `json:"samples"`   // Sorted by image path
+}
+
+// OCREvalSummary aggregates the samples of one language (or of the whole corpus). Error rates are
+// micro-averaged: total errors over total reference length, so long pages weigh more than short ones.
+// Failed samples count as if nothing was recognized.
+type OCREvalSummary struct {
+	Language        string  `json:"language"` // "all" for the overall summary
+	Samples         int     `json:"samples"`
+	Failed          int     `json:"failed"`
+	Characters      int     `json:"characters"`
+	CharacterErrors int     `json:"characterErrors"`
+	Words           int     `json:"words"`
+	WordErrors      int     `json:"wordErrors"`
+	CER             float64 `json:"cer"`        // Character error rate; can exceed 1 when the output is much longer
+	WER             float64 `json:"wer"`        // Word error rate
+	Confidence      float64 `json:"confidence"` // Mean engine confidence of the recognized samples (0-100)
+	Millis          int64   `json:"millis"`     // Total recognition time
+}
+
+// OCREvalSample is the accuracy of OCR on one image of the corpus.
+type OCREvalSample struct {
+	Image           string  `json:"image"` // Path relative to the corpus directory
+	Language        string  `json:"language"`
+	Characters      int     `json:"characters"`
+	CharacterErrors int     `json:"characterErrors"`
+	Words           int     `json:"words"`
+	WordErrors      int     `json:"wordErrors"`
+	CER             float64 `json:"cer"`
+	WER             float64 `json:"wer"`
+	Confidence      float64 `json:"confidence"`
+	Millis          int64   `json:"millis"`
+	Text            string  `json:"text"`            // Recognized text, for inspecting errors
+	Error           string  `json:"error,omitempty"` // Why recognition failed
+}
+
+// OCREvalChange compares an error rate of a report with the same rate of a baseline report.
+type OCREvalChange struct {
+	Language  string  `json:"language"` // "all" for the overall rates
+	Metric    string  `json:"metric"`   // "cer" or "wer"
+	Baseline  float64 `json:"baseline"`
+	Current   float64 `json:"current"`
+	Delta     float64 `json:"delta"`     // Current - Baseline; positive is worse
+	Regressed bool    `json:"regressed"` // Delta exceeds the comparison's tolerance
+}
@@ -0,0 +1,40 @@
+package types
+
+// OCROptions holds the Tesseract engine tuning parameters for a single scan.
+// Pointer fields distinguish "not set" (use the Tesseract default) from explicit values.
+type OCROptions struct {
+	Engine                  string   `json:"engine,omitempty" bson:"engine,omitempty" example:"tesseract" doc:"OCR engine backend (see GET /ocr/engines); unset uses the server default"`
+	PageSegMode             *int     `json:"psm,omitempty" bson:"psm,omitempty" example:"6" doc:"Tesseract page segmentation mode (1, 3-13)"`
+	EngineMode              *int     `json:"oem,omitempty" bson:"oem,omitempty" example:"1" doc:"Tesseract OCR engine mode (0 legacy, 1 LSTM, 2 both, 3 default)"`
+	CharWhitelist           string   `json:"whitelist,omitempty" bson:"whitelist,omitempty" doc:"Only recognize these characters"`
+	CharBlacklist           string   `json:"blacklist,omitempty" bson:"blacklist,omitempty" doc:"Never recognize these characters"`
+	PreserveInterwordSpaces *bool    `json:"preserveInterwordSpaces,omitempty" bson:"preserve_interword_spaces,omitempty" doc:"Keep runs of spaces between words (useful for forms)"`
+	UserWords               []string `json:"userWords,omitempty" bson:"user_words,omitempty" doc:"Extra dictionary words for Tesseract"`
+	UserPatterns            []string `json:"userPatterns,omitempty" bson:"user_patterns,omitempty" doc:"Extra Tesseract user patterns (e.g., '\\d\\d\\d\\d/\\d\\d/\\d\\d')"`
+
+	// PostProcess controls the text clean-up applied to Tesseract's output.
+	PostProcess TextPostProcessOptions `json:"postProcess,omitempty" bson:"post_process,omitempty" doc:"Post-processing applied to the recognized text"`
+}
+
+// TextPostProcessOptions toggles the individual stages of OCR text post-processing.
+// Unset fields use the defaults: Unicode normalization on, everything else off.
+type TextPostProcessOptions struct {
+	NormalizeUnicode *bool  `json:"normalizeUnicode,omitempty" bson:"normalize_unicode,omitempty" doc:"NFC-normalize and strip stray zero-width characters (default true)"`
+	Digits           string `json:"digits,omitempty" bson:"digits,omitempty" enum:"ascii,devanagari" doc:"Convert digits to 'ascii' (0-9) or 'devanagari' (०-९); unset keeps them as recognized"`
+	RepairLineBreaks *bool  `json:"repairLineBreaks,omitempty" bson:"repair_line_breaks,omitempty" doc:"Re-join hyphenated words and collapse stray whitespace (default false)"`
+	FixConfusions    *bool  `json:"fixConfusions,omitempty" bson:"fix_confusions,omitempty" doc:"Fix common Devanagari confusions such as '|' for '।' (default false)"`
+}
+
+// OCRPreset is a named, server-side set of OCROptions (e.g., "legal-form", "prose").
+type OCRPreset struct {
+	Name        string     `json:"name" example:"legal-form" doc:"Preset name, as accepted by the 'preset' field of /scan"`
+	Description string     `json:"description" example:"Single uniform block with preserved spacing, for printed forms"`
+	Options     OCROptions `json:"options" doc:"Tesseract options applied by this preset"`
+}
+
+// OCRPresetsOutput is the output structure for the GET /ocr/presets endpoint.
+type OCRPresetsOutput struct {
+	Body struct {
+		Presets []OCRPreset `json:"presets" doc:"Available OCR presets, sorted by name"`
+	}
+}
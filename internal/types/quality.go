@@ -0,0 +1,58 @@
+package types
+
+import "github.com/danielgtaylor/huma/v2" // For FormFile
+
+// Codes of QualityIssue.
+const (
+	QualityIssueBlurry        = "blurry"
+	QualityIssueDark          = "dark"
+	QualityIssueOverexposed   = "overexposed"
+	QualityIssueLowContrast   = "low_contrast"
+	QualityIssueLowResolution = "low_resolution"
+	QualityIssueSkewed        = "skewed"
+	QualityIssueNoText        = "no_text"
+)
+
+// Severities of QualityIssue.
+const (
+	QualitySeverityWarning  = "warning"
+	QualitySeverityCritical = "critical"
+)
+
+// ImageQuality is the assessment of how well an image is suited for OCR.
+type ImageQuality struct {
+	Page   int  `json:"page,omitempty" doc:"Page of a multi-page scan the assessment belongs to"`
+	Score  int  `json:"score" minimum:"0" maximum:"100" example:"82" doc:"Overall fitness for OCR; below 60, OCR results are usually poor"`
+	Retake bool `json:"retake" doc:"Whether the user should take the picture again"`
+
+	Width      int     `json:"width" example:"3024"`
+	Height     int     `json:"height" example:"4032"`
+	Sharpness  float64 `json:"sharpness" example:"412.5" doc:"Variance of the Laplacian of the image reduced to 1600 pixels; below about 60 the image is blurry"`
+	Brightness float64 `json:"brightness" example:"221.4" doc:"Mean luminance of the paper, from 0 (black) to 255 (white)"`
+	Contrast   float64 `json:"contrast" example:"168.9" doc:"Difference between the mean luminance of the paper and of the ink"`
+	DPI        int     `json:"dpi" example:"240" doc:"Resolution from the file metadata, or estimated from the text line height assuming body text of about 11 pt; 0 if unknown"`
+	DPISource  string  `json:"dpiSource" enum:"metadata,estimated,unknown" example:"estimated"`
+	TextHeight int     `json:"textHeight" example:"36" doc:"Median height of the text lines in pixels; 0 if no text lines were found"`
+	Skew       float64 `json:"skew" example:"-1.5" doc:"Angle of the text lines in degrees, positive when they rise to the right"`
+
+	Issues []QualityIssue `json:"issues" doc:"Problems found, most severe first, each with advice for the user"`
+}
+
+// QualityIssue is a problem with an image, with advice on how to take a better one.
+type QualityIssue struct {
+	Code     string `json:"code" enum:"blurry,dark,overexposed,low_contrast,low_resolution,skewed,no_text" example:"blurry"`
+	Severity string `json:"severity" enum:"warning,critical" example:"critical"`
+	Advice   string `json:"advice" example:"The image is blurry. Hold the phone steady and tap the page to focus."`
+}
+
+// ScanQualityInput is the input structure for POST /scan/quality.
+type ScanQualityInput struct {
+	RawBody huma.MultipartFormFiles[struct {
+		Image huma.FormFile `form:"image" contentType:"image/*" required:"true" doc:"Image to assess (PNG or JPEG)"`
+	}]
+}
+
+// ScanQualityOutput is the output structure for POST /scan/quality.
+type ScanQualityOutput struct {
+	Body ImageQuality
+}
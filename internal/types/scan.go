@@ -1,38 +1,187 @@
 package types
 
-import "github.com/danielgtaylor/huma/v2" // Ensure huma is imported for FormFile
+import (
+	"time"
 
-// Define constants for supported OCR languages.
+	"github.com/danielgtaylor/huma/v2"           // Ensure huma is imported for FormFile
+	"go.mongodb.org/mongo-driver/bson/primitive" // For MongoDB's ObjectID
+)
+
+// Define constants for commonly used OCR languages.
+// The set actually accepted by /scan is discovered at startup from the tessdata directory.
 const (
 	LangEnglish    = "eng"
 	LangNepali     = "nep"
 	LangHindi      = "hin"
-	LangDevanagari = "dev" // Script-specific, not a language
+	LangDevanagari = "script/Devanagari" // Script-specific model, not a language
 )
 
-// SupportedOCRLanguages is a map for quick lookup of valid language/script codes.
-var SupportedOCRLanguages = map[string]bool{
-	LangEnglish:    true,
-	LangNepali:     true,
-	LangHindi:      true,
-	LangDevanagari: true,
-}
-
 // ScanInput is the input structure for the /scan endpoint using multipart/form-data.
 // It expects an image file and an optional language hint.
 type ScanInput struct {
-	RawBody huma.MultipartFormFiles[struct {
-		Image huma.FormFile `form:"image" contentType:"image/*" required:"true" doc:"Image file for OCR scanning (e.g., JPEG, PNG)"`
-		// Language field now uses `enum` tag for documentation and hints at allowed values.
-		// Runtime validation will be added in the handler.
-		Language string `form:"lang" huma:"example:eng,default:eng,enum:eng,nep,hin,dev" doc:"Tesseract language code (e.g., 'eng', 'nep', 'hin', 'dev' for Devanagari script). Use '+' to combine (e.g., 'eng+hin'). Default is 'eng'."`
-	}]
+	RawBody huma.MultipartFormFiles[ScanFormData]
+}
+
+// ScanFormData holds the multipart form fields of a /scan request.
+// This is a named struct so helpers can accept the decoded form.
+type ScanFormData struct {
+	Image huma.FormFile `form:"image" contentType:"image/*" required:"true" doc:"Image file for OCR scanning (e.g., JPEG, PNG)"`
+	// Allowed values depend on the installed traineddata; they are validated in the handler
+	// and listed by GET /ocr/languages.
+	Language string `form:"lang" huma:"example:eng,default:eng" doc:"Tesseract language code(s) (e.g., 'eng', 'nep', 'hin', 'script/Devanagari'). Use '+' to combine (e.g., 'eng+nep'). See GET /ocr/languages for installed codes. Default is 'eng'."`
+
+	// Recognition mode; "accurate" runs several configurations and merges them line by line.
+	Mode string `form:"mode" huma:"example:accurate" doc:"'fast' (default): one OCR pass; 'accurate': several passes (page segmentation modes, preprocessing, single languages) merged by line confidence"`
+	// Tesseract tuning. Values given here override the selected preset; empty means "not set".
+	Engine                  string `form:"engine" huma:"example:tesseract" doc:"OCR engine backend (see GET /ocr/engines); default is the server's default engine"`
+	Preset                  string `form:"preset" huma:"example:legal-form" doc:"Named server-side preset of OCR options (see GET /ocr/presets)"`
+	PageSegMode             string `form:"psm" huma:"example:6" doc:"Page segmentation mode: 1 or 3-13 (e.g., 6 = single uniform block, 7 = single line)"`
+	EngineMode              string `form:"oem" huma:"example:1" doc:"OCR engine mode: 0 legacy, 1 LSTM, 2 legacy+LSTM, 3 default"`
+	CharWhitelist           string `form:"whitelist" doc:"Only recognize these characters"`
+	CharBlacklist           string `form:"blacklist" doc:"Never recognize these characters"`
+	PreserveInterwordSpaces string `form:"preserve_spaces" huma:"example:true" doc:"'true' or 'false': keep runs of spaces between words"`
+	UserWords               string `form:"user_words" doc:"Extra dictionary words, one per line"`
+	UserPatterns            string `form:"user_patterns" doc:"Extra Tesseract user patterns, one per line"`
+	Dictionary              string `form:"dictionary" huma:"example:legal-np" doc:"Custom vocabulary domain to apply (see GET /ocr/dictionaries)"`
+
+	// Text post-processing toggles. Empty means "use the preset or default".
+	NormalizeUnicode string `form:"normalize" huma:"example:true" doc:"'true' or 'false': NFC-normalize and strip stray zero-width characters (default true)"`
+	Digits           string `form:"digits" huma:"example:ascii" doc:"'ascii' (०-९ → 0-9) or 'devanagari' (0-9 → ०-९); empty keeps digits as recognized"`
+	RepairLineBreaks string `form:"repair_lines" huma:"example:true" doc:"'true' or 'false': re-join hyphenated words and collapse stray whitespace"`
+	FixConfusions    string `form:"fix_confusions" huma:"example:true" doc:"'true' or 'false': fix common Devanagari confusions such as '|' for '।'"`
+
+	// Optional romanized copy of the extracted text.
+	Transliterate string `form:"transliterate" huma:"example:iast" doc:"Also return the text transliterated to 'iast', 'iso15919' or 'nepali'"`
+	// Additional pages, OCRed after 'image' in order; their text is joined with blank lines.
+	Pages []huma.FormFile `form:"pages" contentType:"image/*" doc:"Further page images of a multi-page scan, in page order"`
+	// Background processing with progress events.
+	Async string `form:"async" huma:"example:true" doc:"'true' or 'false': return 202 at once and stream progress from GET /scans/{id}/events (requires a bearer token; default false). A synchronous scan must finish all its pages within the OCR timeout."`
+	// Per-scan webhook, called in addition to the user's registered webhooks.
+	WebhookURL    string `form:"webhook_url" huma:"example:https://example.com/hooks/scan" doc:"URL notified with a signed scan.completed or scan.failed payload (requires a bearer token)"`
+	WebhookSecret string `form:"webhook_secret" doc:"Secret (at least 16 characters) used to sign the payload sent to 'webhook_url'"`
+	// Optional legal document structure of the extracted text.
+	ParseStructure string `form:"parse_structure" huma:"example:true" doc:"'true' or 'false': also return the chapter/section/clause structure of the text (default false)"`
+	// Optional regions of interest; only these parts of each page are recognized.
+	Regions     string `form:"regions" huma:"example:[{\"x\":120,\"y\":340,\"width\":900,\"height\":160}]" doc:"JSON array of rectangles {x, y, width, height} to OCR instead of the whole page, applied to every page; text is returned per region in request order (PNG and JPEG pages only)"`
+	RegionUnits string `form:"region_units" huma:"example:normalized" doc:"'pixels' (default) or 'normalized' (0-1 fractions of the page width and height) for 'regions'"`
+	// Text of regions flagged in 'warnings' can be left out of 'text'.
+	ExcludeUnreliable string `form:"exclude_unreliable" huma:"example:true" doc:"'true' or 'false': leave regions flagged as likely handwriting or low-quality text out of 'text' (default false; they are reported in 'warnings' either way)"`
+	// Optional barcode reading.
+	Barcodes string `form:"barcodes" huma:"example:true" doc:"'true' or 'false': also decode QR codes, Code 128 and EAN-13 barcodes, Data Matrix codes and PDF417 symbols on the pages (default false)"`
+	// Optional dictionary-based correction of low-confidence words.
+	SpellCorrect string `form:"spell_correct" huma:"example:true" doc:"'true' or 'false': replace words recognized with low confidence that are not in the word list of the scan language by the closest frequent word, reporting them in 'corrections' (default false). Words of the selected 'dictionary' count as known."`
+	// Optional image quality check, as by POST /scan/quality.
+	CheckQuality string `form:"check_quality" huma:"example:true" doc:"'true' or 'false': also assess the blur, exposure, resolution and skew of each page, with advice for a retake (default false; PNG and JPEG pages only)"`
+	// Optional table extraction from the page images.
+	Tables string `form:"tables" huma:"example:true" doc:"'true' or 'false': also detect tables on the pages and return their cells as rows and CSV (default false)"`
 }
 
 // ScanOutput is the output structure for the /scan endpoint.
-// It returns the extracted text.
+// It returns the extracted text, or for asynchronous scans (status 202) the ID to follow.
 type ScanOutput struct {
-	Body struct {
-		Text string `json:"text" huma:"example:Extracted text from the image"`
-	}
+	Status int
+	Body   ScanOutputBody
+}
+
+// Values of the 'mode' field of /scan.
+const (
+	ScanModeFast     = "fast"
+	ScanModeAccurate = "accurate"
+)
+
+// Values of the 'region_units' field of /scan.
+const (
+	RegionUnitsPixels     = "pixels"
+	RegionUnitsNormalized = "normalized"
+)
+
+// ScanRegion is a rectangle of a page given in the 'regions' field of /scan, in the request's region units.
+type ScanRegion struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// ScanRegionResult is the text recognized in one region of a page.
+type ScanRegionResult struct {
+	Page       int     `json:"page" example:"1" doc:"Page the region is on"`
+	Index      int     `json:"index" example:"0" doc:"Position of the region in the 'regions' field, from 0"`
+	Box        OCRBox  `json:"box" doc:"The region in page image pixels, clipped to the page"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence" doc:"Mean word confidence (0-100) of the region"`
+}
+
+// Values of ScanOutputBody.Status.
+const (
+	ScanStatusCompleted = "completed"
+	ScanStatusQueued    = "queued"
+)
+
+// ScanOutputBody is the JSON body returned by /scan.
+type ScanOutputBody struct {
+	// ID is empty if the scan could not be stored; the text is still returned.
+	ID     string `json:"id,omitempty" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"ID of the stored scan, for attaching it to a document; only scans made with a bearer token are stored"`
+	Status string `json:"status" enum:"completed,queued" doc:"'queued' for an asynchronous scan that has not run yet; its result follows as a 'completed' event"`
+	// EventsURL is only set for asynchronous scans.
+	EventsURL string `json:"eventsUrl,omitempty" example:"/scans/654a93c7e0f2f3f4c5d6e7f8/events" doc:"Server-Sent Events stream with the scan's progress"`
+	Text      string `json:"text" huma:"example:Extracted text from the image"`
+	// Cached is true when an identical scan (same image, languages and options) was answered from the OCR cache.
+	Cached bool `json:"cached" doc:"True if the text was served from the OCR result cache"`
+	// Transliteration is only present when the 'transliterate' form field was set.
+	Transliteration string `json:"transliteration,omitempty" example:"nepālako saṃvidhāna" doc:"Extracted text in the requested romanization scheme"`
+	// Dates lists the Bikram Sambat and Gregorian dates found in the text, in text order.
+	Dates []DetectedDate `json:"dates" doc:"Dates found in the text, in both calendars"`
+	// Warnings flags regions of likely handwriting or low-confidence text, in page order.
+	Warnings []ScanWarning `json:"warnings,omitempty" doc:"Regions whose text is unreliable, such as handwritten marginalia"`
+	// Ensemble is only present for 'accurate' scans, with one report per page.
+	Ensemble []OCREnsembleReport `json:"ensemble,omitempty" doc:"How each page's variants were merged, including which variant won"`
+	// Regions is only present when the 'regions' form field was set; Text then holds the regions' text.
+	Regions []ScanRegionResult `json:"regions,omitempty" doc:"Text of each requested region, by page and then in request order"`
+	// Barcodes is only present when the 'barcodes' form field was 'true' and codes were found.
+	Barcodes []DetectedBarcode `json:"barcodes,omitempty" doc:"QR codes and barcodes decoded from the pages, with their positions"`
+	// Tables is only present when the 'tables' form field was 'true' and tables were found.
+	Tables []DetectedTable `json:"tables,omitempty" doc:"Tables found on the pages, in page order and top to bottom"`
+	// Corrections is only present when the 'spell_correct' form field was 'true' and words were corrected.
+	Corrections []SpellCorrection `json:"corrections,omitempty" doc:"Words replaced by spell correction, in page and reading order"`
+	// Quality is only present when the 'check_quality' form field was 'true'.
+	Quality []ImageQuality `json:"quality,omitempty" doc:"Image quality assessment of each page, in page order"`
+	// Structure is only present when the 'parse_structure' form field was 'true'.
+	Structure *DocumentStructure `json:"structure,omitempty" doc:"Legal document structure parsed from the text"`
+}
+
+// ScanRecord is a stored OCR result. Documents reference scans as their pages.
+type ScanRecord struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OwnerID   string             `bson:"owner_id,omitempty" json:"ownerId,omitempty" doc:"ID of the user who made the scan; empty for anonymous scans"`
+	Language  string             `bson:"language" json:"language" example:"nep"`
+	Preset    string             `bson:"preset,omitempty" json:"preset,omitempty" example:"legal-form"`
+	Text      string             `bson:"text" json:"text"`
+	Pages     int                `bson:"pages,omitempty" json:"pages,omitempty" doc:"Number of page images the text was read from"`
+	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
+
+	// Uploaded image (the first page), stored in the blob store under its SHA-256. Empty if the image was not kept.
+	ImageKey     string `bson:"image_key,omitempty" json:"imageSha256,omitempty" doc:"SHA-256 of the original image"`
+	ImageType    string `bson:"image_type,omitempty" json:"imageType,omitempty" example:"image/png"`
+	ImageSize    int64  `bson:"image_size,omitempty" json:"imageSize,omitempty" doc:"Size of the original image in bytes"`
+	ImageWidth   int    `bson:"image_width,omitempty" json:"imageWidth,omitempty"`
+	ImageHeight  int    `bson:"image_height,omitempty" json:"imageHeight,omitempty"`
+	ThumbnailKey string `bson:"thumbnail_key,omitempty" json:"-"`
+	// PageImageKeys lists the blob keys of all pages of a multi-page scan, in page order.
+	PageImageKeys []string `bson:"page_image_keys,omitempty" json:"-"`
+}
+
+// ScanImageInput is the input for GET /scans/{id}/image.
+type ScanImageInput struct {
+	ID        string `path:"id" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"ID of the stored scan"`
+	Page      int    `query:"page" minimum:"1" default:"1" doc:"Page of a multi-page scan"`
+	Thumbnail bool   `query:"thumbnail" doc:"Return a small JPEG thumbnail of the first page instead of the original image"`
+}
+
+// ScanImageOutput returns the raw bytes of a stored scan image.
+type ScanImageOutput struct {
+	ContentType  string `header:"Content-Type"`
+	ETag         string `header:"ETag"`
+	CacheControl string `header:"Cache-Control"`
+	Body         []byte
 }
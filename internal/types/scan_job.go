@@ -0,0 +1,38 @@
+package types
+
+// Stages reported in "progress" events of GET /scans/{id}/events.
+const (
+	ScanStageQueued        = "queued"        // Waiting for a free OCR worker
+	ScanStagePreprocessing = "preprocessing" // Loading the image and configuring Tesseract
+	ScanStageRecognizing   = "recognizing"   // Tesseract is recognizing text
+	ScanStagePage          = "page"          // Started a new page of a multi-page scan
+)
+
+// ScanProgressEvent is the data of a "progress" event.
+type ScanProgressEvent struct {
+	Stage   string `json:"stage" enum:"queued,preprocessing,recognizing,page" doc:"Pipeline stage the scan entered"`
+	Page    int    `json:"page,omitempty" example:"2" doc:"1-based page the stage applies to; absent before the first page starts"`
+	Pages   int    `json:"pages" example:"3" doc:"Total number of pages in the scan"`
+	Message string `json:"message" example:"page 2 of 3" doc:"Human-readable description of the stage"`
+}
+
+// ScanFailedEvent is the data of a "failed" event.
+type ScanFailedEvent struct {
+	Status int    `json:"status" example:"504" doc:"HTTP status the scan would have failed with"`
+	Error  string `json:"error" example:"OCR scan did not finish within 30s"`
+}
+
+// ScanEvent is one entry of a scan job's event log. Exactly one of Progress, Completed and Failed is set;
+// Completed and Failed end the log.
+type ScanEvent struct {
+	ID        int // 1-based, increasing; sent as the SSE event ID for Last-Event-ID reconnects
+	Progress  *ScanProgressEvent
+	Completed *ScanOutputBody
+	Failed    *ScanFailedEvent
+}
+
+// ScanEventsInput is the input for GET /scans/{id}/events.
+type ScanEventsInput struct {
+	ID          string `path:"id" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"ID returned by an asynchronous /scan request"`
+	LastEventID int    `header:"Last-Event-ID" doc:"ID of the last event received; the stream resumes after it"`
+}
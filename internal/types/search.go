@@ -0,0 +1,68 @@
+package types
+
+import "time"
+
+// Kinds of items in the full-text search index.
+const (
+	SearchKindScan     = "scan"
+	SearchKindDocument = "document"
+)
+
+// SearchItem is one searchable unit: a stored scan or the assembled text of a document.
+type SearchItem struct {
+	Kind      string    // SearchKindScan or SearchKindDocument
+	ItemID    string    // Scan or document ID
+	OwnerID   string    // Empty for anonymous scans
+	Language  string    // Tesseract language code(s), e.g. "eng+nep"
+	Title     string    // Document title; empty for scans
+	Text      string    // Full text
+	CreatedAt time.Time // Scan or document creation time
+}
+
+// SearchFilter restricts and pages search results. Zero values mean "no restriction".
+type SearchFilter struct {
+	OwnerID  string
+	Language string // Matches items whose language list contains this code
+	Kind     string
+	From     time.Time // Inclusive lower bound on CreatedAt
+	To       time.Time // Exclusive upper bound on CreatedAt
+	Limit    int
+	Offset   int
+}
+
+// SearchHit is an index entry matching a query, with its relevance score.
+type SearchHit struct {
+	Item  SearchItem
+	Score float64
+}
+
+// SearchInput is the input structure for the /search endpoint.
+type SearchInput struct {
+	Query    string `query:"q" required:"true" minLength:"1" maxLength:"500" example:"\"दफा ५\" संशोधन*" doc:"Words must all match; use \"double quotes\" for phrases and a trailing * for prefixes"`
+	Kind     string `query:"kind" enum:"scan,document" doc:"Only return scans or only documents"`
+	Language string `query:"language" example:"nep" doc:"Only return items recognized with this language code"`
+	Owner    string `query:"owner" doc:"Only return items of this user (admins only; others always search their own items)"`
+	From     string `query:"from" example:"2024-01-01" doc:"Only items created on or after this date (YYYY-MM-DD)"`
+	To       string `query:"to" example:"2024-12-31" doc:"Only items created on or before this date (YYYY-MM-DD)"`
+	Limit    int    `query:"limit" minimum:"1" maximum:"100" default:"20"`
+	Offset   int    `query:"offset" minimum:"0" default:"0"`
+}
+
+// SearchResult is one search hit returned by the /search endpoint.
+type SearchResult struct {
+	Kind      string    `json:"kind" example:"document"`
+	ID        string    `json:"id" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"Scan or document ID"`
+	Title     string    `json:"title,omitempty" example:"मुलुकी अपराध संहिता, २०७४"`
+	Language  string    `json:"language" example:"nep"`
+	Score     float64   `json:"score" example:"3.5"`
+	Snippet   string    `json:"snippet" example:"... <mark>दफा</mark> <mark>५</mark> बमोजिम ..." doc:"Excerpt with matches wrapped in <mark> tags; other text is HTML-escaped"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SearchOutput is the output structure for the /search endpoint.
+type SearchOutput struct {
+	Body struct {
+		Total   int            `json:"total" example:"42" doc:"Number of matching items"`
+		Results []SearchResult `json:"results"`
+	}
+}
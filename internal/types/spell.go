@@ -0,0 +1,13 @@
+package types
+
+// SpellCorrection is a word recognized with low confidence that was replaced by the closest
+// dictionary word.
+type SpellCorrection struct {
+	Page       int     `json:"page,omitempty" doc:"Page of a multi-page scan the word is on"`
+	Original   string  `json:"original" example:"Governrnent" doc:"Word as recognized"`
+	Corrected  string  `json:"corrected" example:"Government" doc:"Word in the scan text"`
+	Language   string  `json:"language" example:"eng" doc:"Language of the word list the correction comes from; 'custom' for the words of the selected dictionary"`
+	Confidence float64 `json:"confidence" example:"41.5" doc:"Recognition confidence of the original word, 0-100"`
+	Distance   int     `json:"distance" example:"2" doc:"Edits between the words; an OCR confusion such as 'rn' for 'm' counts as one"`
+	Box        OCRBox  `json:"box" doc:"Position of the word in the page image, in pixels"`
+}
@@ -0,0 +1,30 @@
+package types
+
+// DocumentNode is one element of a parsed legal document: a chapter (परिच्छेद), section (दफा),
+// sub-section (उपदफा), clause (खण्ड) or the preamble.
+type DocumentNode struct {
+	Kind     string         `json:"kind" enum:"preamble,chapter,section,subsection,clause" example:"section" doc:"Structural element type"`
+	Number   string         `json:"number,omitempty" example:"१" doc:"Number or letter as written in the document (e.g., '१', '5A', 'क')"`
+	Heading  string         `json:"heading,omitempty" example:"संक्षिप्त नाम र प्रारम्भ" doc:"Chapter title or section heading"`
+	Text     string         `json:"text,omitempty" doc:"Body text belonging directly to this element"`
+	Children []DocumentNode `json:"children,omitempty" doc:"Nested elements, in document order"`
+}
+
+// DocumentStructure is the hierarchy parsed from the text of an act, rules or similar instrument.
+type DocumentStructure struct {
+	Title    string         `json:"title,omitempty" example:"मुलुकी अपराध संहिता, २०७४" doc:"Title of the act, if one was recognized"`
+	Sections int            `json:"sections" example:"12" doc:"Number of sections found"`
+	Nodes    []DocumentNode `json:"nodes" doc:"Top-level elements: preamble, chapters, or sections when the document has no chapters"`
+}
+
+// ParseDocumentInput is the input structure for the /documents/parse endpoint.
+type ParseDocumentInput struct {
+	Body struct {
+		Text string `json:"text" minLength:"1" maxLength:"2000000" doc:"Plain text of the document (e.g., the 'text' returned by /scan)"`
+	}
+}
+
+// ParseDocumentOutput is the output structure for the /documents/parse endpoint.
+type ParseDocumentOutput struct {
+	Body DocumentStructure
+}
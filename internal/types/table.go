@@ -0,0 +1,20 @@
+package types
+
+// Values of DetectedTable.Source.
+const (
+	TableSourceGrid  = "grid"
+	TableSourceRuled = "ruled"
+)
+
+// DetectedTable is a table found on a scanned page, with the OCR text of each cell.
+type DetectedTable struct {
+	Page   int    `json:"page,omitempty" doc:"Page of a multi-page scan the table is on"`
+	Box    OCRBox `json:"box" doc:"Bounds of the table in page image pixels"`
+	Source string `json:"source" enum:"grid,ruled" doc:"'grid': drawn row and column lines; 'ruled': drawn row lines only, columns inferred from the gaps between words"`
+	// Rows holds the text of every cell, by row and then column; all rows have the same number of cells.
+	Rows        [][]string `json:"rows" doc:"Cell text by row, then column; empty cells are empty strings"`
+	RowCount    int        `json:"rowCount" example:"5"`
+	ColumnCount int        `json:"columnCount" example:"3"`
+	Confidence  float64    `json:"confidence" doc:"Mean confidence (0-100) of the words in the table"`
+	CSV         string     `json:"csv" example:"क्र.सं.,विवरण,दस्तुर\n१,नागरिकता प्रतिलिपि,१००\n" doc:"The rows as RFC 4180 CSV"`
+}
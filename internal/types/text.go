@@ -0,0 +1,19 @@
+package types
+
+// TransliterateInput is the input structure for the /text/transliterate endpoint.
+type TransliterateInput struct {
+	Body struct {
+		Text string `json:"text" maxLength:"100000" example:"नेपालको संविधान" doc:"Text to transliterate"`
+		From string `json:"from,omitempty" enum:"devanagari,iast,iso15919" default:"devanagari" doc:"Scheme of the input text"`
+		To   string `json:"to" enum:"devanagari,iast,iso15919,nepali" example:"iast" doc:"Scheme to convert to. 'nepali' is a lossy, diacritic-free romanization and cannot be converted back."`
+	}
+}
+
+// TransliterateOutput is the output structure for the /text/transliterate endpoint.
+type TransliterateOutput struct {
+	Body struct {
+		Text string `json:"text" example:"nepālako saṃvidhāna" doc:"Transliterated text"`
+		From string `json:"from" example:"devanagari"`
+		To   string `json:"to" example:"iast"`
+	}
+}
@@ -0,0 +1,20 @@
+package types
+
+// Kinds of ScanWarning.
+const (
+	WarningHandwriting = "handwriting"
+	WarningLowQuality  = "low_quality"
+)
+
+// ScanWarning flags a region of a page whose recognized text is unreliable: likely handwriting
+// (e.g., marginalia), or print that was read with low confidence.
+type ScanWarning struct {
+	Page       int     `json:"page,omitempty" doc:"Page of a multi-page scan the region is on"`
+	Kind       string  `json:"kind" enum:"handwriting,low_quality" example:"handwriting"`
+	Message    string  `json:"message" example:"Likely handwriting (2 lines, confidence 31.5); its text is unreliable."`
+	Box        OCRBox  `json:"box" doc:"The region in page image pixels"`
+	Lines      int     `json:"lines" example:"2" doc:"Number of text lines in the region"`
+	Confidence float64 `json:"confidence" doc:"Mean word confidence (0-100) of the region"`
+	Text       string  `json:"text" doc:"Text recognized in the region, as read by the engine"`
+	Excluded   bool    `json:"excluded" doc:"Whether the region's text was left out of the scan text"`
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive" // For MongoDB's ObjectID
+)
+
+// Webhook event names.
+const (
+	WebhookEventScanCompleted = "scan.completed"
+	WebhookEventScanFailed    = "scan.failed"
+)
+
+// Webhook delivery states.
+const (
+	WebhookDeliveryPending   = "pending"   // Still being attempted
+	WebhookDeliverySucceeded = "succeeded" // The receiver answered 2xx
+	WebhookDeliveryFailed    = "failed"    // All attempts failed
+)
+
+// Webhook is a URL registered by a user to be notified when their scans finish.
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" huma:"example:654a93c7e0f2f3f4c5d6e7f8"`
+	OwnerID   string             `bson:"owner_id" json:"ownerId"`
+	URL       string             `bson:"url" json:"url" example:"https://example.com/hooks/niyam"`
+	Events    []string           `bson:"events" json:"events" example:"[\"scan.completed\",\"scan.failed\"]"`
+	Secret    string             `bson:"secret" json:"-"` // HMAC-SHA256 signing key; only returned when the webhook is created
+	CreatedAt time.Time          `bson:"created_at" json:"createdAt" huma:"example:2024-01-01T12:00:00Z"`
+}
+
+// WebhookBody is the request body used to register a webhook.
+type WebhookBody struct {
+	URL    string   `json:"url" format:"uri" maxLength:"2048" example:"https://example.com/hooks/niyam" doc:"http(s) URL receiving POSTed JSON payloads"`
+	Events []string `json:"events,omitempty" uniqueItems:"true" enum:"scan.completed,scan.failed" doc:"Events to deliver (default: all)"`
+	Secret string   `json:"secret,omitempty" minLength:"16" maxLength:"256" doc:"Signing secret; generated when omitted"`
+}
+
+// CreateWebhookInput is the input structure for registering a webhook.
+type CreateWebhookInput struct {
+	Body WebhookBody
+}
+
+// WebhookIDInput is the input structure for endpoints addressing a single webhook.
+type WebhookIDInput struct {
+	ID string `path:"id" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"Webhook ID"`
+}
+
+// CreateWebhookOutput returns a newly registered webhook together with its signing secret.
+type CreateWebhookOutput struct {
+	Body struct {
+		Webhook
+		Secret string `json:"secret" doc:"Key for verifying the X-Niyam-Signature header; it is not shown again"`
+	}
+}
+
+// WebhooksOutput is the output structure for listing webhooks.
+type WebhooksOutput struct {
+	Body struct {
+		Webhooks []Webhook `json:"webhooks" doc:"The caller's webhooks, newest first"`
+	}
+}
+
+// WebhookPayload is the JSON body POSTed to webhook URLs.
+type WebhookPayload struct {
+	Event      string    `json:"event" enum:"scan.completed,scan.failed"`
+	DeliveryID string    `json:"deliveryId"`
+	ScanID     string    `json:"scanId"`
+	OccurredAt time.Time `json:"occurredAt"`
+	// Exactly one of Result and Failure is set, depending on the event.
+	Result  *ScanOutputBody  `json:"result,omitempty"`
+	Failure *ScanFailedEvent `json:"failure,omitempty"`
+}
+
+// WebhookAttempt records one HTTP request of a delivery.
+type WebhookAttempt struct {
+	At         time.Time `bson:"at" json:"at"`
+	StatusCode int       `bson:"status_code,omitempty" json:"statusCode,omitempty" doc:"HTTP status returned by the receiver"`
+	Error      string    `bson:"error,omitempty" json:"error,omitempty" doc:"Network error or non-2xx status"`
+	DurationMS int64     `bson:"duration_ms" json:"durationMs"`
+}
+
+// WebhookDelivery is the log entry of one event sent to one URL, with all its attempts.
+type WebhookDelivery struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WebhookID string             `bson:"webhook_id,omitempty" json:"webhookId,omitempty" doc:"Registered webhook; empty for a per-scan webhook URL"`
+	OwnerID   string             `bson:"owner_id" json:"ownerId"`
+	URL       string             `bson:"url" json:"url"`
+	Secret    string             `bson:"secret" json:"-"` // Kept so the delivery can be replayed
+	Event     string             `bson:"event" json:"event"`
+	ScanID    string             `bson:"scan_id" json:"scanId"`
+	Payload   string             `bson:"payload" json:"payload" doc:"JSON body that was sent"`
+	Status    string             `bson:"status" json:"status" enum:"pending,succeeded,failed"`
+	Attempts  []WebhookAttempt   `bson:"attempts" json:"attempts"`
+	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updatedAt"`
+}
+
+// WebhookDeliveriesInput is the input structure for listing deliveries.
+type WebhookDeliveriesInput struct {
+	ScanID string `query:"scanId" doc:"Only deliveries for this scan"`
+	Limit  int    `query:"limit" minimum:"1" maximum:"100" default:"20"`
+}
+
+// WebhookDeliveryIDInput is the input structure for endpoints addressing a single delivery.
+type WebhookDeliveryIDInput struct {
+	ID string `path:"id" example:"654a93c7e0f2f3f4c5d6e7f8" doc:"Delivery ID"`
+}
+
+// WebhookDeliveryOutput is the output structure for returning a delivery.
+type WebhookDeliveryOutput struct {
+	Body WebhookDelivery
+}
+
+// WebhookDeliveriesOutput is the output structure for listing deliveries.
+type WebhookDeliveriesOutput struct {
+	Body struct {
+		Deliveries []WebhookDelivery `json:"deliveries" doc:"Deliveries, newest first"`
+	}
+}
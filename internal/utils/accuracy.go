@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// AccuracyText normalizes a text for measuring OCR accuracy: NFC, with runs of whitespace (including
+// line breaks) collapsed to single spaces, so layout differences do not count as recognition errors.
+func AccuracyText(text string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(text)), " ")
+}
+
+// EditDistance returns the Levenshtein distance between a and b: the fewest insertions, deletions and
+// substitutions turning a into b.
+func EditDistance[T comparable](a, b []T) int {
+	if len(a) < len(b) {
+		a, b = b, a // Keep the rows as short as the shorter sequence.
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// CharacterErrors returns the edit distance between the characters (code points) of the normalized
+// reference and hypothesis texts, and the number of characters of the reference. Their ratio is the
+// character error rate (CER). Devanagari vowel signs and viramas count as characters of their own.
+func CharacterErrors(reference, hypothesis string) (errors, characters int) {
+	ref, hyp := []rune(AccuracyText(reference)), []rune(AccuracyText(hypothesis))
+	return EditDistance(ref, hyp), len(ref)
+}
+
+// WordErrors returns the edit distance between the whitespace-separated words of the normalized
+// reference and hypothesis texts, and the number of words of the reference. Their ratio is the
+// word error rate (WER).
+func WordErrors(reference, hypothesis string) (errors, words int) {
+	ref, hyp := strings.Fields(AccuracyText(reference)), strings.Fields(AccuracyText(hypothesis))
+	return EditDistance(ref, hyp), len(ref)
+}
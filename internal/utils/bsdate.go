@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// Range of Bikram Sambat years covered by bsMonthDays.
+const (
+	BSMinYear = 2000
+	BSMaxYear = 2090
+)
+
+// bsEpoch is the Gregorian date of 2000-01-01 BS (1 Baishakh 2000).
+var bsEpoch = time.Date(1943, time.April, 14, 0, 0, 0, 0, time.UTC)
+
+// bsMonthDays holds the number of days in each month (Baishakh … Chaitra) of BS years
+// BSMinYear to BSMaxYear, as published in the official Nepali calendar (Nepal Panchanga Nirnayak Samiti).
+// Years after 2083 are projections and may shift by a day once the official calendar is published.
+var bsMonthDays = [BSMaxYear - BSMinYear + 1][12]int{
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2000
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2001
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2002
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2003
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2004
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2005
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2006
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2007
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 29, 31}, // 2008
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2009
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2010
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2011
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30}, // 2012
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2013
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2014
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2015
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30}, // 2016
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2017
+	{31, 32, 31, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2018
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2019
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30}, // 2020
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2021
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30}, // 2022
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2023
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30}, // 2024
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2025
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2026
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2027
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2028
+	{31, 31, 32, 31, 32, 30, 30, 29, 30, 29, 30, 30}, // 2029
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2030
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2031
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2032
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2033
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2034
+	{30, 32, 31, 32, 31, 31, 29, 30, 30, 29, 29, 31}, // 2035
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2036
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2037
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2038
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30}, // 2039
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2040
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2041
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2042
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30}, // 2043
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2044
+	{31, 32, 31, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2045
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2046
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30}, // 2047
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2048
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30}, // 2049
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2050
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30}, // 2051
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2052
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30}, // 2053
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2054
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2055
+	{31, 31, 32, 31, 32, 30, 30, 29, 30, 29, 30, 30}, // 2056
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2057
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2058
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2059
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2060
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2061
+	{30, 32, 31, 32, 31, 31, 29, 30, 29, 30, 29, 31}, // 2062
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2063
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2064
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2065
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 29, 31}, // 2066
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2067
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2068
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2069
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30}, // 2070
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2071
+	{31, 32, 31, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2072
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2073
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30}, // 2074
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2075
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30}, // 2076
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2077
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30}, // 2078
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2079
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30}, // 2080
+	{31, 31, 32, 32, 31, 30, 30, 30, 29, 30, 30, 30}, // 2081
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 30, 30}, // 2082
+	{31, 31, 32, 31, 31, 30, 30, 30, 29, 30, 30, 30}, // 2083
+	{31, 31, 32, 31, 31, 30, 30, 30, 29, 30, 30, 30}, // 2084
+	{31, 32, 31, 32, 30, 31, 30, 30, 29, 30, 30, 30}, // 2085
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 30, 30}, // 2086
+	{31, 31, 32, 31, 31, 31, 30, 30, 29, 30, 30, 30}, // 2087
+	{30, 31, 32, 32, 30, 31, 30, 30, 29, 30, 30, 30}, // 2088
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 30, 30}, // 2089
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 30, 30}, // 2090
+}
+
+// BSMonthNames are the Nepali names of the Bikram Sambat months, Baishakh first.
+var BSMonthNames = [12]string{"बैशाख", "जेठ", "असार", "साउन", "भदौ", "असोज", "कात्तिक", "मंसिर", "पुस", "माघ", "फागुन", "चैत"}
+
+// BSMonthNamesLatin are the romanized names of the Bikram Sambat months, Baishakh first.
+var BSMonthNamesLatin = [12]string{"Baishakh", "Jestha", "Asar", "Shrawan", "Bhadra", "Asoj", "Kartik", "Mangsir", "Poush", "Magh", "Falgun", "Chaitra"}
+
+// BSDate is a date in the Bikram Sambat calendar. Month is 1 (Baishakh) to 12 (Chaitra).
+type BSDate struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// String formats the date as YYYY-MM-DD.
+func (d BSDate) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// Validate reports whether the date exists in the bundled calendar table.
+func (d BSDate) Validate() error {
+	if d.Year < BSMinYear || d.Year > BSMaxYear {
+		return fmt.Errorf("BS year %d is outside the supported range %d-%d", d.Year, BSMinYear, BSMaxYear)
+	}
+	if d.Month < 1 || d.Month > 12 {
+		return fmt.Errorf("BS month must be 1-12, got %d", d.Month)
+	}
+	if days := BSDaysInMonth(d.Year, d.Month); d.Day < 1 || d.Day > days {
+		return fmt.Errorf("%s %d has %d days, got day %d", BSMonthNamesLatin[d.Month-1], d.Year, days, d.Day)
+	}
+	return nil
+}
+
+// BSDaysInMonth returns the number of days in a BS month, or 0 if the year or month is out of range.
+func BSDaysInMonth(year, month int) int {
+	if year < BSMinYear || year > BSMaxYear || month < 1 || month > 12 {
+		return 0
+	}
+	return bsMonthDays[year-BSMinYear][month-1]
+}
+
+// BSToAD converts a Bikram Sambat date to the Gregorian calendar (midnight UTC).
+func BSToAD(d BSDate) (time.Time, error) {
+	if err := d.Validate(); err != nil {
+		return time.Time{}, err
+	}
+
+	days := d.Day - 1
+	for y := BSMinYear; y < d.Year; y++ {
+		for _, n := range bsMonthDays[y-BSMinYear] {
+			days += n
+		}
+	}
+	for m := 1; m < d.Month; m++ {
+		days += bsMonthDays[d.Year-BSMinYear][m-1]
+	}
+	return bsEpoch.AddDate(0, 0, days), nil
+}
+
+// ADToBS converts a Gregorian date to Bikram Sambat. Only the calendar date of `t` is used.
+func ADToBS(t time.Time) (BSDate, error) {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(date.Sub(bsEpoch).Hours() / 24)
+	if days < 0 {
+		return BSDate{}, fmt.Errorf("date %s is before the supported range (from %s)", date.Format(time.DateOnly), bsEpoch.Format(time.DateOnly))
+	}
+
+	for y := BSMinYear; y <= BSMaxYear; y++ {
+		for m, n := range bsMonthDays[y-BSMinYear] {
+			if days < n {
+				return BSDate{Year: y, Month: m + 1, Day: days + 1}, nil
+			}
+			days -= n
+		}
+	}
+	return BSDate{}, fmt.Errorf("date %s is after the supported range (BS year %d)", date.Format(time.DateOnly), BSMaxYear)
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func adDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBSToAD(t *testing.T) {
+	tests := []struct {
+		name string
+		bs   BSDate
+		ad   time.Time
+	}{
+		{"first supported day", BSDate{2000, 1, 1}, adDate(1943, time.April, 14)},
+		{"last supported day", BSDate{2090, 12, 30}, adDate(2034, time.April, 13)},
+		{"last day of the first year", BSDate{2000, 12, 31}, adDate(1944, time.April, 12)},
+		{"first day of the second year", BSDate{2001, 1, 1}, adDate(1944, time.April, 13)},
+		{"last day of 2080", BSDate{2080, 12, 30}, adDate(2024, time.April, 12)},
+		{"new year 2081", BSDate{2081, 1, 1}, adDate(2024, time.April, 13)},
+		{"32-day month", BSDate{2081, 3, 32}, adDate(2024, time.July, 15)},
+		{"after a 32-day month", BSDate{2081, 4, 1}, adDate(2024, time.July, 16)},
+		{"across the AD new year", BSDate{2081, 9, 17}, adDate(2025, time.January, 1)},
+	}
+	for _, tt := range tests {
+		ad, err := BSToAD(tt.bs)
+		if err != nil || !ad.Equal(tt.ad) {
+			t.Errorf("%s: BSToAD(%s) = %s, %v; want %s", tt.name, tt.bs, ad.Format(time.DateOnly), err, tt.ad.Format(time.DateOnly))
+		}
+		bs, err := ADToBS(tt.ad)
+		if err != nil || bs != tt.bs {
+			t.Errorf("%s: ADToBS(%s) = %s, %v; want %s", tt.name, tt.ad.Format(time.DateOnly), bs, err, tt.bs)
+		}
+	}
+}
+
+func TestBSDateOutOfRange(t *testing.T) {
+	invalid := []BSDate{
+		{1999, 12, 30}, // Before the table
+		{2091, 1, 1},   // After the table
+		{2081, 0, 1},
+		{2081, 13, 1},
+		{2081, 1, 0},
+		{2081, 3, 33}, // Asar 2081 has 32 days
+		{2080, 12, 31},
+	}
+	for _, d := range invalid {
+		if err := d.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil, want an error", d)
+		}
+		if _, err := BSToAD(d); err == nil {
+			t.Errorf("%s: BSToAD succeeded, want an error", d)
+		}
+	}
+	if n := BSDaysInMonth(2091, 1); n != 0 {
+		t.Errorf("BSDaysInMonth(2091, 1) = %d, want 0", n)
+	}
+
+	for _, ad := range []time.Time{adDate(1943, time.April, 13), adDate(2034, time.April, 14)} {
+		if bs, err := ADToBS(ad); err == nil {
+			t.Errorf("ADToBS(%s) = %s, want an error", ad.Format(time.DateOnly), bs)
+		}
+	}
+	// Only the calendar date counts, not the time of day or zone.
+	late := time.Date(2024, time.April, 13, 23, 30, 0, 0, time.FixedZone("NPT", 5*3600+45*60))
+	if bs, err := ADToBS(late); err != nil || bs != (BSDate{2081, 1, 1}) {
+		t.Errorf("ADToBS(%s) = %s, %v; want 2081-01-01", late, bs, err)
+	}
+}
+
+func TestBSCalendarRoundTrip(t *testing.T) {
+	// Every day of the table converts to the next Gregorian day after its predecessor, and back.
+	want := adDate(1943, time.April, 14)
+	for year := BSMinYear; year <= BSMaxYear; year++ {
+		for month := 1; month <= 12; month++ {
+			days := BSDaysInMonth(year, month)
+			if days < 29 || days > 32 {
+				t.Fatalf("%s %d has %d days", BSMonthNamesLatin[month-1], year, days)
+			}
+			for day := 1; day <= days; day++ {
+				bs := BSDate{year, month, day}
+				ad, err := BSToAD(bs)
+				if err != nil || !ad.Equal(want) {
+					t.Fatalf("BSToAD(%s) = %s, %v; want %s", bs, ad.Format(time.DateOnly), err, want.Format(time.DateOnly))
+				}
+				if back, err := ADToBS(ad); err != nil || back != bs {
+					t.Fatalf("ADToBS(%s) = %s, %v; want %s", ad.Format(time.DateOnly), back, err, bs)
+				}
+				want = want.AddDate(0, 0, 1)
+			}
+		}
+	}
+}
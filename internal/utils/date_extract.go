@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Calendars reported by ExtractDates.
+const (
+	CalendarBS = "BS" // Bikram Sambat
+	CalendarAD = "AD" // Gregorian
+)
+
+// DateMatch is a date found in text, with its value in both calendars.
+// BS or AD is the zero value when the date falls outside the range of the bundled BS table.
+type DateMatch struct {
+	Text     string    // Matched text, as written
+	Start    int       // Byte offset of the match in the input
+	End      int       // Byte offset just after the match
+	Calendar string    // Calendar the date is written in (CalendarBS or CalendarAD)
+	BS       BSDate    // Date in Bikram Sambat
+	AD       time.Time // Date in the Gregorian calendar (midnight UTC)
+}
+
+// bsMonthSpellings maps spellings of BS month names (formal, colloquial and romanized, lowercased) to month numbers.
+var bsMonthSpellings = map[string]int{
+	"वैशाख": 1, "बैशाख": 1, "बैसाख": 1, "baishakh": 1, "baisakh": 1, "vaishakh": 1,
+	"जेठ": 2, "जेष्ठ": 2, "ज्येष्ठ": 2, "jestha": 2, "jeth": 2, "jeshtha": 2,
+	"असार": 3, "आषाढ": 3, "असाढ": 3, "asar": 3, "ashadh": 3, "asadh": 3,
+	"साउन": 4, "श्रावण": 4, "shrawan": 4, "saun": 4, "sawan": 4, "shravan": 4,
+	"भदौ": 5, "भाद्र": 5, "bhadra": 5, "bhadau": 5,
+	"असोज": 6, "आश्विन": 6, "असोझ": 6, "asoj": 6, "ashwin": 6, "aswin": 6,
+	"कात्तिक": 7, "कार्तिक": 7, "kartik": 7, "kattik": 7,
+	"मंसिर": 8, "मङ्सिर": 8, "मार्ग": 8, "मार्गशीर्ष": 8, "mangsir": 8, "marga": 8,
+	"पुस": 9, "पौष": 9, "पूस": 9, "poush": 9, "push": 9, "paush": 9,
+	"माघ": 10, "magh": 10,
+	"फागुन": 11, "फाल्गुन": 11, "falgun": 11, "phalgun": 11, "fagun": 11,
+	"चैत": 12, "चैत्र": 12, "chaitra": 12, "chait": 12,
+}
+
+// adMonthSpellings maps lowercased English month names and abbreviations to month numbers.
+var adMonthSpellings = map[string]int{
+	"january": 1, "jan": 1, "february": 2, "feb": 2, "march": 3, "mar": 3, "april": 4, "apr": 4,
+	"may": 5, "june": 6, "jun": 6, "july": 7, "jul": 7, "august": 8, "aug": 8,
+	"september": 9, "sep": 9, "sept": 9, "october": 10, "oct": 10, "november": 11, "nov": 11,
+	"december": 12, "dec": 12,
+}
+
+// Calendar markers that may precede or follow a date (e.g., "वि.सं. २०८१/०३/१५", "2024-06-29 A.D.").
+var (
+	bsMarker = regexp.MustCompile(`(?i)(वि\.?\s?सं\.?|बि\.?\s?सं\.?|विक्रम\s*संवत्?|\bB\.?\s?S\.?|\bV\.?\s?S\.?)`)
+	adMarker = regexp.MustCompile(`(?i)(ई\.?\s?सं\.?|इ\.?\s?सं\.?|ईस्वी\s*संवत्?|\bA\.?\s?D\.?|\bC\.?\s?E\.?)`)
+)
+
+// datePattern is one textual date layout. The named groups y, m (number) or mon (name) and d locate the parts.
+type datePattern struct {
+	re       *regexp.Regexp
+	calendar string // Fixed calendar implied by the layout, or "" to infer it
+}
+
+// digit matches an ASCII or Devanagari digit.
+const digit = `[0-9०-९]`
+
+var datePatterns = []datePattern{
+	// 2081/03/15, २०८१-०३-१५, 2024.06.29
+	{re: regexp.MustCompile(`(?P<y>` + digit + `{4})\s?[/.-]\s?(?P<m>` + digit + `{1,2})\s?[/.-]\s?(?P<d>` + digit + `{1,2})`)},
+	// 15/03/2081, २९-०६-२०२४ (day first, as written in Nepal)
+	{re: regexp.MustCompile(`(?P<d>` + digit + `{1,2})\s?[/.-]\s?(?P<m>` + digit + `{1,2})\s?[/.-]\s?(?P<y>` + digit + `{4})`)},
+	// २०८१ साल असार १५ गते, 2081 Asar 15
+	{re: regexp.MustCompile(`(?i)(?P<y>` + digit + `{4})\s*(?:साल\s*)?,?\s*(?P<mon>[\p{L}\p{M}]+)\s*(?:महिना\s*)?(?P<d>` + digit + `{1,2})(?:\s*गते)?`), calendar: CalendarBS},
+	// १५ असार २०८१, 15 Asar, 2081, 29th June 2024
+	{re: regexp.MustCompile(`(?i)(?P<d>` + digit + `{1,2})(?:st|nd|rd|th)?\s*(?:गते\s*)?(?P<mon>[\p{L}\p{M}]+)\.?,?\s*(?P<y>` + digit + `{4})`)},
+	// June 29, 2024
+	{re: regexp.MustCompile(`(?i)(?P<mon>[\p{L}]+)\.?\s+(?P<d>` + digit + `{1,2})(?:st|nd|rd|th)?,?\s+(?P<y>` + digit + `{4})`), calendar: CalendarAD},
+}
+
+// ExtractDates finds Bikram Sambat and Gregorian dates in (OCR) text and converts each to the other calendar.
+// Numeric dates are assigned a calendar from a nearby marker (वि.सं., B.S., ई.सं., A.D.), otherwise from
+// which calendar the date is valid in, Devanagari digits (BS) and the year (2035 or later is read as BS).
+// Matches are returned in text order and never overlap.
+func ExtractDates(text string) []DateMatch {
+	var matches []DateMatch
+	for _, pattern := range datePatterns {
+		for _, loc := range pattern.re.FindAllStringSubmatchIndex(text, -1) {
+			if !standsAlone(text, loc[0], loc[1]) {
+				continue
+			}
+			if match, ok := parseDateMatch(text, pattern, loc); ok {
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	// Keep the earliest (then longest) match where several patterns matched overlapping text.
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+	var result []DateMatch
+	for _, match := range matches {
+		if len(result) > 0 && match.Start < result[len(result)-1].End {
+			continue
+		}
+		result = append(result, match)
+	}
+	return result
+}
+
+// parseDateMatch converts one regexp match into a DateMatch, rejecting impossible dates.
+func parseDateMatch(text string, pattern datePattern, loc []int) (DateMatch, bool) {
+	group := func(name string) string {
+		i := pattern.re.SubexpIndex(name)
+		if i < 0 || loc[2*i] < 0 {
+			return ""
+		}
+		return text[loc[2*i]:loc[2*i+1]]
+	}
+
+	year, day := parseDigits(group("y")), parseDigits(group("d"))
+	month, calendar := 0, pattern.calendar
+	if name := strings.ToLower(group("mon")); name != "" {
+		if m, ok := bsMonthSpellings[name]; ok && calendar != CalendarAD {
+			month, calendar = m, CalendarBS
+		} else if m, ok := adMonthSpellings[name]; ok && calendar != CalendarBS {
+			month, calendar = m, CalendarAD
+		} else {
+			return DateMatch{}, false
+		}
+	} else {
+		month = parseDigits(group("m"))
+	}
+
+	match := DateMatch{Text: text[loc[0]:loc[1]], Start: loc[0], End: loc[1]}
+	bs := BSDate{Year: year, Month: month, Day: day}
+	bsValid := bs.Validate() == nil
+	ad := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	adValid := month >= 1 && month <= 12 && ad.Day() == day && ad.Month() == time.Month(month) && year >= 1900 && year <= 2100
+
+	if calendar == "" {
+		calendar = inferCalendar(text, loc[0], loc[1], match.Text, year, bsValid, adValid)
+	}
+
+	switch calendar {
+	case CalendarBS:
+		if !bsValid {
+			return DateMatch{}, false
+		}
+		match.Calendar, match.BS = CalendarBS, bs
+		match.AD, _ = BSToAD(bs)
+	case CalendarAD:
+		if !adValid {
+			return DateMatch{}, false
+		}
+		match.Calendar, match.AD = CalendarAD, ad
+		match.BS, _ = ADToBS(ad) // Zero value outside the BS table range.
+	default:
+		return DateMatch{}, false
+	}
+	return match, true
+}
+
+// inferCalendar decides which calendar a numeric date is written in. It returns "" if neither fits.
+func inferCalendar(text string, start, end int, matched string, year int, bsValid, adValid bool) string {
+	// An explicit marker right before or after the date wins.
+	before := text[max(0, start-24):start]
+	after := text[end:min(len(text), end+24)]
+	switch {
+	case bsMarker.MatchString(before) || bsMarker.MatchString(after):
+		return CalendarBS
+	case adMarker.MatchString(before) || adMarker.MatchString(after):
+		return CalendarAD
+	}
+
+	switch {
+	case bsValid && !adValid:
+		return CalendarBS
+	case adValid && !bsValid:
+		return CalendarAD
+	case !bsValid && !adValid:
+		return ""
+	}
+	// Valid in both calendars: Nepali documents write BS dates in Devanagari digits,
+	// and a Gregorian year of 2035 or later is implausible in a scanned document.
+	if strings.IndexFunc(matched, isDevanagariDigit) >= 0 || year >= 2035 {
+		return CalendarBS
+	}
+	return CalendarAD
+}
+
+// parseDigits parses a run of ASCII and/or Devanagari digits.
+func parseDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			n = n*10 + int(r-'0')
+		case isDevanagariDigit(r):
+			n = n*10 + int(r-devanagariZero)
+		}
+	}
+	return n
+}
+
+// standsAlone reports whether text[start:end] is not part of a longer number or word,
+// e.g., the "2081/03/15" inside "12081/03/155".
+func standsAlone(text string, start, end int) bool {
+	isAlnum := func(r rune) bool {
+		return r >= '0' && r <= '9' || isDevanagariDigit(r) || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+	}
+	if r, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isAlnum(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isAlnum(r) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtractDates(t *testing.T) {
+	type date struct {
+		text     string
+		calendar string
+		bs       BSDate
+		ad       time.Time
+	}
+	tests := []struct {
+		name string
+		text string
+		want []date
+	}{
+		{"BS marker", "वि.सं. २०८१/०३/१५ मा पारित", []date{
+			{"२०८१/०३/१५", CalendarBS, BSDate{2081, 3, 15}, adDate(2024, time.June, 28)},
+		}},
+		{"AD marker", "Signed on 2024-06-29 A.D.", []date{
+			{"2024-06-29", CalendarAD, BSDate{2081, 3, 16}, adDate(2024, time.June, 29)},
+		}},
+		{"Devanagari digits are BS", "मिति २०८०-१२-३०", []date{
+			{"२०८०-१२-३०", CalendarBS, BSDate{2080, 12, 30}, adDate(2024, time.April, 12)},
+		}},
+		{"32nd day is only BS", "2081/03/32", []date{
+			{"2081/03/32", CalendarBS, BSDate{2081, 3, 32}, adDate(2024, time.July, 15)},
+		}},
+		{"day first", "Issued 15/03/2081", []date{
+			{"15/03/2081", CalendarBS, BSDate{2081, 3, 15}, adDate(2024, time.June, 28)},
+		}},
+		{"BS month name", "२०८१ साल असार १५ गते", []date{
+			{"२०८१ साल असार १५ गते", CalendarBS, BSDate{2081, 3, 15}, adDate(2024, time.June, 28)},
+		}},
+		{"day before BS month name", "१ बैशाख २०८१", []date{
+			{"१ बैशाख २०८१", CalendarBS, BSDate{2081, 1, 1}, adDate(2024, time.April, 13)},
+		}},
+		{"AD month name", "29th June 2024 and June 30, 2024", []date{
+			{"29th June 2024", CalendarAD, BSDate{2081, 3, 16}, adDate(2024, time.June, 29)},
+			{"June 30, 2024", CalendarAD, BSDate{2081, 3, 17}, adDate(2024, time.June, 30)},
+		}},
+		{"first supported BS day", "2000 Baishakh 1", []date{
+			{"2000 Baishakh 1", CalendarBS, BSDate{2000, 1, 1}, adDate(1943, time.April, 14)},
+		}},
+		{"last supported BS day", "2090 Chaitra 30", []date{
+			{"2090 Chaitra 30", CalendarBS, BSDate{2090, 12, 30}, adDate(2034, time.April, 13)},
+		}},
+		{"AD outside the BS table", "1 January 1920", []date{
+			{"1 January 1920", CalendarAD, BSDate{}, adDate(1920, time.January, 1)},
+		}},
+		{"BS year outside the table", "2091 Baishakh 1", nil},
+		{"impossible BS day", "2081 Asar 33", nil},
+		{"impossible date in both calendars", "2081/13/01", nil},
+		{"part of a longer number", "12081/03/155", nil},
+	}
+	for _, tt := range tests {
+		got := ExtractDates(tt.text)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: ExtractDates(%q) = %+v, want %d date(s)", tt.name, tt.text, got, len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			g := got[i]
+			if g.Text != want.text || tt.text[g.Start:g.End] != want.text || g.Calendar != want.calendar || g.BS != want.bs || !g.AD.Equal(want.ad) {
+				t.Errorf("%s: date %d = %q %s BS %s AD %s, want %q %s BS %s AD %s", tt.name, i, g.Text, g.Calendar, g.BS,
+					g.AD.Format(time.DateOnly), want.text, want.calendar, want.bs, want.ad.Format(time.DateOnly))
+			}
+		}
+	}
+}
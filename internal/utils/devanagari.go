@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Devanagari code points used by the OCR post-processing helpers.
+const (
+	devanagariVirama = '\u094D' // ्  (halant)
+	devanagariDanda  = '\u0964' // ।
+	devanagariDouble = '\u0965' // ॥
+	devanagariZero   = '\u0966' // ०
+	zeroWidthJoiner  = '\u200D'
+	zeroWidthNonJoin = '\u200C'
+	zeroWidthSpace   = '\u200B'
+	byteOrderMark    = '\uFEFF'
+)
+
+// Digit conversion modes accepted by ConvertDigits.
+const (
+	DigitsUnchanged  = ""           // Leave digits as recognized
+	DigitsASCII      = "ascii"      // ०-९ → 0-9
+	DigitsDevanagari = "devanagari" // 0-9 → ०-९
+)
+
+// IsDevanagari reports whether r is in the Devanagari Unicode block.
+func IsDevanagari(r rune) bool {
+	return r >= 0x0900 && r <= 0x097F
+}
+
+// isDevanagariDigit reports whether r is one of ०-९.
+func isDevanagariDigit(r rune) bool {
+	return r >= devanagariZero && r <= devanagariZero+9
+}
+
+// isDevanagariSign reports whether r is a dependent vowel sign or modifier (matra, anusvara, nukta, ...)
+// that should never appear twice in a row.
+func isDevanagariSign(r rune) bool {
+	return (r >= 0x0900 && r <= 0x0903) || r == 0x093C || (r >= 0x093E && r <= 0x094D) || (r >= 0x0962 && r <= 0x0963)
+}
+
+// NormalizeDevanagari applies Unicode NFC normalization and removes invisible characters
+// that Tesseract leaves behind: zero-width spaces, byte-order marks, and ZWJ/ZWNJ that do
+// not follow a virama (the only position where they change how a conjunct is rendered).
+func NormalizeDevanagari(text string) string {
+	text = norm.NFC.String(text)
+
+	var b strings.Builder
+	b.Grow(len(text))
+	var prev rune
+	for _, r := range text {
+		switch r {
+		case zeroWidthSpace, byteOrderMark:
+			continue
+		case zeroWidthJoiner, zeroWidthNonJoin:
+			if prev != devanagariVirama {
+				continue
+			}
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// ConvertDigits converts digits between Devanagari (०-९) and ASCII (0-9) according to `mode`
+// (DigitsASCII or DigitsDevanagari). Any other mode returns the text unchanged.
+func ConvertDigits(text, mode string) string {
+	switch mode {
+	case DigitsASCII:
+		return strings.Map(func(r rune) rune {
+			if isDevanagariDigit(r) {
+				return '0' + (r - devanagariZero)
+			}
+			return r
+		}, text)
+	case DigitsDevanagari:
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return devanagariZero + (r - '0')
+			}
+			return r
+		}, text)
+	default:
+		return text
+	}
+}
+
+var (
+	// hyphenatedBreak matches a word split by a hyphen at the end of a line ("regu-\nlation").
+	hyphenatedBreak = regexp.MustCompile(`([\p{L}\p{M}])-[ \t]*\r?\n[ \t]*([\p{L}\p{M}])`)
+	// horizontalSpaceRun matches runs of spaces and tabs.
+	horizontalSpaceRun = regexp.MustCompile(`[ \t]{2,}`)
+	// trailingLineSpace matches spaces and tabs at the end of a line.
+	trailingLineSpace = regexp.MustCompile(`[ \t]+\r?\n`)
+	// blankLineRun matches three or more consecutive line breaks (two or more blank lines).
+	blankLineRun = regexp.MustCompile(`\n{3,}`)
+)
+
+// RepairLineBreaks re-joins words hyphenated across line breaks, collapses repeated spaces,
+// strips trailing whitespace on each line and limits blank lines to one between paragraphs.
+// When `preserveSpaces` is true, runs of spaces are kept (for column-aligned forms).
+func RepairLineBreaks(text string, preserveSpaces bool) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = hyphenatedBreak.ReplaceAllString(text, "$1$2")
+	text = trailingLineSpace.ReplaceAllString(text, "\n")
+	if !preserveSpaces {
+		text = horizontalSpaceRun.ReplaceAllString(text, " ")
+	}
+	text = blankLineRun.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// FixDevanagariConfusions corrects character confusions Tesseract commonly makes on Nepali text:
+//   - '|' (or 'I', 'l') used as a danda after Devanagari text becomes '।', and '।।' becomes '॥'
+//   - Latin 'o'/'O' inside a Devanagari number becomes '०' (e.g., "२o८१/o३" → "२०८१/०३")
+//   - repeated vowel signs or modifiers ("ाा", "््") are collapsed to one
+func FixDevanagariConfusions(text string) string {
+	runes := []rune(text)
+	out := make([]rune, 0, len(runes))
+
+	for i, r := range runes {
+		prev := lastNonSpace(out)
+
+		switch {
+		case (r == '|' || r == 'I' || r == 'l') && IsDevanagari(prev) && !isDevanagariDigit(prev) && isWordEnd(runes, i):
+			r = devanagariDanda
+		case (r == 'o' || r == 'O') && isInDevanagariNumber(out, runes, i):
+			r = devanagariZero
+		}
+
+		if len(out) > 0 {
+			last := out[len(out)-1]
+			// Collapse duplicated signs ("ाा" → "ा").
+			if r == last && isDevanagariSign(r) {
+				continue
+			}
+			// Merge two dandas into a double danda.
+			if r == devanagariDanda && last == devanagariDanda {
+				out[len(out)-1] = devanagariDouble
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// isInDevanagariNumber reports whether the Latin 'o'/'O' at index i sits inside a Devanagari number:
+// it touches a Devanagari digit (or another 'o') on one side and no Latin letter on either side.
+func isInDevanagariNumber(out, runes []rune, i int) bool {
+	var prev, next rune
+	if len(out) > 0 {
+		prev = out[len(out)-1]
+	}
+	if i+1 < len(runes) {
+		next = runes[i+1]
+	}
+	isZeroLike := func(r rune) bool { return r == 'o' || r == 'O' }
+	isLatinLetter := func(r rune) bool { return r < 0x80 && unicode.IsLetter(r) && !isZeroLike(r) }
+	if isLatinLetter(prev) || isLatinLetter(next) {
+		return false
+	}
+	return isDevanagariDigit(prev) || isDevanagariDigit(next) || (isZeroLike(next) && i+2 < len(runes) && isDevanagariDigit(runes[i+2]))
+}
+
+// lastNonSpace returns the last non-space rune of `runes`, or 0 if there is none.
+func lastNonSpace(runes []rune) rune {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if runes[i] != ' ' && runes[i] != '\t' {
+			return runes[i]
+		}
+	}
+	return 0
+}
+
+// isWordEnd reports whether the rune at index i is followed by whitespace, punctuation or the end of text.
+func isWordEnd(runes []rune, i int) bool {
+	if i+1 >= len(runes) {
+		return true
+	}
+	next := runes[i+1]
+	return unicode.IsSpace(next) || unicode.IsPunct(next) || next == devanagariDanda || next == '|'
+}
@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+)
+
+// Image formats accepted for OCR, as returned by DetectImageFormat.
+const (
+	ImageFormatPNG  = "png"
+	ImageFormatJPEG = "jpeg"
+	ImageFormatTIFF = "tiff"
+	ImageFormatWebP = "webp"
+	ImageFormatBMP  = "bmp"
+)
+
+// imageFormatMIMETypes maps each supported format to its MIME type.
+var imageFormatMIMETypes = map[string]string{
+	ImageFormatPNG:  "image/png",
+	ImageFormatJPEG: "image/jpeg",
+	ImageFormatTIFF: "image/tiff",
+	ImageFormatWebP: "image/webp",
+	ImageFormatBMP:  "image/bmp",
+}
+
+// ImageFormatMIMEType returns the MIME type of a format returned by DetectImageFormat.
+func ImageFormatMIMEType(format string) string {
+	return imageFormatMIMETypes[format]
+}
+
+// DetectImageFormat identifies an image by its magic bytes, ignoring any declared content type.
+// It returns "" for data that is not one of the supported formats.
+func DetectImageFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return ImageFormatPNG
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return ImageFormatJPEG
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		return ImageFormatTIFF
+	case len(data) >= 12 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return ImageFormatWebP
+	case bytes.HasPrefix(data, []byte("BM")) && len(data) >= 26:
+		return ImageFormatBMP
+	}
+	return ""
+}
+
+// ImageDimensions reads the width and height of an image from its header, without decoding
+// the pixels, so oversized images can be rejected before they are decompressed.
+func ImageDimensions(data []byte, format string) (width, height int, err error) {
+	switch format {
+	case ImageFormatPNG, ImageFormatJPEG:
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid %s header: %w", format, err)
+		}
+		return cfg.Width, cfg.Height, nil
+	case ImageFormatTIFF:
+		return tiffDimensions(data)
+	case ImageFormatWebP:
+		return webpDimensions(data)
+	case ImageFormatBMP:
+		return bmpDimensions(data)
+	}
+	return 0, 0, fmt.Errorf("unsupported image format %q", format)
+}
+
+// maxTIFFDirectories bounds how many pages (image file directories) of a TIFF are read.
+const maxTIFFDirectories = 64
+
+// tiffDimensions reads the ImageWidth and ImageLength tags of every TIFF directory (page). Since each
+// page is recognized, it returns the largest width and the largest height of any page.
+func tiffDimensions(data []byte) (int, int, error) {
+	if len(data) < 8 {
+		return 0, 0, fmt.Errorf("truncated TIFF header")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if data[0] == 'M' {
+		order = binary.BigEndian
+	}
+
+	maxWidth, maxHeight := 0, 0
+	seen := map[int]bool{}
+	for ifd := int(order.Uint32(data[4:8])); ifd != 0; {
+		if len(seen) == maxTIFFDirectories {
+			return 0, 0, fmt.Errorf("TIFF has more than %d pages", maxTIFFDirectories)
+		}
+		if seen[ifd] {
+			return 0, 0, fmt.Errorf("TIFF directories form a loop")
+		}
+		seen[ifd] = true
+
+		width, height, next, err := tiffDirectory(data, order, ifd)
+		if err != nil {
+			return 0, 0, err
+		}
+		maxWidth, maxHeight = max(maxWidth, width), max(maxHeight, height)
+		ifd = next
+	}
+	if len(seen) == 0 {
+		return 0, 0, fmt.Errorf("invalid TIFF directory offset")
+	}
+	return maxWidth, maxHeight, nil
+}
+
+// tiffDirectory reads the image size from the TIFF directory at offset `ifd` and returns the offset
+// of the next directory (0 after the last).
+func tiffDirectory(data []byte, order binary.ByteOrder, ifd int) (width, height, next int, err error) {
+	if ifd < 8 || ifd+2 > len(data) {
+		return 0, 0, 0, fmt.Errorf("invalid TIFF directory offset")
+	}
+	entries := int(order.Uint16(data[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(data) {
+			return 0, 0, 0, fmt.Errorf("truncated TIFF directory")
+		}
+		tag, fieldType := order.Uint16(data[entry:entry+2]), order.Uint16(data[entry+2:entry+4])
+		var value int
+		switch fieldType {
+		case 3: // SHORT
+			value = int(order.Uint16(data[entry+8 : entry+10]))
+		case 4: // LONG
+			value = int(order.Uint32(data[entry+8 : entry+12]))
+		default:
+			continue
+		}
+		switch tag {
+		case 256: // ImageWidth
+			width = value
+		case 257: // ImageLength
+			height = value
+		}
+	}
+	if width == 0 || height == 0 {
+		return 0, 0, 0, fmt.Errorf("TIFF image has no dimensions")
+	}
+	nextOffset := ifd + 2 + entries*12
+	if nextOffset+4 > len(data) {
+		return 0, 0, 0, fmt.Errorf("truncated TIFF directory")
+	}
+	return width, height, int(order.Uint32(data[nextOffset : nextOffset+4])), nil
+}
+
+// webpDimensions reads the canvas size from the first chunk of a WebP file (lossy, lossless or extended).
+func webpDimensions(data []byte) (int, int, error) {
+	if len(data) < 30 {
+		return 0, 0, fmt.Errorf("truncated WebP header")
+	}
+	switch string(data[12:16]) {
+	case "VP8 ":
+		if !bytes.Equal(data[23:26], []byte{0x9d, 0x01, 0x2a}) {
+			return 0, 0, fmt.Errorf("invalid VP8 frame header")
+		}
+		return int(binary.LittleEndian.Uint16(data[26:28]) & 0x3fff), int(binary.LittleEndian.Uint16(data[28:30]) & 0x3fff), nil
+	case "VP8L":
+		if data[20] != 0x2f {
+			return 0, 0, fmt.Errorf("invalid VP8L signature")
+		}
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, nil
+	case "VP8X":
+		width := int(data[24]) | int(data[25])<<8 | int(data[26])<<16
+		height := int(data[27]) | int(data[28])<<8 | int(data[29])<<16
+		return width + 1, height + 1, nil
+	}
+	return 0, 0, fmt.Errorf("unknown WebP chunk %q", data[12:16])
+}
+
+// bmpDimensions reads the size from the BMP info header; negative heights mark top-down bitmaps.
+func bmpDimensions(data []byte) (int, int, error) {
+	if len(data) < 26 {
+		return 0, 0, fmt.Errorf("truncated BMP header")
+	}
+	if binary.LittleEndian.Uint32(data[14:18]) == 12 { // OS/2 BITMAPCOREHEADER
+		return int(binary.LittleEndian.Uint16(data[18:20])), int(binary.LittleEndian.Uint16(data[20:22])), nil
+	}
+	width := int(int32(binary.LittleEndian.Uint32(data[18:22])))
+	height := int(int32(binary.LittleEndian.Uint32(data[22:26])))
+	if height < 0 {
+		height = -height
+	}
+	if width <= 0 || height == 0 {
+		return 0, 0, fmt.Errorf("invalid BMP dimensions")
+	}
+	return width, height, nil
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// tiffPages builds a little-endian TIFF header and one directory per page with only the ImageWidth
+// and ImageLength tags. If loop is true, the last directory points back to the first.
+func tiffPages(loop bool, sizes ...[2]int) []byte {
+	data := []byte("II*\x00")
+	data = binary.LittleEndian.AppendUint32(data, 8)
+	for i, size := range sizes {
+		data = binary.LittleEndian.AppendUint16(data, 2)
+		for tag, value := range size { // ImageWidth (256), then ImageLength (257)
+			data = binary.LittleEndian.AppendUint16(data, uint16(256+tag))
+			data = binary.LittleEndian.AppendUint16(data, 4) // LONG
+			data = binary.LittleEndian.AppendUint32(data, 1)
+			data = binary.LittleEndian.AppendUint32(data, uint32(value))
+		}
+		next := uint32(len(data) + 4)
+		switch {
+		case loop && i == len(sizes)-1:
+			next = 8
+		case i == len(sizes)-1:
+			next = 0
+		}
+		data = binary.LittleEndian.AppendUint32(data, next)
+	}
+	return data
+}
+
+func TestTIFFDimensions(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          []byte
+		width, height int
+		err           string
+	}{
+		{"one page", tiffPages(false, [2]int{2480, 3508}), 2480, 3508, ""},
+		{"later page is larger", tiffPages(false, [2]int{100, 100}, [2]int{50000, 80}, [2]int{90, 60000}), 50000, 60000, ""},
+		{"directory loop", tiffPages(true, [2]int{100, 100}, [2]int{100, 100}), 0, 0, "loop"},
+		{"truncated", tiffPages(false, [2]int{100, 100})[:20], 0, 0, "truncated"},
+	}
+	for _, tt := range tests {
+		width, height, err := ImageDimensions(tt.data, DetectImageFormat(tt.data))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: got %dx%d, %v; want an error containing %q", tt.name, width, height, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || width != tt.width || height != tt.height {
+			t.Errorf("%s: got %dx%d, %v; want %dx%d", tt.name, width, height, err, tt.width, tt.height)
+		}
+	}
+
+	// Pages past the bound are not read, however small.
+	pages := make([][2]int, maxTIFFDirectories+1)
+	for i := range pages {
+		pages[i] = [2]int{100, 100}
+	}
+	if _, _, err := tiffDimensions(tiffPages(false, pages...)); err == nil || !strings.Contains(err.Error(), "more than") {
+		t.Errorf("%d pages: got %v, want an error", len(pages), err)
+	}
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kinds of nodes produced by ParseLegalStructure, from the root down.
+const (
+	LegalNodeAct        = "act"
+	LegalNodePreamble   = "preamble"
+	LegalNodeChapter    = "chapter"    // परिच्छेद / Chapter / Part
+	LegalNodeSection    = "section"    // दफा / Section, e.g. "१. संक्षिप्त नाम र प्रारम्भः"
+	LegalNodeSubsection = "subsection" // उपदफा / Sub-section, e.g. "(१)"
+	LegalNodeClause     = "clause"     // खण्ड / Clause, e.g. "(क)" or "(a)"
+)
+
+// legalNodeLevels orders node kinds by depth; a node is attached to the nearest open node of a lower level.
+var legalNodeLevels = map[string]int{
+	LegalNodeAct:        0,
+	LegalNodePreamble:   1,
+	LegalNodeChapter:    1,
+	LegalNodeSection:    2,
+	LegalNodeSubsection: 3,
+	LegalNodeClause:     4,
+}
+
+// LegalNode is one element of a parsed legal document.
+type LegalNode struct {
+	Kind     string       // One of the LegalNode* kinds
+	Number   string       // Number or letter as written (e.g., "३", "5A", "क"); empty for act and preamble
+	Heading  string       // Title of the act or chapter, or the marginal heading of a section
+	Text     string       // Body text belonging directly to this node
+	Children []*LegalNode // Nested nodes, in document order
+}
+
+// Line patterns recognizing the start of each structural element.
+var (
+	// Act titles end with the kind of instrument and usually its year: "मुलुकी देवानी संहिता ऐन, २०७४", "Companies Act, 2063".
+	actTitleLine = regexp.MustCompile(`(?i)(ऐन|संहिता|नियमावली|नियम|विनियमावली|निर्देशिका|कार्यविधि|आदेश|\bAct|\bCode|\bRules|\bRegulations?|\bOrder|\bDirectives?)\s*,?\s*([0-9०-९]{4})?\s*$`)
+	// "परिच्छेद–१", "परिच्छेद - २ प्रारम्भिक", "Chapter 3: Preliminary", "PART-II"
+	chapterLine = regexp.MustCompile(`(?i)^(?:परिच्छेद|भाग|chapter|part)\s*[-–—:.]?\s*([0-9०-९]+|[IVXLC]+\b)\s*[-–—:.]?\s*(.*)$`)
+	// "१. संक्षिप्त नाम र प्रारम्भः", "५क. परिभाषा:", "2. Definitions:- In this Act, ..."
+	sectionLine = regexp.MustCompile(`^([0-9०-९]+[क-हA-Z]?)\s*[.)]\s+(.*)$`)
+	// A section heading ends with a visarga or colon (optionally followed by a dash), ".-" or a spaced dash.
+	sectionHeadingEnd = regexp.MustCompile(`^([^ः:–—]{1,160}?)\s*(?:[ः:]\s*[-–—]?|\.\s*[-–—]|\s[–—])\s*(.*)$`)
+	// "(१) यो ऐनको नाम ...", "(2) ..."
+	subsectionLine = regexp.MustCompile(`^\(([0-9०-९]+[क-हa-z]?)\)\s*(.*)$`)
+	// "(क) ...", "(a) ..."
+	clauseLine = regexp.MustCompile(`^\(([क-ह][\x{093E}-\x{094C}]?[0-9०-९]?|[a-z])\)\s*(.*)$`)
+)
+
+// ParseLegalStructure segments the text of an act or rules into a hierarchy of
+// act → chapters (परिच्छेद) → sections (दफा) → sub-sections (उपदफा) → clauses (खण्ड),
+// following both Nepali and English numbering conventions. Lines that do not start a
+// new element are appended to the text of the most recent one; text between the title and
+// the first chapter or section becomes the preamble. The returned root is always an act node.
+func ParseLegalStructure(text string) *LegalNode {
+	root := &LegalNode{Kind: LegalNodeAct}
+	stack := []*LegalNode{root}
+	headingPending := false // The last chapter had no heading on its own line.
+
+	open := func(node *LegalNode) {
+		level := legalNodeLevels[node.Kind]
+		for len(stack) > 1 && legalNodeLevels[stack[len(stack)-1].Kind] >= level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, node)
+	}
+	appendText := func(line string) {
+		current := stack[len(stack)-1]
+		if current == root {
+			// Loose text before the first chapter or section belongs to the preamble.
+			current = &LegalNode{Kind: LegalNodePreamble}
+			open(current)
+		}
+		if current.Text == "" {
+			current.Text = line
+		} else {
+			current.Text += "\n" + line
+		}
+	}
+
+	for _, line := range strings.Split(NormalizeDevanagari(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// The title is the first line that names an instrument, or else the first line.
+		if root.Heading == "" && len(root.Children) == 0 && !chapterLine.MatchString(line) && !sectionLine.MatchString(line) {
+			root.Heading = line
+			continue
+		}
+
+		if m := chapterLine.FindStringSubmatch(line); m != nil {
+			open(&LegalNode{Kind: LegalNodeChapter, Number: m[1], Heading: strings.TrimSpace(m[2])})
+			headingPending = m[2] == ""
+			continue
+		}
+		if headingPending {
+			headingPending = false
+			if !sectionLine.MatchString(line) && !subsectionLine.MatchString(line) {
+				stack[len(stack)-1].Heading = line
+				continue
+			}
+		}
+
+		if m := sectionLine.FindStringSubmatch(line); m != nil {
+			section := &LegalNode{Kind: LegalNodeSection, Number: m[1]}
+			rest := m[2]
+			if h := sectionHeadingEnd.FindStringSubmatch(rest); h != nil {
+				section.Heading, rest = strings.TrimSpace(h[1]), h[2]
+			}
+			open(section)
+			parseLegalLine(rest, open, appendText)
+			continue
+		}
+		parseLegalLine(line, open, appendText)
+	}
+
+	// The first line is only kept as the title if it names an instrument;
+	// otherwise it was ordinary text and belongs to the preamble.
+	if root.Heading != "" && !actTitleLine.MatchString(root.Heading) {
+		if len(root.Children) > 0 && root.Children[0].Kind == LegalNodePreamble {
+			root.Children[0].Text = root.Heading + "\n" + root.Children[0].Text
+		} else {
+			preamble := &LegalNode{Kind: LegalNodePreamble, Text: root.Heading}
+			root.Children = append([]*LegalNode{preamble}, root.Children...)
+		}
+		root.Heading = ""
+	}
+	return root
+}
+
+// parseLegalLine opens a sub-section or clause if the line starts with one ("(१) ...", "(क) ..."),
+// and otherwise appends the line to the text of the current node.
+func parseLegalLine(line string, open func(*LegalNode), appendText func(string)) {
+	if line == "" {
+		return
+	}
+	if m := subsectionLine.FindStringSubmatch(line); m != nil {
+		open(&LegalNode{Kind: LegalNodeSubsection, Number: m[1], Text: strings.TrimSpace(m[2])})
+		return
+	}
+	if m := clauseLine.FindStringSubmatch(line); m != nil {
+		open(&LegalNode{Kind: LegalNodeClause, Number: m[1], Text: strings.TrimSpace(m[2])})
+		return
+	}
+	appendText(line)
+}
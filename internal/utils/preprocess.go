@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Grayscale converts img to 8-bit grayscale. JPEG images (YCbCr) use their luma plane directly.
+func Grayscale(img image.Image) *image.Gray {
+	bounds := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	switch src := img.(type) {
+	case *image.Gray:
+		for y := 0; y < bounds.Dy(); y++ {
+			copy(gray.Pix[y*gray.Stride:y*gray.Stride+bounds.Dx()], src.Pix[src.PixOffset(bounds.Min.X, bounds.Min.Y+y):])
+		}
+	case *image.YCbCr:
+		for y := 0; y < bounds.Dy(); y++ {
+			copy(gray.Pix[y*gray.Stride:y*gray.Stride+bounds.Dx()], src.Y[src.YOffset(bounds.Min.X, bounds.Min.Y+y):])
+		}
+	default:
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				gray.SetGray(x, y, color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray))
+			}
+		}
+	}
+	return gray
+}
+
+// OtsuThreshold returns the gray level that best separates dark (ink) from light (paper) pixels,
+// by maximizing the between-class variance of the histogram (Otsu's method).
+func OtsuThreshold(gray *image.Gray) uint8 {
+	var histogram [256]int
+	bounds := gray.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := gray.Pix[gray.PixOffset(bounds.Min.X, y):gray.PixOffset(bounds.Max.X, y)]
+		for _, v := range row {
+			histogram[v]++
+		}
+	}
+
+	total := bounds.Dx() * bounds.Dy()
+	var sumAll float64
+	for level, count := range histogram {
+		sumAll += float64(level * count)
+	}
+
+	var sumDark float64
+	var dark int
+	var best float64
+	threshold := uint8(127)
+	for level, count := range histogram {
+		dark += count
+		if dark == 0 {
+			continue
+		}
+		light := total - dark
+		if light == 0 {
+			break
+		}
+		sumDark += float64(level * count)
+		meanDark := sumDark / float64(dark)
+		meanLight := (sumAll - sumDark) / float64(light)
+		if variance := float64(dark) * float64(light) * (meanDark - meanLight) * (meanDark - meanLight); variance > best {
+			best, threshold = variance, uint8(level)
+		}
+	}
+	return threshold
+}
+
+// Binarize returns a black-and-white copy of gray: pixels at or below the Otsu threshold become black.
+func Binarize(gray *image.Gray) *image.Gray {
+	threshold := OtsuThreshold(gray)
+	bounds := gray.Bounds()
+	out := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			if gray.GrayAt(bounds.Min.X+x, bounds.Min.Y+y).Y > threshold {
+				out.Pix[y*out.Stride+x] = 255
+			}
+		}
+	}
+	return out
+}
+
+// Upscale enlarges gray by an integer factor with bilinear interpolation, which helps OCR on
+// low-resolution scans whose glyphs are only a few pixels tall.
+func Upscale(gray *image.Gray, factor int) *image.Gray {
+	bounds := gray.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	out := image.NewGray(image.Rect(0, 0, srcW*factor, srcH*factor))
+	at := func(x, y int) float64 {
+		x, y = min(max(x, 0), srcW-1), min(max(y, 0), srcH-1)
+		return float64(gray.Pix[gray.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)])
+	}
+	for y := 0; y < srcH*factor; y++ {
+		// Sample at pixel centers, so the output is not shifted by half a source pixel.
+		fy := (float64(y)+0.5)/float64(factor) - 0.5
+		y0 := int(fy)
+		if fy < 0 {
+			y0 = -1
+		}
+		wy := fy - float64(y0)
+		for x := 0; x < srcW*factor; x++ {
+			fx := (float64(x)+0.5)/float64(factor) - 0.5
+			x0 := int(fx)
+			if fx < 0 {
+				x0 = -1
+			}
+			wx := fx - float64(x0)
+			top := at(x0, y0)*(1-wx) + at(x0+1, y0)*wx
+			bottom := at(x0, y0+1)*(1-wx) + at(x0+1, y0+1)*wx
+			out.Pix[y*out.Stride+x] = uint8(top*(1-wy) + bottom*wy + 0.5)
+		}
+	}
+	return out
+}
+
+// EncodePNG encodes img as a PNG, the lossless format used for preprocessed images sent to OCR.
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
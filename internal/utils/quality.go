@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"encoding/binary"
+	"image"
+	"math"
+	"sort"
+)
+
+// ShrinkGray reduces gray by an integer factor, averaging each factor×factor block, so that its longer
+// side is at most maxSide pixels. It returns the reduced image and the factor (1 if it was small enough).
+func ShrinkGray(gray *image.Gray, maxSide int) (*image.Gray, int) {
+	bounds := gray.Bounds()
+	factor := (max(bounds.Dx(), bounds.Dy()) + maxSide - 1) / maxSide
+	if factor <= 1 {
+		return gray, 1
+	}
+	width, height := bounds.Dx()/factor, bounds.Dy()/factor
+	small := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sum := 0
+			for sy := 0; sy < factor; sy++ {
+				row := gray.Pix[gray.PixOffset(bounds.Min.X+x*factor, bounds.Min.Y+y*factor+sy):]
+				for sx := 0; sx < factor; sx++ {
+					sum += int(row[sx])
+				}
+			}
+			small.Pix[y*small.Stride+x] = uint8(sum / (factor * factor))
+		}
+	}
+	return small, factor
+}
+
+// LaplacianVariance returns the variance of the 4-neighbour Laplacian of gray, a standard focus measure:
+// sharp edges give large responses, blur smooths them out.
+func LaplacianVariance(gray *image.Gray) float64 {
+	bounds := gray.Bounds()
+	var sum, sumSquares float64
+	n := 0
+	for y := bounds.Min.Y + 1; y < bounds.Max.Y-1; y++ {
+		for x := bounds.Min.X + 1; x < bounds.Max.X-1; x++ {
+			i := gray.PixOffset(x, y)
+			v := float64(int(gray.Pix[i-1]) + int(gray.Pix[i+1]) + int(gray.Pix[i-gray.Stride]) + int(gray.Pix[i+gray.Stride]) - 4*int(gray.Pix[i]))
+			sum += v
+			sumSquares += v * v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	mean := sum / float64(n)
+	return sumSquares/float64(n) - mean*mean
+}
+
+// PaperAndInk returns the mean gray levels of the light (paper) and dark (ink) pixels, separated by
+// Otsu's threshold. Unlike the overall mean and deviation, they do not depend on how much text there is.
+func PaperAndInk(gray *image.Gray) (paper, ink float64) {
+	threshold := OtsuThreshold(gray)
+	bounds := gray.Bounds()
+	var light, dark [2]float64 // Sum and count
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for _, p := range gray.Pix[gray.PixOffset(bounds.Min.X, y):gray.PixOffset(bounds.Max.X, y)] {
+			if p <= threshold {
+				dark[0], dark[1] = dark[0]+float64(p), dark[1]+1
+			} else {
+				light[0], light[1] = light[0]+float64(p), light[1]+1
+			}
+		}
+	}
+	// A uniform image has no second class; it is all paper (or all ink) at that level.
+	if light[1] == 0 {
+		light = dark
+	}
+	if dark[1] == 0 {
+		dark = light
+	}
+	return light[0] / light[1], dark[0] / dark[1]
+}
+
+// inkPoints returns the coordinates of the dark pixels of gray (by Otsu's threshold), sampled down to
+// at most maxPoints.
+func inkPoints(gray *image.Gray, maxPoints int) []image.Point {
+	threshold := OtsuThreshold(gray)
+	bounds := gray.Bounds()
+	var points []image.Point
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if gray.Pix[gray.PixOffset(x, y)] <= threshold {
+				points = append(points, image.Pt(x-bounds.Min.X, y-bounds.Min.Y))
+			}
+		}
+	}
+	if len(points) <= maxPoints {
+		return points
+	}
+	step := float64(len(points)) / float64(maxPoints)
+	sampled := make([]image.Point, 0, maxPoints)
+	for i := 0.0; int(i) < len(points); i += step {
+		sampled = append(sampled, points[int(i)])
+	}
+	return sampled
+}
+
+// EstimateSkew returns the angle of the text lines of gray in degrees (within ±15), positive when the
+// lines rise to the right. It projects the ink onto rows sheared by candidate angles and picks the angle
+// whose row profile is most sharply peaked, as text lines then fall into the fewest rows.
+func EstimateSkew(gray *image.Gray) float64 {
+	points := inkPoints(gray, 50000)
+	if len(points) < 100 {
+		return 0
+	}
+	height := gray.Bounds().Dy()
+	width := gray.Bounds().Dx()
+	// Sheared rows range over the image height plus the shear across its width (16° covers the
+	// refinement around ±15°).
+	offset := int(float64(width)*math.Tan(16*math.Pi/180)) + 1
+	profile := make([]int, height+2*offset+1)
+	score := func(degrees float64) float64 {
+		clear(profile)
+		tan := math.Tan(degrees * math.Pi / 180)
+		for _, p := range points {
+			profile[p.Y+int(math.Round(float64(p.X)*tan))+offset]++
+		}
+		var sum float64
+		for _, n := range profile {
+			sum += float64(n) * float64(n)
+		}
+		return sum
+	}
+
+	search := func(from, to, step float64) float64 {
+		best, bestScore := 0.0, -1.0
+		for angle := from; angle <= to+step/2; angle += step {
+			if s := score(angle); s > bestScore {
+				best, bestScore = angle, s
+			}
+		}
+		return best
+	}
+	coarse := search(-15, 15, 0.5)
+	skew := math.Round(search(coarse-0.5, coarse+0.5, 0.1)*10) / 10
+	if skew == 0 {
+		return 0 // Not -0
+	}
+	return skew
+}
+
+// EstimateTextHeight returns the median height in pixels of the text lines of gray, found as bands of
+// rows containing ink, or 0 if there are none. Rows follow the lines' skew (see EstimateSkew).
+func EstimateTextHeight(gray *image.Gray, skew float64) int {
+	bounds := gray.Bounds()
+	tan := math.Tan(skew * math.Pi / 180)
+	offset := int(math.Abs(float64(bounds.Dx())*tan)) + 1
+	rows := make([]int, bounds.Dy()+2*offset+1)
+	for _, p := range inkPoints(gray, math.MaxInt) {
+		rows[p.Y+int(math.Round(float64(p.X)*tan))+offset]++
+	}
+
+	minInk := max(2, bounds.Dx()/100)
+	var bands []int
+	run := 0
+	for _, ink := range append(rows, 0) {
+		if ink >= minInk {
+			run++
+			continue
+		}
+		// Bands of a few rows are rules or noise; very tall ones are pictures.
+		if run >= 4 && run <= bounds.Dy()/10 {
+			bands = append(bands, run)
+		}
+		run = 0
+	}
+	if len(bands) == 0 {
+		return 0
+	}
+	sort.Ints(bands)
+	return bands[len(bands)/2]
+}
+
+// ImageDPI returns the resolution recorded in a PNG (pHYs chunk) or JPEG (JFIF header) file, or 0 if
+// there is none.
+func ImageDPI(data []byte, format string) int {
+	switch format {
+	case ImageFormatPNG:
+		// Chunks follow the 8-byte signature: length, type, data, CRC.
+		for i := 8; i+12 <= len(data); {
+			length := int(binary.BigEndian.Uint32(data[i:]))
+			kind := string(data[i+4 : i+8])
+			if kind == "pHYs" && length == 9 && i+17 <= len(data) {
+				if data[i+16] != 1 { // Unit is not the meter
+					return 0
+				}
+				return int(math.Round(float64(binary.BigEndian.Uint32(data[i+8:])) * 0.0254))
+			}
+			if kind == "IDAT" || length < 0 {
+				return 0 // pHYs must precede the image data.
+			}
+			i += 12 + length
+		}
+	case ImageFormatJPEG:
+		// The JFIF APP0 segment directly follows the SOI marker.
+		if len(data) >= 18 && data[2] == 0xFF && data[3] == 0xE0 && string(data[6:11]) == "JFIF\x00" {
+			density := int(binary.BigEndian.Uint16(data[14:]))
+			switch data[13] {
+			case 1: // Dots per inch
+				return density
+			case 2: // Dots per centimeter
+				return int(math.Round(float64(density) * 2.54))
+			}
+		}
+	}
+	return 0
+}
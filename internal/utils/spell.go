@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// spellPrefixLength bounds the deletes generated per dictionary word to those of its first runes, as in
+// SymSpell. Candidates are still verified on the whole word, so no match is lost, only memory saved.
+const spellPrefixLength = 7
+
+// SpellDictionary finds the most frequent known word closest to a misspelled one with the symmetric
+// delete algorithm: every word is indexed under the strings left after deleting up to maxDistance of
+// its runes, so lookups only compare against words sharing such a delete with the input.
+// Words are matched in their NormalizeTerm form; suggestions keep the dictionary's spelling.
+type SpellDictionary struct {
+	maxDistance int
+	words       []spellEntry       // Dictionary words, in insertion order
+	index       map[string]int     // Normalized word -> position in words
+	deletes     map[string][]int32 // Delete of a normalized word prefix -> positions in words
+}
+
+// spellEntry is a dictionary word with its corpus count.
+type spellEntry struct {
+	word  string // Spelling returned as a suggestion
+	key   []rune // NormalizeTerm(word)
+	count int
+}
+
+// SpellSuggestion is the closest known word to a looked-up word.
+type SpellSuggestion struct {
+	Word     string // Dictionary spelling
+	Distance int    // Edit distance (in runes) between the normalized words
+	Count    int    // Corpus count of the word
+}
+
+// NewSpellDictionary creates an empty dictionary that suggests words up to maxDistance edits away.
+func NewSpellDictionary(maxDistance int) *SpellDictionary {
+	return &SpellDictionary{maxDistance: maxDistance, index: make(map[string]int), deletes: make(map[string][]int32)}
+}
+
+// Load reads "word count" lines into d. Blank lines and lines starting with '#' are skipped.
+func (d *SpellDictionary) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return fmt.Errorf("line %d: expected a word and a count, got %q", line, text)
+		}
+		count, err := strconv.Atoi(fields[1])
+		if err != nil || count < 1 {
+			return fmt.Errorf("line %d: invalid count %q", line, fields[1])
+		}
+		d.Add(fields[0], count)
+	}
+	return scanner.Err()
+}
+
+// Add adds a word with its corpus count. Words with the same normalized form share one entry, whose
+// counts add up and whose spelling is the one with the higher count.
+func (d *SpellDictionary) Add(word string, count int) {
+	key := NormalizeTerm(word)
+	if key == "" {
+		return
+	}
+	if i, ok := d.index[key]; ok {
+		if count > d.words[i].count {
+			d.words[i].word = word
+		}
+		d.words[i].count += count
+		return
+	}
+
+	position := int32(len(d.words))
+	runes := []rune(key)
+	d.words = append(d.words, spellEntry{word: word, key: runes, count: count})
+	d.index[key] = int(position)
+	for _, del := range spellDeletes(runes[:min(len(runes), spellPrefixLength)], d.maxDistance) {
+		d.deletes[del] = append(d.deletes[del], position)
+	}
+}
+
+// Len returns the number of distinct words.
+func (d *SpellDictionary) Len() int {
+	return len(d.words)
+}
+
+// Contains reports whether word is in the dictionary, in any spelling with the same normalized form.
+func (d *SpellDictionary) Contains(word string) bool {
+	_, ok := d.index[NormalizeTerm(word)]
+	return ok
+}
+
+// Lookup returns the known word closest to word, within maxDistance edits (capped at the dictionary's
+// own maximum). Ties go to the more frequent word. A known word is its own suggestion at distance 0.
+func (d *SpellDictionary) Lookup(word string, maxDistance int) (SpellSuggestion, bool) {
+	key := NormalizeTerm(word)
+	if i, ok := d.index[key]; ok {
+		return SpellSuggestion{Word: d.words[i].word, Count: d.words[i].count}, true
+	}
+	maxDistance = min(maxDistance, d.maxDistance)
+	input := []rune(key)
+	if len(input) == 0 || maxDistance < 1 {
+		return SpellSuggestion{}, false
+	}
+
+	best, found := SpellSuggestion{Distance: maxDistance + 1}, false
+	checked := make(map[int32]bool)
+	for _, del := range spellDeletes(input[:min(len(input), spellPrefixLength)], maxDistance) {
+		for _, position := range d.deletes[del] {
+			if checked[position] {
+				continue
+			}
+			checked[position] = true
+			entry := d.words[position]
+			if abs(len(entry.key)-len(input)) > maxDistance {
+				continue
+			}
+			distance := EditDistance(input, entry.key)
+			if distance < best.Distance || (distance == best.Distance && entry.count > best.Count) {
+				best, found = SpellSuggestion{Word: entry.word, Distance: distance, Count: entry.count}, true
+			}
+		}
+	}
+	return best, found && best.Distance <= maxDistance
+}
+
+// spellDeletes returns word and the distinct strings left after deleting 1 to maxDistance of its runes.
+func spellDeletes(word []rune, maxDistance int) []string {
+	seen := map[string]bool{string(word): true}
+	deletes := []string{string(word)}
+	level := [][]rune{word}
+	for distance := 1; distance <= maxDistance; distance++ {
+		var next [][]rune
+		for _, w := range level {
+			if len(w) <= 1 {
+				continue // Deleting everything would match every short word.
+			}
+			for i := range w {
+				del := append(append(make([]rune, 0, len(w)-1), w[:i]...), w[i+1:]...)
+				if s := string(del); !seen[s] {
+					seen[s] = true
+					deletes = append(deletes, s)
+					next = append(next, del)
+				}
+			}
+		}
+		level = next
+	}
+	return deletes
+}
+
+// abs returns the absolute value of an integer.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// MatchCase applies the capitalization of template to word: all upper case, a capital first letter,
+// or else word unchanged.
+func MatchCase(word, template string) string {
+	if template == strings.ToUpper(template) && template != strings.ToLower(template) {
+		return strings.ToUpper(word)
+	}
+	first, _ := utf8.DecodeRuneInString(template)
+	if unicode.IsUpper(first) {
+		wordFirst, size := utf8.DecodeRuneInString(word)
+		return string(unicode.ToUpper(wordFirst)) + word[size:]
+	}
+	return word
+}
@@ -0,0 +1,325 @@
+package utils
+
+import (
+	"image"
+	"sort"
+)
+
+// Table detection works on an ink mask of the page: ruling lines are long, thin runs of ink.
+// Tuning values are relative to the typical text height, so they hold across scan resolutions.
+const (
+	// rulingGapTolerance is the number of light pixels a ruling line may be interrupted by (scan noise).
+	rulingGapTolerance = 2
+	// rulingMaxThickness is the thickness (in text heights) above which a run of ink is not a line.
+	rulingMaxThickness = 0.5
+	// rulingJoinTolerance is how far (in pixels) lines may miss each other and still be taken to meet.
+	rulingJoinTolerance = 6
+)
+
+// RulingLine is a horizontal or vertical line drawn on the page.
+type RulingLine struct {
+	Bounds     image.Rectangle
+	Horizontal bool
+}
+
+// center returns the line's position across its direction.
+func (l RulingLine) center() int {
+	if l.Horizontal {
+		return (l.Bounds.Min.Y + l.Bounds.Max.Y) / 2
+	}
+	return (l.Bounds.Min.X + l.Bounds.Max.X) / 2
+}
+
+// TableGrid is a table found on the page: its bounds and the row and column boundaries
+// (both sorted, including the outer edges), so there are len(Rows)-1 rows and len(Columns)-1 columns.
+type TableGrid struct {
+	Bounds  image.Rectangle
+	Rows    []int // y coordinates
+	Columns []int // x coordinates
+	// Ruled is true when the table has horizontal rules only and its columns come from word positions.
+	Ruled bool
+}
+
+// InkMask returns a mask of the dark pixels of gray (true = ink), using Otsu's threshold.
+func InkMask(gray *image.Gray) [][]bool {
+	threshold := OtsuThreshold(gray)
+	bounds := gray.Bounds()
+	mask := make([][]bool, bounds.Dy())
+	for y := range mask {
+		mask[y] = make([]bool, bounds.Dx())
+		row := gray.Pix[gray.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		for x := range mask[y] {
+			mask[y][x] = row[x] <= threshold
+		}
+	}
+	return mask
+}
+
+// DetectRulingLines finds horizontal lines of at least minHorizontal pixels and vertical lines of at
+// least minVertical pixels in the ink mask. Runs thicker than maxThickness pixels (filled areas,
+// bold text) are ignored.
+func DetectRulingLines(mask [][]bool, minHorizontal, minVertical, maxThickness int) []RulingLine {
+	height := len(mask)
+	if height == 0 {
+		return nil
+	}
+	width := len(mask[0])
+
+	lines := detectRuns(height, width, func(i, j int) bool { return mask[i][j] }, minHorizontal, maxThickness, true)
+	return append(lines, detectRuns(width, height, func(i, j int) bool { return mask[j][i] }, minVertical, maxThickness, false)...)
+}
+
+// detectRuns scans `outer` scanlines of `inner` pixels each for runs of ink of at least minLength,
+// and joins runs on adjacent scanlines into lines.
+func detectRuns(outer, inner int, ink func(i, j int) bool, minLength, maxThickness int, horizontal bool) []RulingLine {
+	type openLine struct {
+		start, end  int // Extent along the line
+		first, last int // Scanlines covered
+	}
+	var open, closed []openLine
+	for i := 0; i < outer; i++ {
+		var runs [][2]int
+		for j := 0; j < inner; {
+			if !ink(i, j) {
+				j++
+				continue
+			}
+			start, end, gap := j, j, 0
+			for j++; j < inner && gap <= rulingGapTolerance; j++ {
+				if ink(i, j) {
+					end, gap = j, 0
+				} else {
+					gap++
+				}
+			}
+			if end-start+1 >= minLength {
+				runs = append(runs, [2]int{start, end + 1})
+			}
+		}
+
+		// Extend the lines of the previous scanline that a run overlaps by at least half.
+		var next []openLine
+		for _, run := range runs {
+			extended := false
+			for k := range open {
+				l := &open[k]
+				if l.last != i-1 {
+					continue
+				}
+				overlap := min(l.end, run[1]) - max(l.start, run[0])
+				if overlap*2 >= min(l.end-l.start, run[1]-run[0]) {
+					l.start, l.end, l.last = min(l.start, run[0]), max(l.end, run[1]), i
+					extended = true
+					break
+				}
+			}
+			if !extended {
+				next = append(next, openLine{start: run[0], end: run[1], first: i, last: i})
+			}
+		}
+		for _, l := range open {
+			if l.last == i {
+				next = append(next, l)
+			} else {
+				closed = append(closed, l)
+			}
+		}
+		open = next
+	}
+	closed = append(closed, open...)
+
+	var lines []RulingLine
+	for _, l := range closed {
+		if l.last-l.first+1 > maxThickness {
+			continue
+		}
+		r := image.Rect(l.start, l.first, l.end, l.last+1)
+		if !horizontal {
+			r = image.Rect(l.first, l.start, l.last+1, l.end)
+		}
+		lines = append(lines, RulingLine{Bounds: r, Horizontal: horizontal})
+	}
+	return lines
+}
+
+// DetectTables finds tables on a grayscale page. `words` are the boxes of the OCR words on the page
+// and `textHeight` their typical height; words are used to infer columns of tables that only have
+// horizontal rules.
+func DetectTables(gray *image.Gray, words []image.Rectangle, textHeight int) []TableGrid {
+	bounds := gray.Bounds()
+	textHeight = max(textHeight, 8)
+	minHorizontal := max(bounds.Dx()/10, 4*textHeight)
+	minVertical := max(bounds.Dy()/40, 3*textHeight)
+	maxThickness := max(2, int(float64(textHeight)*rulingMaxThickness))
+
+	lines := DetectRulingLines(InkMask(gray), minHorizontal, minVertical, maxThickness)
+	tables, leftover := gridTables(lines)
+	return append(tables, ruledTables(leftover, words, textHeight)...)
+}
+
+// gridTables groups intersecting lines into grids with at least two horizontal and two vertical
+// lines. Horizontal lines that are not part of a grid are returned as leftovers.
+func gridTables(lines []RulingLine) ([]TableGrid, []RulingLine) {
+	parent := make([]int, len(lines))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i, h := range lines {
+		if !h.Horizontal {
+			continue
+		}
+		for j, v := range lines {
+			if v.Horizontal {
+				continue
+			}
+			if h.Bounds.Inset(-rulingJoinTolerance).Overlaps(v.Bounds.Inset(-rulingJoinTolerance)) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	groups := map[int][]RulingLine{}
+	var order []int
+	for i, line := range lines {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			order = append(order, root)
+		}
+		groups[root] = append(groups[root], line)
+	}
+
+	var tables []TableGrid
+	var leftover []RulingLine
+	for _, root := range order {
+		group := groups[root]
+		var rows, columns []int
+		var table image.Rectangle
+		for _, line := range group {
+			table = table.Union(line.Bounds)
+			if line.Horizontal {
+				rows = append(rows, line.center())
+			} else {
+				columns = append(columns, line.center())
+			}
+		}
+		if len(rows) < 2 || len(columns) < 2 {
+			for _, line := range group {
+				if line.Horizontal {
+					leftover = append(leftover, line)
+				}
+			}
+			continue
+		}
+		// Tables without an outer border still end where their lines do.
+		rows = mergeBoundaries(append(rows, table.Min.Y, table.Max.Y-1))
+		columns = mergeBoundaries(append(columns, table.Min.X, table.Max.X-1))
+		if len(rows) >= 2 && len(columns) >= 2 {
+			tables = append(tables, TableGrid{Bounds: table, Rows: rows, Columns: columns})
+		}
+	}
+	return tables, leftover
+}
+
+// ruledTables finds tables made of at least three horizontal rules of about the same extent and
+// infers their columns from vertical gaps between the words inside.
+func ruledTables(lines []RulingLine, words []image.Rectangle, textHeight int) []TableGrid {
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Bounds.Min.Y < lines[j].Bounds.Min.Y })
+
+	var tables []TableGrid
+	used := make([]bool, len(lines))
+	for i := range lines {
+		if used[i] {
+			continue
+		}
+		group := []RulingLine{lines[i]}
+		for j := i + 1; j < len(lines); j++ {
+			last := group[len(group)-1]
+			if used[j] || lines[j].Bounds.Min.Y-last.Bounds.Max.Y > 6*textHeight {
+				continue
+			}
+			if sameExtent(lines[i].Bounds, lines[j].Bounds) {
+				group = append(group, lines[j])
+				used[j] = true
+			}
+		}
+		if len(group) < 3 {
+			continue
+		}
+
+		var table image.Rectangle
+		var rows []int
+		for _, line := range group {
+			table = table.Union(line.Bounds)
+			rows = append(rows, line.center())
+		}
+		columns := inferColumns(table, words, textHeight)
+		if len(columns) >= 3 { // At least two columns
+			tables = append(tables, TableGrid{Bounds: table, Rows: mergeBoundaries(rows), Columns: columns, Ruled: true})
+		}
+	}
+	return tables
+}
+
+// sameExtent reports whether two horizontal lines cover mostly the same x range.
+func sameExtent(a, b image.Rectangle) bool {
+	overlap := min(a.Max.X, b.Max.X) - max(a.Min.X, b.Min.X)
+	return overlap*10 >= max(a.Dx(), b.Dx())*8
+}
+
+// inferColumns returns column boundaries inside `table` at vertical gaps of at least 1.5 text heights
+// that no word crosses, including the table's edges. It returns nil when there are no words inside.
+func inferColumns(table image.Rectangle, words []image.Rectangle, textHeight int) []int {
+	covered := make([]bool, table.Dx())
+	found := false
+	for _, word := range words {
+		center := image.Pt((word.Min.X+word.Max.X)/2, (word.Min.Y+word.Max.Y)/2)
+		if !center.In(table) {
+			continue
+		}
+		found = true
+		for x := max(word.Min.X, table.Min.X); x < min(word.Max.X, table.Max.X); x++ {
+			covered[x-table.Min.X] = true
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	columns := []int{table.Min.X}
+	minGap := textHeight * 3 / 2
+	for x := 0; x < len(covered); {
+		if covered[x] {
+			x++
+			continue
+		}
+		start := x
+		for x < len(covered) && !covered[x] {
+			x++
+		}
+		// Gaps at the table's edges are margins, not column separators.
+		if start > 0 && x < len(covered) && x-start >= minGap {
+			columns = append(columns, table.Min.X+(start+x)/2)
+		}
+	}
+	return append(columns, table.Max.X-1)
+}
+
+// mergeBoundaries sorts positions and merges those closer than the join tolerance (double rules).
+func mergeBoundaries(positions []int) []int {
+	sort.Ints(positions)
+	var merged []int
+	for _, p := range positions {
+		if len(merged) > 0 && p-merged[len(merged)-1] <= rulingJoinTolerance {
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
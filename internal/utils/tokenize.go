@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Devanagari signs folded together when normalizing search terms (the nukta is in transliterate.go).
+const (
+	devanagariCandrabindu = '\u0901' // ँ
+	devanagariAnusvara    = '\u0902' // ं
+)
+
+// Token is one word of a text, located by byte offsets into the original string.
+type Token struct {
+	Term  string // Normalized form (see NormalizeTerm)
+	Start int    // Byte offset of the first byte of the word
+	End   int    // Byte offset just after the word
+}
+
+// isWordRune reports whether r belongs inside a word. Unlike unicode.IsLetter, this keeps
+// Devanagari vowel signs, virama and other combining marks (and ZWJ/ZWNJ inside conjuncts)
+// in the word instead of splitting "सम्झनु" into fragments.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.M, r) || r == zeroWidthJoiner || r == zeroWidthNonJoin
+}
+
+// Tokenize splits text into words. Whitespace, punctuation, dandas and symbols separate words.
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			if term := NormalizeTerm(text[start:i]); term != "" {
+				tokens = append(tokens, Token{Term: term, Start: start, End: i})
+			}
+			start = -1
+		}
+	}
+	if start >= 0 {
+		if term := NormalizeTerm(text[start:]); term != "" {
+			tokens = append(tokens, Token{Term: term, Start: start, End: len(text)})
+		}
+	}
+	return tokens
+}
+
+// Terms returns just the normalized terms of Tokenize(text).
+func Terms(text string) []string {
+	tokens := Tokenize(text)
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		terms = append(terms, token.Term)
+	}
+	return terms
+}
+
+// NormalizeTerm folds a word into its search form, so spelling and OCR variants match:
+// NFC, lowercase, ASCII digits (५ → 5), no nukta (ज़ → ज), chandrabindu as anusvara (ँ → ं),
+// and no ZWJ/ZWNJ.
+func NormalizeTerm(word string) string {
+	// NFD separates precomposed nukta letters (क़ → क + ़) so the nukta can be dropped.
+	word = norm.NFD.String(word)
+
+	var b strings.Builder
+	b.Grow(len(word))
+	for _, r := range word {
+		switch {
+		case r == devanagariNukta || r == zeroWidthJoiner || r == zeroWidthNonJoin:
+			continue
+		case r == devanagariCandrabindu:
+			r = devanagariAnusvara
+		case isDevanagariDigit(r):
+			r = '0' + (r - devanagariZero)
+		default:
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return norm.NFC.String(b.String())
+}
+
+// SearchQuery is a parsed full-text query. All parts must match.
+type SearchQuery struct {
+	Words    []string   // Normalized single words
+	Phrases  [][]string // Normalized words that must appear consecutively ("दफा ५")
+	Prefixes []string   // Normalized word prefixes (written as "संशोधन*")
+}
+
+// IsEmpty reports whether the query has nothing to match.
+func (q SearchQuery) IsEmpty() bool {
+	return len(q.Words) == 0 && len(q.Phrases) == 0 && len(q.Prefixes) == 0
+}
+
+// AllWords returns the query words together with the words of every phrase.
+func (q SearchQuery) AllWords() []string {
+	words := append([]string(nil), q.Words...)
+	for _, phrase := range q.Phrases {
+		words = append(words, phrase...)
+	}
+	return words
+}
+
+// ParseSearchQuery parses a query such as `"दफा ५" नियमावली संशोधन*`: quoted text is a phrase,
+// a word ending in '*' is a prefix, and any other word must match exactly (after NormalizeTerm).
+// A quoted text of a single word is treated as a plain word.
+func ParseSearchQuery(query string) SearchQuery {
+	var q SearchQuery
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			// Inside quotes (an unterminated quote runs to the end of the query).
+			switch terms := Terms(part); len(terms) {
+			case 0:
+			case 1:
+				q.Words = append(q.Words, terms[0])
+			default:
+				q.Phrases = append(q.Phrases, terms)
+			}
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			if strings.HasSuffix(field, "*") {
+				if terms := Terms(strings.TrimRight(field, "*")); len(terms) == 1 {
+					q.Prefixes = append(q.Prefixes, terms[0])
+					continue
+				}
+			}
+			q.Words = append(q.Words, Terms(field)...)
+		}
+	}
+	return q
+}
+
+// Highlight returns an excerpt of `text` around the first match of `q`, with every matching
+// word wrapped in `open` and `close`. At most `maxRunes` runes of context are kept (roughly
+// split before and after the first match); the excerpt is marked with "…" where it was cut.
+// `escape` is applied to all text outside the markers (e.g., html.EscapeString), or nil.
+func Highlight(text string, q SearchQuery, maxRunes int, open, close string, escape func(string) string) string {
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+	tokens := Tokenize(text)
+	matched := matchTokens(tokens, q)
+
+	first := -1
+	for i, ok := range matched {
+		if ok {
+			first = i
+			break
+		}
+	}
+
+	// Choose the excerpt window in bytes, snapped to rune boundaries.
+	start, end := 0, len(text)
+	if utf8.RuneCountInString(text) > maxRunes {
+		anchor := 0
+		if first >= 0 {
+			anchor = tokens[first].Start
+		}
+		start = snapToWord(text, moveRunes(text, anchor, -maxRunes/3), -1)
+		end = snapToWord(text, moveRunes(text, start, maxRunes), 1)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for i, token := range tokens {
+		if !matched[i] || token.Start < start || token.End > end {
+			continue
+		}
+		b.WriteString(escape(text[pos:token.Start]))
+		b.WriteString(open)
+		b.WriteString(escape(text[token.Start:token.End]))
+		b.WriteString(close)
+		pos = token.End
+	}
+	if end < len(text) {
+		b.WriteString(escape(strings.TrimRightFunc(text[pos:end], unicode.IsSpace)))
+		b.WriteString("…")
+	} else {
+		b.WriteString(escape(text[pos:end]))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// matchTokens marks the tokens that match a word, prefix or (complete) phrase of the query.
+func matchTokens(tokens []Token, q SearchQuery) []bool {
+	matched := make([]bool, len(tokens))
+	words := make(map[string]bool, len(q.Words))
+	for _, word := range q.Words {
+		words[word] = true
+	}
+	for i, token := range tokens {
+		if words[token.Term] {
+			matched[i] = true
+			continue
+		}
+		for _, prefix := range q.Prefixes {
+			if strings.HasPrefix(token.Term, prefix) {
+				matched[i] = true
+				break
+			}
+		}
+	}
+	for _, phrase := range q.Phrases {
+		for i := 0; i+len(phrase) <= len(tokens); i++ {
+			if tokensMatchPhrase(tokens[i:i+len(phrase)], phrase) {
+				for j := range phrase {
+					matched[i+j] = true
+				}
+			}
+		}
+	}
+	return matched
+}
+
+// tokensMatchPhrase reports whether the tokens spell out the phrase.
+func tokensMatchPhrase(tokens []Token, phrase []string) bool {
+	for i, word := range phrase {
+		if tokens[i].Term != word {
+			return false
+		}
+	}
+	return true
+}
+
+// moveRunes returns the byte offset `n` runes after (or, for negative n, before) offset `from`, clamped to the text.
+func moveRunes(text string, from, n int) int {
+	for ; n > 0 && from < len(text); n-- {
+		_, size := utf8.DecodeRuneInString(text[from:])
+		from += size
+	}
+	for ; n < 0 && from > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+	return from
+}
+
+// snapToWord moves a byte offset out of the middle of a word: backwards to the word's start
+// (dir < 0) or forwards to its end (dir > 0), so excerpts never cut through a syllable.
+func snapToWord(text string, offset, dir int) int {
+	for offset > 0 && offset < len(text) {
+		before, _ := utf8.DecodeLastRuneInString(text[:offset])
+		after, _ := utf8.DecodeRuneInString(text[offset:])
+		if !isWordRune(before) || !isWordRune(after) {
+			break
+		}
+		offset = moveRunes(text, offset, dir)
+	}
+	return offset
+}
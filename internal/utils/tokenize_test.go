@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"html"
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"दफा ५", []string{"दफा", "5"}},
+		{"दफा ५(२) बमोजिम।", []string{"दफा", "5", "2", "बमोजिम"}},
+		{"नेपाल।सरकार॥", []string{"नेपाल", "सरकार"}},
+		{"सम्झनु क्षेत्र", []string{"सम्झनु", "क्षेत्र"}},
+		{"ज़िल्ला जिल्ला", []string{"जिल्ला", "जिल्ला"}},
+		{"चाँदी चांदी", []string{"चांदी", "चांदी"}},
+		{"क्‍ष", []string{"क्ष"}}, // ZWJ inside the conjunct
+		{"Section 5, Act-2074", []string{"section", "5", "act", "2074"}},
+		{" ।, ", nil},
+	}
+	for _, tt := range tests {
+		if got := Terms(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTokenizeOffsets(t *testing.T) {
+	text := "(दफा ५) संशोधन"
+	for _, token := range Tokenize(text) {
+		if got := NormalizeTerm(text[token.Start:token.End]); got != token.Term {
+			t.Errorf("token %q at [%d:%d] spells %q", token.Term, token.Start, token.End, got)
+		}
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  SearchQuery
+	}{
+		{`दफा ५`, SearchQuery{Words: []string{"दफा", "5"}}},
+		{`"दफा ५" संशोधन*`, SearchQuery{Phrases: [][]string{{"दफा", "5"}}, Prefixes: []string{"संशोधन"}}},
+		{`"नियमावली"`, SearchQuery{Words: []string{"नियमावली"}}},
+		{`ऐन "उपदफा (२)`, SearchQuery{Words: []string{"ऐन"}, Phrases: [][]string{{"उपदफा", "2"}}}},
+		{`Act* *`, SearchQuery{Prefixes: []string{"act"}}},
+		{`"" ।`, SearchQuery{}},
+	}
+	for _, tt := range tests {
+		got := ParseSearchQuery(tt.query)
+		if !slices.Equal(got.Words, tt.want.Words) || !slices.EqualFunc(got.Phrases, tt.want.Phrases, slices.Equal) ||
+			!slices.Equal(got.Prefixes, tt.want.Prefixes) {
+			t.Errorf("ParseSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+	if !ParseSearchQuery(`"" ।`).IsEmpty() {
+		t.Error("a query without words should be empty")
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text, query string
+		maxRunes    int
+		want        string
+	}{
+		{"दफा ५ बमोजिम <सजाय>", `"दफा ५"`, 100, "<mark>दफा</mark> <mark>५</mark> बमोजिम &lt;सजाय&gt;"},
+		{"दफा ६ र दफा ५", `"दफा ५"`, 100, "दफा ६ र <mark>दफा</mark> <mark>५</mark>"},
+		{"संशोधन गर्ने संशोधनको ऐन", `संशोधन*`, 100, "<mark>संशोधन</mark> गर्ने <mark>संशोधनको</mark> ऐन"},
+		{"क ख ग घ ङ च छ ज झ ञ ट ठ ड ढ ण दफा त थ द ध न", `दफा`, 12, "…ढ ण <mark>दफा</mark> त थ…"},
+	}
+	for _, tt := range tests {
+		got := Highlight(tt.text, ParseSearchQuery(tt.query), tt.maxRunes, "<mark>", "</mark>", html.EscapeString)
+		if got != tt.want {
+			t.Errorf("Highlight(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+		}
+	}
+}
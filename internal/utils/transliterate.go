@@ -0,0 +1,550 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Transliteration schemes accepted by Transliterate.
+const (
+	SchemeDevanagari = "devanagari"
+	SchemeIAST       = "iast"     // International Alphabet of Sanskrit Transliteration, with the ISO 15919 ':' and ï/ü marks
+	SchemeISO15919   = "iso15919" // ISO 15919 (distinguishes ē/ō, r̥, ṁ)
+	SchemeNepali     = "nepali"   // Common Nepali romanization: no diacritics, schwa deletion (one-way)
+)
+
+// TransliterationSchemes lists every scheme name accepted by Transliterate.
+var TransliterationSchemes = []string{SchemeDevanagari, SchemeIAST, SchemeISO15919, SchemeNepali}
+
+// Devanagari code points that drive token parsing.
+const (
+	devanagariNukta = '\u093C' // ़
+)
+
+// romanSeparator separates Latin letters that would otherwise be read as one letter (ISO 15919).
+const romanSeparator = ':'
+
+// Keys identify letters independently of any romanization scheme.
+// Consonants use their own key space ("k", "kh", ...) separate from vowels ("a", "aa", ...).
+var (
+	devanagariConsonants = map[rune]string{
+		'क': "k", 'ख': "kh", 'ग': "g", 'घ': "gh", 'ङ': "ng",
+		'च': "c", 'छ': "ch", 'ज': "j", 'झ': "jh", 'ञ': "ny",
+		'ट': "tt", 'ठ': "tth", 'ड': "dd", 'ढ': "ddh", 'ण': "nn",
+		'त': "t", 'थ': "th", 'द': "d", 'ध': "dh", 'न': "n",
+		'प': "p", 'फ': "ph", 'ब': "b", 'भ': "bh", 'म': "m",
+		'य': "y", 'र': "r", 'ल': "l", 'ळ': "ll", 'व': "v",
+		'श': "sh", 'ष': "ss", 'स': "s", 'ह': "h",
+		// Precomposed nukta letters (NFC decomposes these, but raw input may contain them).
+		'\u0958': "q", '\u0959': "khh", '\u095A': "ghh", '\u095B': "z",
+		'\u095C': "rr", '\u095D': "rrh", '\u095E': "f", '\u095F': "yy",
+	}
+	// nuktaConsonants maps a base consonant key to its nukta variant (क + ़ → क़).
+	nuktaConsonants = map[string]string{
+		"k": "q", "kh": "khh", "g": "ghh", "j": "z", "dd": "rr", "ddh": "rrh", "ph": "f", "y": "yy",
+	}
+	devanagariVowels = map[rune]string{
+		'अ': "a", 'आ': "aa", 'इ': "i", 'ई': "ii", 'उ': "u", 'ऊ': "uu",
+		'ऋ': "ri", 'ॠ': "rii", 'ऌ': "li", 'ॡ': "lii",
+		'ए': "e", 'ऐ': "ai", 'ओ': "o", 'औ': "au", 'ऍ': "ae", 'ऑ': "ao",
+	}
+	devanagariMatras = map[rune]string{
+		'ा': "aa", 'ि': "i", 'ी': "ii", 'ु': "u", 'ू': "uu",
+		'ृ': "ri", 'ॄ': "rii", 'ॢ': "li", 'ॣ': "lii",
+		'े': "e", 'ै': "ai", 'ो': "o", 'ौ': "au", 'ॅ': "ae", 'ॉ': "ao",
+	}
+	devanagariSigns = map[rune]string{
+		'ं': "anusvara", 'ँ': "candrabindu", 'ः': "visarga", 'ऽ': "avagraha", 'ॐ': "om",
+		devanagariDanda: "danda", devanagariDouble: "ddanda",
+	}
+)
+
+// romanScheme holds the Latin spelling of every consonant, vowel and sign key for one scheme.
+type romanScheme struct {
+	consonants map[string]string
+	vowels     map[string]string
+	signs      map[string]string
+}
+
+var iastScheme = romanScheme{
+	consonants: map[string]string{
+		"k": "k", "kh": "kh", "g": "g", "gh": "gh", "ng": "ṅ",
+		"c": "c", "ch": "ch", "j": "j", "jh": "jh", "ny": "ñ",
+		"tt": "ṭ", "tth": "ṭh", "dd": "ḍ", "ddh": "ḍh", "nn": "ṇ",
+		"t": "t", "th": "th", "d": "d", "dh": "dh", "n": "n",
+		"p": "p", "ph": "ph", "b": "b", "bh": "bh", "m": "m",
+		"y": "y", "r": "r", "l": "l", "ll": "ḻ", "v": "v",
+		"sh": "ś", "ss": "ṣ", "s": "s", "h": "h",
+		"q": "q", "khh": "k͟h", "ghh": "ġ", "z": "z", "rr": "ṙ", "rrh": "ṙh", "f": "f", "yy": "ẏ",
+	},
+	vowels: map[string]string{
+		"a": "a", "aa": "ā", "i": "i", "ii": "ī", "u": "u", "uu": "ū",
+		"ri": "ṛ", "rii": "ṝ", "li": "ḷ", "lii": "ḹ",
+		"e": "e", "ai": "ai", "o": "o", "au": "au", "ae": "ê", "ao": "ô",
+	},
+	signs: map[string]string{
+		"anusvara": "ṃ", "candrabindu": "m̐", "visarga": "ḥ", "avagraha": "'", "om": "oṃ",
+		"danda": "|", "ddanda": "||",
+	},
+}
+
+var iso15919Scheme = romanScheme{
+	consonants: map[string]string{
+		"k": "k", "kh": "kh", "g": "g", "gh": "gh", "ng": "ṅ",
+		"c": "c", "ch": "ch", "j": "j", "jh": "jh", "ny": "ñ",
+		"tt": "ṭ", "tth": "ṭh", "dd": "ḍ", "ddh": "ḍh", "nn": "ṇ",
+		"t": "t", "th": "th", "d": "d", "dh": "dh", "n": "n",
+		"p": "p", "ph": "ph", "b": "b", "bh": "bh", "m": "m",
+		"y": "y", "r": "r", "l": "l", "ll": "ḷ", "v": "v",
+		"sh": "ś", "ss": "ṣ", "s": "s", "h": "h",
+		"q": "q", "khh": "k͟h", "ghh": "ġ", "z": "z", "rr": "ṛ", "rrh": "ṛh", "f": "f", "yy": "ẏ",
+	},
+	vowels: map[string]string{
+		"a": "a", "aa": "ā", "i": "i", "ii": "ī", "u": "u", "uu": "ū",
+		"ri": "r̥", "rii": "r̥̄", "li": "l̥", "lii": "l̥̄",
+		"e": "ē", "ai": "ai", "o": "ō", "au": "au", "ae": "ê", "ao": "ô",
+	},
+	signs: map[string]string{
+		"anusvara": "ṁ", "candrabindu": "m̐", "visarga": "ḥ", "avagraha": "'", "om": "ōṁ",
+		"danda": "|", "ddanda": "||",
+	},
+}
+
+var nepaliScheme = romanScheme{
+	consonants: map[string]string{
+		"k": "k", "kh": "kh", "g": "g", "gh": "gh", "ng": "ng",
+		"c": "ch", "ch": "chh", "j": "j", "jh": "jh", "ny": "ny",
+		"tt": "t", "tth": "th", "dd": "d", "ddh": "dh", "nn": "n",
+		"t": "t", "th": "th", "d": "d", "dh": "dh", "n": "n",
+		"p": "p", "ph": "ph", "b": "b", "bh": "bh", "m": "m",
+		"y": "y", "r": "r", "l": "l", "ll": "l", "v": "v",
+		"sh": "sh", "ss": "sh", "s": "s", "h": "h",
+		"q": "k", "khh": "kh", "ghh": "g", "z": "j", "rr": "r", "rrh": "rh", "f": "f", "yy": "y",
+	},
+	vowels: map[string]string{
+		"a": "a", "aa": "a", "i": "i", "ii": "i", "u": "u", "uu": "u",
+		"ri": "ri", "rii": "ri", "li": "li", "lii": "li",
+		"e": "e", "ai": "ai", "o": "o", "au": "au", "ae": "e", "ao": "o",
+	},
+	signs: map[string]string{
+		"anusvara": "n", "candrabindu": "n", "visarga": "h", "avagraha": "", "om": "om",
+		"danda": ".", "ddanda": ".",
+	},
+}
+
+// romanSchemes maps scheme names to their tables.
+var romanSchemes = map[string]*romanScheme{
+	SchemeIAST:     &iastScheme,
+	SchemeISO15919: &iso15919Scheme,
+	SchemeNepali:   &nepaliScheme,
+}
+
+// Token kinds produced when parsing Devanagari text.
+const (
+	tokenOther = iota
+	tokenConsonant
+	tokenVowel
+	tokenSign
+)
+
+// translitToken is one parsed unit of Devanagari text.
+// For consonants, `vowel` is the following vowel key: "a" for the inherent vowel,
+// a matra key, or "" when the consonant carries a virama (i.e., is part of a conjunct).
+type translitToken struct {
+	kind  int
+	key   string
+	vowel string
+	raw   rune
+}
+
+// Transliterate converts `text` from one scheme to another. Supported directions are
+// Devanagari → IAST / ISO 15919 / Nepali, and IAST / ISO 15919 → Devanagari.
+// The Nepali romanization is lossy and therefore cannot be converted back.
+func Transliterate(text, from, to string) (string, error) {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	if from == to {
+		return text, nil
+	}
+
+	switch {
+	case from == SchemeDevanagari && romanSchemes[to] != nil:
+		tokens := parseDevanagari(norm.NFC.String(text))
+		if to == SchemeNepali {
+			return renderNepali(tokens), nil
+		}
+		return renderRoman(tokens, romanSchemes[to]), nil
+	case to == SchemeDevanagari && (from == SchemeIAST || from == SchemeISO15919):
+		return romanToDevanagari(text, romanSchemes[from], from == SchemeISO15919), nil
+	case from == SchemeNepali && to == SchemeDevanagari:
+		return "", fmt.Errorf("the '%s' romanization is lossy and cannot be converted back to Devanagari", SchemeNepali)
+	default:
+		return "", fmt.Errorf("unsupported transliteration from '%s' to '%s' (supported schemes: %s)",
+			from, to, strings.Join(TransliterationSchemes, ", "))
+	}
+}
+
+// parseDevanagari splits NFC-normalized text into consonant, vowel, sign and other tokens.
+func parseDevanagari(text string) []translitToken {
+	runes := []rune(text)
+	tokens := make([]translitToken, 0, len(runes))
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if key, ok := devanagariConsonants[r]; ok {
+			if i+1 < len(runes) && runes[i+1] == devanagariNukta {
+				if nukta, ok := nuktaConsonants[key]; ok {
+					key = nukta
+				}
+				i++
+			}
+			tok := translitToken{kind: tokenConsonant, key: key, vowel: "a"}
+			if i+1 < len(runes) {
+				if runes[i+1] == devanagariVirama {
+					tok.vowel = ""
+					i++
+					// ZWJ/ZWNJ after a virama only affect rendering.
+					for i+1 < len(runes) && (runes[i+1] == zeroWidthJoiner || runes[i+1] == zeroWidthNonJoin) {
+						i++
+					}
+				} else if matra, ok := devanagariMatras[runes[i+1]]; ok {
+					tok.vowel = matra
+					i++
+				}
+			}
+			tokens = append(tokens, tok)
+			continue
+		}
+
+		if key, ok := devanagariVowels[r]; ok {
+			tokens = append(tokens, translitToken{kind: tokenVowel, key: key})
+			continue
+		}
+		if key, ok := devanagariSigns[r]; ok {
+			tokens = append(tokens, translitToken{kind: tokenSign, key: key})
+			continue
+		}
+		if isDevanagariDigit(r) {
+			r = '0' + (r - devanagariZero)
+		}
+		tokens = append(tokens, translitToken{kind: tokenOther, raw: r})
+	}
+	return tokens
+}
+
+// renderRoman writes tokens using a reversible scheme (IAST or ISO 15919). Both use the ISO 15919
+// marks for spellings that would otherwise read back as a different letter: an independent i/u
+// directly after an 'a' is written ï/ü (अइ is not ऐ), and ':' separates a half consonant from
+// a following one it would combine with (क्ह is "k:ha", not ख).
+func renderRoman(tokens []translitToken, scheme *romanScheme) string {
+	var b strings.Builder
+	endsWithA := false
+	halfConsonant := "" // Spelling of a preceding consonant with a virama
+
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenConsonant:
+			consonant := scheme.consonants[tok.key]
+			if halfConsonant != "" && joinsConsonant(scheme, halfConsonant, consonant) {
+				b.WriteRune(romanSeparator)
+			}
+			b.WriteString(consonant)
+			b.WriteString(scheme.vowels[tok.vowel])
+			endsWithA = tok.vowel == "a"
+			halfConsonant = ""
+			if tok.vowel == "" {
+				halfConsonant = consonant
+			}
+			continue
+		case tokenVowel:
+			vowel := scheme.vowels[tok.key]
+			if endsWithA {
+				switch tok.key {
+				case "i":
+					vowel = "ï"
+				case "u":
+					vowel = "ü"
+				}
+			}
+			b.WriteString(vowel)
+			endsWithA = tok.key == "a"
+		case tokenSign:
+			b.WriteString(scheme.signs[tok.key])
+			endsWithA = false
+		default:
+			b.WriteRune(tok.raw)
+			endsWithA = false
+		}
+		halfConsonant = ""
+	}
+	return b.String()
+}
+
+// joinsConsonant reports whether the spelling of a half consonant followed by another consonant
+// starts with the spelling of a different, longer consonant (k + h reads as kh).
+func joinsConsonant(scheme *romanScheme, half, next string) bool {
+	for _, spelling := range scheme.consonants {
+		if len(spelling) > len(half) && strings.HasPrefix(spelling, half) && strings.HasPrefix(half+next, spelling) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderNepali writes tokens in the common Nepali romanization. It applies schwa deletion
+// word by word (see deleteSchwas) and a few spelling conventions (ज्ञ → gy, ं → m before labials).
+func renderNepali(tokens []translitToken) string {
+	var b strings.Builder
+	for start := 0; start < len(tokens); {
+		if tok := tokens[start]; isWordBoundary(tok) {
+			if tok.kind == tokenSign {
+				b.WriteString(nepaliScheme.signs[tok.key])
+			} else {
+				b.WriteRune(tok.raw)
+			}
+			start++
+			continue
+		}
+		end := start
+		for end < len(tokens) && !isWordBoundary(tokens[end]) {
+			end++
+		}
+		word := append([]translitToken(nil), tokens[start:end]...)
+		deleteSchwas(word)
+		writeNepaliWord(&b, word)
+		start = end
+	}
+	return b.String()
+}
+
+// isWordBoundary reports whether a token separates words: anything but letters and the signs
+// written on them (the dandas are punctuation).
+func isWordBoundary(tok translitToken) bool {
+	return tok.kind == tokenOther || (tok.kind == tokenSign && (tok.key == "danda" || tok.key == "ddanda"))
+}
+
+// deleteSchwas removes inherent vowels that are not pronounced in Nepali:
+// the word-final one (in words of two or more syllables not ending in a conjunct),
+// then, right to left, a medial one between a full syllable and a consonant+vowel syllable.
+// A medial schwa is kept next to another inherent a that is still pronounced: before one, or after
+// an independent अ or an inherent a that follows a different vowel (उपदफा → upadapha, अवसर → avasar,
+// but नगरको → nagarko).
+func deleteSchwas(word []translitToken) {
+	syllables := 0
+	for _, tok := range word {
+		if tok.kind == tokenVowel || (tok.kind == tokenConsonant && tok.vowel != "") {
+			syllables++
+		}
+	}
+	last := len(word) - 1
+	if last < 0 || syllables < 2 {
+		return
+	}
+
+	isFullSyllable := func(i int) bool {
+		if i < 0 || i > last {
+			return false
+		}
+		return word[i].kind == tokenVowel || (word[i].kind == tokenConsonant && word[i].vowel != "")
+	}
+	hasSchwa := func(i int) bool {
+		return i >= 0 && word[i].kind == tokenConsonant && word[i].vowel == "a"
+	}
+	// keepsSchwa reports whether the syllable at i is an inherent a that could itself have been dropped.
+	keepsSchwa := func(i int) bool {
+		return (word[i].kind == tokenVowel && word[i].key == "a") || (i > 0 && hasSchwa(i) && !hasSchwa(i-1))
+	}
+
+	if tok := word[last]; tok.kind == tokenConsonant && tok.vowel == "a" &&
+		!(last > 0 && word[last-1].kind == tokenConsonant && word[last-1].vowel == "") {
+		word[last].vowel = ""
+	}
+
+	for i := last - 1; i > 0; i-- {
+		if !hasSchwa(i) {
+			continue
+		}
+		next := word[i+1]
+		if isFullSyllable(i-1) && !keepsSchwa(i-1) && next.kind == tokenConsonant && next.vowel != "" && next.vowel != "a" {
+			word[i].vowel = ""
+		}
+	}
+}
+
+// writeNepaliWord renders one word after schwa deletion.
+func writeNepaliWord(b *strings.Builder, word []translitToken) {
+	for i, tok := range word {
+		switch tok.kind {
+		case tokenConsonant:
+			spelling := nepaliScheme.consonants[tok.key]
+			switch {
+			case tok.key == "j" && tok.vowel == "" && i+1 < len(word) && word[i+1].key == "ny":
+				spelling = "g" // ज्ञ is pronounced "gya"
+			case tok.key == "ny" && i > 0 && word[i-1].key == "j" && word[i-1].vowel == "":
+				spelling = "y"
+			case (tok.key == "ny" || tok.key == "ng") && tok.vowel == "":
+				spelling = "n" // ञ्च → nch, ङ्ग → ng
+			}
+			b.WriteString(spelling)
+			b.WriteString(nepaliScheme.vowels[tok.vowel])
+		case tokenVowel:
+			b.WriteString(nepaliScheme.vowels[tok.key])
+		case tokenSign:
+			spelling := nepaliScheme.signs[tok.key]
+			if tok.key == "anusvara" && i+1 < len(word) {
+				switch word[i+1].key {
+				case "p", "ph", "b", "bh", "m":
+					spelling = "m"
+				}
+			}
+			b.WriteString(spelling)
+		}
+	}
+}
+
+// romanToken is one entry of the reverse lookup table for a romanization scheme.
+type romanToken struct {
+	latin string
+	kind  int
+	key   string
+}
+
+// romanToDevanagari converts IAST or ISO 15919 text back to Devanagari using greedy
+// longest-match tokenization. Characters that are not part of the scheme are copied as-is,
+// except ASCII digits, which become Devanagari digits.
+func romanToDevanagari(text string, scheme *romanScheme, iso bool) string {
+	table := reverseTable(scheme, iso)
+	consonants := invertRuneMap(devanagariConsonants)
+	vowels := invertRuneMap(devanagariVowels)
+	matras := invertRuneMap(devanagariMatras)
+	signs := invertRuneMap(devanagariSigns)
+
+	input := []rune(norm.NFC.String(strings.ToLower(text)))
+	var b strings.Builder
+	pendingConsonant := false
+	closeConsonant := func() {
+		if pendingConsonant {
+			b.WriteRune(devanagariVirama)
+			pendingConsonant = false
+		}
+	}
+
+	for i := 0; i < len(input); {
+		if input[i] == romanSeparator && pendingConsonant && i+1 < len(input) && unicode.IsLetter(input[i+1]) {
+			closeConsonant()
+			i++
+			continue
+		}
+		tok, n := matchRomanToken(input[i:], table)
+		if n == 0 {
+			closeConsonant()
+			r := input[i]
+			if r >= '0' && r <= '9' {
+				r = devanagariZero + (r - '0')
+			}
+			b.WriteRune(r)
+			i++
+			continue
+		}
+		i += n
+
+		switch tok.kind {
+		case tokenConsonant:
+			closeConsonant()
+			b.WriteRune(consonants[tok.key])
+			pendingConsonant = true
+		case tokenVowel:
+			if pendingConsonant {
+				if tok.key != "a" {
+					b.WriteRune(matras[tok.key])
+				}
+				pendingConsonant = false
+			} else {
+				b.WriteRune(vowels[tok.key])
+			}
+		case tokenSign:
+			closeConsonant()
+			b.WriteRune(signs[tok.key])
+		}
+	}
+	closeConsonant()
+	return norm.NFC.String(b.String())
+}
+
+// reverseTable builds the Latin → key lookup for a scheme, longest spellings first.
+func reverseTable(scheme *romanScheme, iso bool) []romanToken {
+	var table []romanToken
+	add := func(kind int, m map[string]string) {
+		for key, latin := range m {
+			if latin != "" {
+				table = append(table, romanToken{latin: norm.NFC.String(latin), kind: kind, key: key})
+			}
+		}
+	}
+	add(tokenConsonant, scheme.consonants)
+	add(tokenVowel, scheme.vowels)
+	for key, latin := range scheme.signs {
+		if key == "om" { // Read back as its letters (ओं), not as the ॐ ligature.
+			continue
+		}
+		table = append(table, romanToken{latin: latin, kind: tokenSign, key: key})
+	}
+	// Accept the hiatus marks and the spellings commonly typed for the other scheme.
+	table = append(table,
+		romanToken{latin: "ï", kind: tokenVowel, key: "i"},
+		romanToken{latin: "ü", kind: tokenVowel, key: "u"},
+	)
+	if iso {
+		table = append(table,
+			romanToken{latin: "e", kind: tokenVowel, key: "e"},
+			romanToken{latin: "o", kind: tokenVowel, key: "o"},
+			romanToken{latin: "ṃ", kind: tokenSign, key: "anusvara"},
+		)
+	} else {
+		table = append(table, romanToken{latin: "ṁ", kind: tokenSign, key: "anusvara"})
+	}
+	sort.SliceStable(table, func(i, j int) bool {
+		if li, lj := len([]rune(table[i].latin)), len([]rune(table[j].latin)); li != lj {
+			return li > lj
+		}
+		return table[i].latin < table[j].latin
+	})
+	return table
+}
+
+// matchRomanToken returns the longest table entry that prefixes `input`, and its length in runes.
+func matchRomanToken(input []rune, table []romanToken) (romanToken, int) {
+	if len(input) == 0 || !(unicode.IsLetter(input[0]) || unicode.IsMark(input[0]) || input[0] == '\'' || input[0] == '|') {
+		return romanToken{}, 0
+	}
+	for _, tok := range table {
+		latin := []rune(tok.latin)
+		if len(latin) > len(input) {
+			continue
+		}
+		if string(input[:len(latin)]) == tok.latin {
+			// Do not split a base letter from a combining mark that belongs to it (e.g., "r" of "r̥").
+			if len(latin) < len(input) && unicode.IsMark(input[len(latin)]) && !unicode.IsMark(latin[len(latin)-1]) {
+				continue
+			}
+			return tok, len(latin)
+		}
+	}
+	return romanToken{}, 0
+}
+
+// invertRuneMap inverts a rune → key table. For keys shared by several runes, the lowest rune wins.
+// Precomposed nukta letters are emitted as-is; the caller NFC-normalizes the result.
+func invertRuneMap(m map[rune]string) map[string]rune {
+	inv := make(map[string]rune, len(m))
+	for r, key := range m {
+		if existing, ok := inv[key]; !ok || r < existing {
+			inv[key] = r
+		}
+	}
+	return inv
+}
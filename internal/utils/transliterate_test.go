@@ -0,0 +1,93 @@
+package utils
+
+import "testing"
+
+// transliterationCorpus holds Nepali sentences of the kind found in legal documents, plus the
+// spellings that need the hiatus and cluster marks to survive a round trip.
+var transliterationCorpus = []string{
+	"नेपालको संविधान २०७२",
+	"नेपाल सरकार।",
+	"दफा ५ को उपदफा (२) बमोजिम प्रदेश सरकारले कानून बनाउनेछ।",
+	"सार्वभौमसत्ता र राजकीय सत्ता नेपाली जनतामा निहित रहेको छ॥",
+	"प्रत्येक नागरिकलाई कानूनबमोजिम सम्पत्ति आर्जन गर्ने हक हुनेछ।",
+	"कृषि, उद्योग र वाणिज्य मन्त्रालय",
+	"श्री ५ को सरकार, काठमाडौं महानगरपालिका",
+	"अख्तियार दुरुपयोग अनुसन्धान आयोग",
+	"ज्ञान, क्षमता र त्रुटि",
+	"संयुक्त अधिवेशनमा शान्तिः",
+	"गइन्, भइरहेको, कइ, अइ, अउ",
+	"क्ह, द्ह, ब्ह",
+}
+
+func TestTransliterateRoundTrip(t *testing.T) {
+	for _, scheme := range []string{SchemeIAST, SchemeISO15919} {
+		for _, text := range transliterationCorpus {
+			roman, err := Transliterate(text, SchemeDevanagari, scheme)
+			if err != nil {
+				t.Fatalf("Transliterate(%q, devanagari, %s): %v", text, scheme, err)
+			}
+			back, err := Transliterate(roman, scheme, SchemeDevanagari)
+			if err != nil {
+				t.Fatalf("Transliterate(%q, %s, devanagari): %v", roman, scheme, err)
+			}
+			if back != text {
+				t.Errorf("%s round trip of %q: got %q via %q", scheme, text, back, roman)
+			}
+		}
+	}
+}
+
+func TestTransliterateRoman(t *testing.T) {
+	tests := []struct {
+		text, scheme, want string
+	}{
+		{"नेपालको संविधान", SchemeIAST, "nepālako saṃvidhāna"},
+		{"नेपालको संविधान", SchemeISO15919, "nēpālakō saṁvidhāna"},
+		{"कइ", SchemeIAST, "kaï"},
+		{"अइ", SchemeIAST, "aï"},
+		{"अउ", SchemeISO15919, "aü"},
+		{"कै", SchemeIAST, "kai"},
+		{"क्ह", SchemeIAST, "k:ha"},
+		{"क्ह", SchemeISO15919, "k:ha"},
+		{"ख", SchemeISO15919, "kha"},
+		{"क्क", SchemeISO15919, "kka"},
+		{"कृषि", SchemeISO15919, "kr̥ṣi"},
+		{"२०७२।", SchemeIAST, "2072|"},
+	}
+	for _, tt := range tests {
+		if got, err := Transliterate(tt.text, SchemeDevanagari, tt.scheme); err != nil || got != tt.want {
+			t.Errorf("Transliterate(%q, devanagari, %s) = %q, %v; want %q", tt.text, tt.scheme, got, err, tt.want)
+		}
+	}
+}
+
+func TestTransliterateNepali(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"नेपाल सरकार।", "nepal sarkar."},
+		{"नेपालको संविधान", "nepalko sanvidhan"},
+		{"उपदफा", "upadapha"},
+		{"अवसर", "avasar"},
+		{"नगरको", "nagarko"},
+		{"काठमाडौं महानगरपालिका", "kathmadaun mahanagarpalika"},
+		{"कमल", "kamal"},
+		{"ज्ञान", "gyan"},
+		{"सम्पत्ति", "sampatti"},
+		{"दफा ५ को उपदफा (२)", "dapha 5 ko upadapha (2)"},
+	}
+	for _, tt := range tests {
+		if got, err := Transliterate(tt.text, SchemeDevanagari, SchemeNepali); err != nil || got != tt.want {
+			t.Errorf("Transliterate(%q, devanagari, nepali) = %q, %v; want %q", tt.text, got, err, tt.want)
+		}
+	}
+}
+
+func TestTransliterateUnsupported(t *testing.T) {
+	if _, err := Transliterate("nepal", SchemeNepali, SchemeDevanagari); err == nil {
+		t.Error("converting the lossy Nepali romanization back should fail")
+	}
+	if _, err := Transliterate("नेपाल", SchemeDevanagari, "hk"); err == nil {
+		t.Error("an unknown scheme should fail")
+	}
+}
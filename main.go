@@ -81,6 +81,10 @@ func main() {
 	// like automatic OpenAPI 3.0 documentation generation and request/response validation.
 	apiConfig := huma.DefaultConfig("Niyam API", "1.0.0")
 	apiConfig.Info.Description = "API/Backend service for the Niyam application."
+	// Document the JWT bearer tokens returned by signup/login; protected operations reference this scheme.
+	apiConfig.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		handler.BearerAuthScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+	}
 
 	api := humachi.New(router, apiConfig)
 
@@ -129,12 +133,16 @@ func main() {
 
 	// Configure the HTTP server with explicit timeouts for read, write, and idle operations.
 	// This helps prevent resource exhaustion and improves server robustness.
+	// The largest allowed upload (a multi-page scan) must be readable at 256 KiB/s; the write deadline runs
+	// from the end of the headers, so it covers reading the body as well as the longest OCR scan.
+	readTimeout := 5*time.Second + time.Duration(cfg.MaxScanUploadBytes/(256<<10))*time.Second
 	srv := &http.Server{
-		Addr:         listener.Addr().String(), // Server address determined by listener
-		Handler:      router,                   // The Chi router handles all incoming requests
-		ReadTimeout:  5 * time.Second,          // Maximum duration for reading the entire request
-		WriteTimeout: 10 * time.Second,         // Maximum duration before timing out writes of the response
-		IdleTimeout:  120 * time.Second,        // Maximum amount of time to wait for the next request when keep-alives are enabled
+		Addr:              listener.Addr().String(),                      // Server address determined by listener
+		Handler:           router,                                        // The Chi router handles all incoming requests
+		ReadHeaderTimeout: 5 * time.Second,                               // Maximum duration for reading the request headers
+		ReadTimeout:       readTimeout,                                   // Maximum duration for reading the entire request, including uploads
+		WriteTimeout:      readTimeout + 10*time.Second + cfg.OCRTimeout, // Maximum duration before timing out writes; leaves room for the upload and the longest OCR scan
+		IdleTimeout:       120 * time.Second,                             // Maximum amount of time to wait for the next request when keep-alives are enabled
 	}
 
 	// Start the HTTP server in a separate goroutine.